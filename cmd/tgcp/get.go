@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/cli"
+	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/utils"
+)
+
+// getTimeout bounds a headless listing, retries included
+const getTimeout = 5 * time.Minute
+
+// runGet implements "tgcp get <service> [resource]" and returns the exit code
+func runGet(args []string) int {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	project := fs.String("project", "", "Override Google Cloud project ID")
+	profile := fs.String("profile", "", "Use a named profile from ~/.tgcprc")
+	region := fs.String("region", "", "Region for regional APIs (default: config region)")
+	replay := fs.String("replay", "", "Serve all API traffic from a cassette file (offline)")
+	impersonateSA := fs.String("impersonate-service-account", "", "Make all API calls as this service account")
+	var output string
+	fs.StringVar(&output, "o", cli.OutputTable, "Output format: table, json, yaml or csv")
+	fs.StringVar(&output, "output", cli.OutputTable, "Output format: table, json, yaml or csv")
+
+	// Allow flags before, between and after the positional arguments
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				fmt.Fprint(os.Stdout, cli.Usage())
+				return 0
+			}
+			fmt.Fprintf(os.Stderr, "tgcp get: %v\n\n%s", err, cli.Usage())
+			return 2
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) == 0 || len(positional) > 2 {
+		fmt.Fprint(os.Stderr, cli.Usage())
+		return 2
+	}
+	if err := cli.ValidateOutput(output); err != nil {
+		fmt.Fprintf(os.Stderr, "tgcp get: %v\n", err)
+		return 2
+	}
+
+	if *debug {
+		if err := utils.InitLogger(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to init logger: %v\n", err)
+			return 1
+		}
+		defer utils.CloseLogger()
+	}
+
+	cfg, err := loadConfig(*profile, false, *impersonateSA, *debug)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load profile: %v\n", err)
+		return 1
+	}
+	configureCore(cfg, *replay != "")
+
+	// Project Priority: Flag > Config > Auto-detect
+	targetProject := *project
+	if targetProject == "" {
+		targetProject = cfg.Project
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), getTimeout)
+	defer cancel()
+
+	var authState core.AuthState
+	if *replay != "" {
+		if _, err := core.StartReplay(*replay); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load cassette: %v\n", err)
+			return 1
+		}
+		// The cassette's project, not the config's, unless --project says otherwise
+		authState = core.ReplayAuthState(*project)
+	} else {
+		authState = core.Authenticate(ctx, targetProject)
+		if authState.Error != nil {
+			fmt.Fprintf(os.Stderr, "Authentication failed: %v\n", authState.Error)
+			return 1
+		}
+	}
+
+	opts := cli.GetOptions{
+		Service: positional[0],
+		Output:  output,
+		Scope: cli.Scope{
+			Project: authState.ProjectID,
+			Region:  *region,
+		},
+	}
+	if len(positional) == 2 {
+		opts.Resource = positional[1]
+	}
+	if opts.Scope.Region == "" {
+		opts.Scope.Region = cfg.Region
+	}
+	opts.Scope.Zone = cfg.Zone
+
+	if err := cli.Get(ctx, opts, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "tgcp get: %v\n", err)
+		return 1
+	}
+	return 0
+}
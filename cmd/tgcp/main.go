@@ -20,10 +20,22 @@ var (
 )
 
 func main() {
+	// Headless subcommands never start the TUI
+	if len(os.Args) > 1 && os.Args[1] == "get" {
+		os.Exit(runGet(os.Args[2:]))
+	}
+
 	// 1. Parse Flags
 	debug := flag.Bool("debug", false, "Enable debug logging")
 	project := flag.String("project", "", "Override Google Cloud project ID")
+	profile := flag.String("profile", "", "Use a named profile from ~/.tgcprc")
 	showVersion := flag.Bool("version", false, "Show version information")
+	record := flag.String("record", "", "Record all API traffic to a cassette file")
+	replay := flag.String("replay", "", "Serve all API traffic from a cassette file (offline)")
+	noCache := flag.Bool("no-cache", false, "Disable the persistent on-disk cache")
+	readOnly := flag.Bool("read-only", false, "Disable every mutating action (start, stop, snapshot, ...)")
+	open := flag.String("open", "", "Open a deep link (e.g. gce/instances/my-vm) or bookmark at startup")
+	impersonateSA := flag.String("impersonate-service-account", "", "Make all API calls as this service account (comma-separated for a delegation chain)")
 	flag.Parse()
 
 	if *showVersion {
@@ -31,6 +43,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *record != "" && *replay != "" {
+		fmt.Println("--record and --replay cannot be used together")
+		os.Exit(1)
+	}
+
 	// 2. Initialize Logger
 	if *debug {
 		if err := utils.InitLogger(); err != nil {
@@ -42,11 +59,19 @@ func main() {
 	}
 
 	// 3. Load Configuration
-	cfg, err := config.LoadConfig()
-	if err != nil && *debug {
-		utils.Log("Error loading config: %v", err)
+	cfg, err := loadConfig(*profile, *readOnly, *impersonateSA, *debug)
+	if err != nil {
+		fmt.Printf("Failed to load profile: %v\n", err)
+		os.Exit(1)
 	}
 
+	// Replays must only ever show cassette data
+	if *noCache || *replay != "" {
+		cfg.Cache.Disk = false
+	}
+
+	configureCore(cfg, *replay != "")
+
 	// 4. Authenticate
 	// Project Priority: Flag > Config > Auto-detect
 	targetProject := *project
@@ -54,8 +79,29 @@ func main() {
 		targetProject = cfg.Project
 	}
 
-	// We do this synchronously for now for the MVP Foundation
-	authState := core.Authenticate(context.Background(), targetProject)
+	// Cassette mode must be set up before any HTTP client is created
+	var authState core.AuthState
+	if *replay != "" {
+		if _, err := core.StartReplay(*replay); err != nil {
+			fmt.Printf("Failed to load cassette: %v\n", err)
+			os.Exit(1)
+		}
+		authState = core.ReplayAuthState(*project)
+	} else {
+		if *record != "" {
+			core.StartRecording(*record)
+			defer func() {
+				if err := core.SaveCassette(); err != nil {
+					fmt.Printf("Failed to save cassette: %v\n", err)
+				}
+			}()
+		}
+
+		// We do this synchronously for now for the MVP Foundation
+		authState = core.Authenticate(context.Background(), targetProject)
+		core.SetCassetteIdentity(authState.ProjectID, authState.EffectiveIdentity())
+		core.SetAuditIdentity(authState.UserEmail, authState.ImpersonatedAccount)
+	}
 
 	// 5. Create Version Info
 	versionInfo := core.VersionInfo{
@@ -66,13 +112,58 @@ func main() {
 
 	// 6. Initialize UI Model
 	initialModel := ui.InitialModel(authState, cfg, versionInfo)
+	initialModel.OpenOnStart(*open)
 
 	// 7. Start Bubbletea Program
 	// WithMouseCellMotion enables mouse click support
 	// Users can hold Shift to select text (standard terminal behavior)
 	p := tea.NewProgram(initialModel, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
+
+	// Exports queued with "print on exit" go to stdout after the alt screen closes
+	if m, ok := final.(ui.MainModel); ok {
+		fmt.Print(m.ExitOutput())
+	}
+}
+
+// loadConfig reads ~/.tgcprc and applies the selected profile.
+// Profile Priority: Flag > default_profile. Explicit flags still win over profile values.
+func loadConfig(profile string, readOnly bool, impersonateSA string, debug bool) (*config.Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil && debug {
+		utils.Log("Error loading config: %v", err)
+	}
+
+	// Set before applying a profile so switching profiles keeps them
+	if readOnly {
+		cfg.ReadOnly = true
+	}
+	cfg.SetImpersonationFlag(impersonateSA)
+
+	if profile == "" {
+		profile = cfg.DefaultProfile
+	}
+	if profile != "" {
+		return cfg.WithProfile(profile)
+	}
+	return cfg, nil
+}
+
+// configureCore installs the process-wide API settings from the config.
+// Endpoint overrides (emulators, private endpoints) must be in place
+// before any service client is created.
+func configureCore(cfg *config.Config, replaying bool) {
+	core.SetEndpoints(cfg.Endpoints)
+	core.SetRetryPolicy(cfg.Retry)
+	core.SetRateLimits(cfg.RateLimits)
+	core.SetGuardrails(cfg)
+
+	// Impersonation Priority: Flag > Profile > Config (see loadConfig)
+	if !replaying {
+		core.SetImpersonation(cfg.ImpersonateServiceAccount)
+	}
 }
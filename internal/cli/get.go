@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Headless "tgcp get"
+//
+// Lists resources with the same service clients and models the TUI uses,
+// without starting Bubble Tea, so tgcp can be used from scripts and CI:
+//
+//	tgcp get gce instances -o json
+//	tgcp get sql -o csv --project my-project
+
+// GetOptions configures a single "tgcp get" invocation
+type GetOptions struct {
+	Service  string // Service short name, e.g. "gce"
+	Resource string // Resource type, empty for the service's default
+	Output   string // One of OutputFormats
+	Scope    Scope
+}
+
+// Get fetches one listing and writes it to w
+func Get(ctx context.Context, opts GetOptions, w io.Writer) error {
+	if opts.Service == "" {
+		return fmt.Errorf("missing service (available: %s)", strings.Join(serviceNames(), ", "))
+	}
+	if err := ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+	if opts.Scope.Project == "" {
+		return fmt.Errorf("no project set: pass --project or set one in ~/.tgcprc")
+	}
+
+	r, err := lookupResource(strings.ToLower(opts.Service), strings.ToLower(opts.Resource))
+	if err != nil {
+		return err
+	}
+
+	res, err := r.fetch(ctx, opts.Scope)
+	if err != nil {
+		return fmt.Errorf("listing %s %s: %w", r.Service, r.Name, err)
+	}
+	return writeResult(w, opts.Output, res)
+}
+
+// Usage describes the services and resource types "tgcp get" supports
+func Usage() string {
+	var b strings.Builder
+	b.WriteString("Usage: tgcp get <service> [resource] [-o table|json|yaml|csv] [--project ID]\n\nResources:\n")
+	for _, svc := range serviceNames() {
+		var names []string
+		for _, r := range resources {
+			if r.Service == svc {
+				names = append(names, r.Name)
+			}
+		}
+		fmt.Fprintf(&b, "  %-10s %s\n", svc, strings.Join(names, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/yogirk/tgcp/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats supported by "tgcp get -o"
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+)
+
+// OutputFormats lists the valid values of -o, default first
+var OutputFormats = []string{OutputTable, OutputJSON, OutputYAML, OutputCSV}
+
+// ValidateOutput checks a value of -o, so that a typo is reported before
+// any API call is made
+func ValidateOutput(format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, f := range OutputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q (available: %s)", format, strings.Join(OutputFormats, ", "))
+}
+
+// writeResult renders a listing in the requested format
+func writeResult(w io.Writer, format string, res Result) error {
+	switch format {
+	case OutputTable, "":
+		return writeTable(w, res)
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(utils.Normalize(res.Items))
+	case OutputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(utils.Normalize(res.Items)); err != nil {
+			return err
+		}
+		return enc.Close()
+	case OutputCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(res.Columns); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(res.Rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		return ValidateOutput(format)
+	}
+}
+
+// writeTable prints aligned columns, like kubectl and gcloud do
+func writeTable(w io.Writer, res Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(res.Columns, "\t"))
+	for _, row := range res.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if cell == "" {
+				cell = "-"
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Name       string
+	State      string
+	InternalIP string `json:"internalIp"` // Tags don't change the schema
+	Labels     map[string]string
+}
+
+func testResult() Result {
+	return Result{
+		Items:   []testItem{{Name: "web-1", State: "RUNNING", InternalIP: "10.0.0.2", Labels: map[string]string{"TeamName": "web"}}, {Name: "db, primary", State: ""}},
+		Columns: []string{"NAME", "STATE"},
+		Rows:    [][]string{{"web-1", "RUNNING"}, {"db, primary", ""}},
+	}
+}
+
+func TestWriteResultFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{OutputTable, []string{"NAME          STATE", "web-1         RUNNING", "db, primary   -"}},
+		{OutputJSON, []string{`"name": "web-1"`, `"state": "RUNNING"`, `"internal_ip": "10.0.0.2"`, `"TeamName": "web"`}},
+		{OutputYAML, []string{"- name: web-1", "  state: RUNNING", "  internal_ip: 10.0.0.2", "    TeamName: web"}},
+		{OutputCSV, []string{"NAME,STATE", "web-1,RUNNING", `"db, primary",`}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeResult(&buf, tt.format, testResult()); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.format, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s output missing %q:\n%s", tt.format, want, buf.String())
+			}
+		}
+	}
+}
+
+func TestWriteResultUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeResult(&buf, "xml", testResult()); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestListWithContextHonorsDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	hang := func(ctx context.Context, scope Scope) ([]testItem, error) {
+		time.Sleep(time.Second)
+		return nil, nil
+	}
+	if _, err := listWithContext(ctx, Scope{}, hang); err != context.DeadlineExceeded {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLookupResource(t *testing.T) {
+	r, err := lookupResource("pubsub", "")
+	if err != nil || r.Name != "topics" {
+		t.Fatalf("default pubsub resource = %q, %v; want topics", r.Name, err)
+	}
+	r, err = lookupResource("pubsub", "subscription")
+	if err != nil || r.Name != "subscriptions" {
+		t.Fatalf("singular lookup = %q, %v; want subscriptions", r.Name, err)
+	}
+	if _, err := lookupResource("gce", "widgets"); err == nil || !strings.Contains(err.Error(), "instances") {
+		t.Errorf("unknown resource error should list valid names, got %v", err)
+	}
+	if _, err := lookupResource("nope", ""); err == nil {
+		t.Error("expected error for unknown service")
+	}
+}
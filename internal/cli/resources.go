@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/services/bigquery"
+	"github.com/yogirk/tgcp/internal/services/bigtable"
+	"github.com/yogirk/tgcp/internal/services/cloudrun"
+	"github.com/yogirk/tgcp/internal/services/cloudsql"
+	"github.com/yogirk/tgcp/internal/services/dataflow"
+	"github.com/yogirk/tgcp/internal/services/dataproc"
+	"github.com/yogirk/tgcp/internal/services/disks"
+	"github.com/yogirk/tgcp/internal/services/firestore"
+	"github.com/yogirk/tgcp/internal/services/gce"
+	"github.com/yogirk/tgcp/internal/services/gcs"
+	"github.com/yogirk/tgcp/internal/services/gke"
+	"github.com/yogirk/tgcp/internal/services/iam"
+	"github.com/yogirk/tgcp/internal/services/net"
+	"github.com/yogirk/tgcp/internal/services/pubsub"
+	"github.com/yogirk/tgcp/internal/services/redis"
+	"github.com/yogirk/tgcp/internal/services/secrets"
+	"github.com/yogirk/tgcp/internal/services/spanner"
+)
+
+// Scope is what a listing runs against
+type Scope struct {
+	Project string
+	Region  string // Only used by regional APIs such as Dataproc
+	Zone    string // Default zone, whose region is used when Region is empty
+}
+
+// Result is a fetched listing: the models themselves for JSON/YAML and
+// their rendered cells for table/CSV output
+type Result struct {
+	Items   interface{}
+	Columns []string
+	Rows    [][]string
+}
+
+// resource describes one listable resource type of a service
+type resource struct {
+	Service string // Service short name, as in the TUI palette
+	Name    string // Resource type, e.g. "instances"
+	fetch   func(ctx context.Context, scope Scope) (Result, error)
+}
+
+// newResource adapts a service client's List* call and a row renderer
+func newResource[T any](service, name string, columns []string, list func(ctx context.Context, scope Scope) ([]T, error), row func(T) []string) resource {
+	return resource{
+		Service: service,
+		Name:    name,
+		fetch: func(ctx context.Context, scope Scope) (Result, error) {
+			items, err := listWithContext(ctx, scope, list)
+			if err != nil {
+				return Result{}, err
+			}
+			if items == nil {
+				items = []T{} // Render "[]" rather than "null"
+			}
+			rows := make([][]string, len(items))
+			for i, item := range items {
+				rows[i] = row(item)
+			}
+			return Result{Items: items, Columns: columns, Rows: rows}, nil
+		},
+	}
+}
+
+// listWithContext runs list but gives up once ctx is done. The services'
+// List* calls don't take a context, so without this a hung API would
+// outlive the caller's deadline; the abandoned call ends with the process.
+func listWithContext[T any](ctx context.Context, scope Scope, list func(ctx context.Context, scope Scope) ([]T, error)) ([]T, error) {
+	type listed struct {
+		items []T
+		err   error
+	}
+	done := make(chan listed, 1)
+	go func() {
+		items, err := list(ctx, scope)
+		done <- listed{items, err}
+	}()
+	select {
+	case r := <-done:
+		return r.items, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resources lists every resource type "tgcp get" knows. The first entry of
+// a service is its default when no resource type is given.
+var resources = []resource{
+	newResource("gce", "instances",
+		[]string{"NAME", "STATUS", "ZONE", "MACHINE_TYPE", "INTERNAL_IP", "EXTERNAL_IP", "ID"},
+		func(ctx context.Context, scope Scope) ([]gce.Instance, error) {
+			c, err := gce.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListInstances(scope.Project)
+		},
+		func(i gce.Instance) []string {
+			return []string{i.Name, string(i.State), i.Zone, i.MachineType, i.InternalIP, i.ExternalIP, i.ID}
+		}),
+
+	newResource("disks", "disks",
+		[]string{"NAME", "ZONE", "SIZE_GB", "TYPE", "STATUS", "ATTACHED_TO"},
+		func(ctx context.Context, scope Scope) ([]disks.Disk, error) {
+			c, err := disks.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListDisks(scope.Project)
+		},
+		func(d disks.Disk) []string {
+			users := make([]string, len(d.Users))
+			for i, u := range d.Users {
+				users[i] = lastSegment(u)
+			}
+			return []string{d.Name, d.Zone, strconv.FormatInt(d.SizeGb, 10), d.ShortType(), d.Status, strings.Join(users, ",")}
+		}),
+
+	newResource("gke", "clusters",
+		[]string{"NAME", "LOCATION", "STATUS", "VERSION", "NODES", "MODE"},
+		func(ctx context.Context, scope Scope) ([]gke.Cluster, error) {
+			c, err := gke.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListClusters(scope.Project)
+		},
+		func(c gke.Cluster) []string {
+			return []string{c.Name, c.Location, c.Status, c.MasterVersion, strconv.Itoa(c.NodeCount), c.Mode}
+		}),
+
+	newResource("run", "services",
+		[]string{"NAME", "REGION", "STATUS", "URL", "LAST_MODIFIED"},
+		func(ctx context.Context, scope Scope) ([]cloudrun.RunService, error) {
+			c, err := cloudrun.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListServices(scope.Project)
+		},
+		func(s cloudrun.RunService) []string {
+			return []string{s.Name, s.Region, string(s.Status), s.URL, formatTime(s.LastModified)}
+		}),
+
+	newResource("run", "functions",
+		[]string{"NAME", "REGION", "STATE", "ENVIRONMENT", "URL", "LAST_UPDATED"},
+		func(ctx context.Context, scope Scope) ([]cloudrun.Function, error) {
+			c, err := cloudrun.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListFunctions(scope.Project)
+		},
+		func(f cloudrun.Function) []string {
+			return []string{f.Name, f.Region, f.State, f.Environment, f.URL, formatTime(f.LastUpdated)}
+		}),
+
+	newResource("sql", "instances",
+		[]string{"NAME", "STATUS", "VERSION", "REGION", "PRIMARY_IP", "TIER"},
+		func(ctx context.Context, scope Scope) ([]cloudsql.Instance, error) {
+			c, err := cloudsql.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListInstances(scope.Project)
+		},
+		func(i cloudsql.Instance) []string {
+			return []string{i.Name, string(i.State), i.DatabaseVersion, i.Region, i.PrimaryIP, i.Tier}
+		}),
+
+	newResource("gcs", "buckets",
+		[]string{"NAME", "LOCATION", "STORAGE_CLASS", "CREATED"},
+		func(ctx context.Context, scope Scope) ([]gcs.Bucket, error) {
+			c, err := gcs.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListBuckets(scope.Project)
+		},
+		func(b gcs.Bucket) []string {
+			return []string{b.Name, b.Location, b.StorageClass, formatTime(b.Created)}
+		}),
+
+	newResource("bq", "datasets",
+		[]string{"ID", "PROJECT", "LOCATION"},
+		func(ctx context.Context, scope Scope) ([]bigquery.Dataset, error) {
+			c, err := bigquery.NewClient(ctx, scope.Project)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListDatasets(scope.Project)
+		},
+		func(d bigquery.Dataset) []string {
+			return []string{d.ID, d.ProjectID, d.Location}
+		}),
+
+	newResource("redis", "instances",
+		[]string{"NAME", "LOCATION", "TIER", "MEMORY_GB", "VERSION", "HOST", "STATE"},
+		func(ctx context.Context, scope Scope) ([]redis.Instance, error) {
+			c, err := redis.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListInstances(scope.Project)
+		},
+		func(i redis.Instance) []string {
+			return []string{i.Name, i.Location, i.Tier, strconv.Itoa(i.MemorySizeGb), i.RedisVersion, i.Host, i.State}
+		}),
+
+	newResource("spanner", "instances",
+		[]string{"NAME", "CONFIG", "STATE", "NODES", "PROCESSING_UNITS"},
+		func(ctx context.Context, scope Scope) ([]spanner.Instance, error) {
+			c, err := spanner.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListInstances(scope.Project)
+		},
+		func(i spanner.Instance) []string {
+			return []string{i.Name, i.Config, i.State, strconv.Itoa(i.NodeCount), strconv.Itoa(i.ProcessingUnits)}
+		}),
+
+	newResource("bigtable", "instances",
+		[]string{"NAME", "DISPLAY_NAME", "TYPE", "STATE"},
+		func(ctx context.Context, scope Scope) ([]bigtable.Instance, error) {
+			c, err := bigtable.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListInstances(scope.Project)
+		},
+		func(i bigtable.Instance) []string {
+			return []string{i.Name, i.DisplayName, i.Type, i.State}
+		}),
+
+	newResource("firestore", "databases",
+		[]string{"NAME", "LOCATION", "TYPE", "STATE"},
+		func(ctx context.Context, scope Scope) ([]firestore.Database, error) {
+			c, err := firestore.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListDatabases(scope.Project)
+		},
+		func(d firestore.Database) []string {
+			return []string{d.Name, d.Location, d.Type, d.State}
+		}),
+
+	newResource("pubsub", "topics",
+		[]string{"NAME", "KMS_KEY"},
+		func(ctx context.Context, scope Scope) ([]pubsub.Topic, error) {
+			c, err := pubsub.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListTopics(scope.Project)
+		},
+		func(t pubsub.Topic) []string {
+			return []string{t.Name, t.KmsKeyName}
+		}),
+
+	newResource("pubsub", "subscriptions",
+		[]string{"NAME", "TOPIC", "STATE", "ACK_DEADLINE", "PUSH_ENDPOINT", "DEAD_LETTER_TOPIC"},
+		func(ctx context.Context, scope Scope) ([]pubsub.Subscription, error) {
+			c, err := pubsub.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListSubscriptions(scope.Project)
+		},
+		func(s pubsub.Subscription) []string {
+			return []string{s.Name, s.Topic, s.State, strconv.Itoa(s.AckDeadline), s.PushEndpoint, s.DeadLetterTopic}
+		}),
+
+	newResource("dataflow", "jobs",
+		[]string{"ID", "NAME", "TYPE", "STATE", "LOCATION", "CREATED"},
+		func(ctx context.Context, scope Scope) ([]dataflow.Job, error) {
+			c, err := dataflow.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListJobs(scope.Project)
+		},
+		func(j dataflow.Job) []string {
+			return []string{j.ID, j.Name, j.Type, j.State, j.Location, j.CreateTime}
+		}),
+
+	newResource("dataproc", "clusters",
+		[]string{"NAME", "STATUS", "ZONE", "MASTER_MACHINE", "WORKERS", "WORKER_MACHINE"},
+		func(ctx context.Context, scope Scope) ([]dataproc.Cluster, error) {
+			c, err := dataproc.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListClusters(scope.Project, dataproc.RegionFor(scope.Region, scope.Zone))
+		},
+		func(c dataproc.Cluster) []string {
+			return []string{c.Name, c.Status, c.Zone, c.MasterMachine, strconv.Itoa(c.WorkerCount), c.WorkerMachine}
+		}),
+
+	newResource("iam", "serviceaccounts",
+		[]string{"EMAIL", "DISPLAY_NAME", "DISABLED"},
+		func(ctx context.Context, scope Scope) ([]iam.ServiceAccount, error) {
+			c, err := iam.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListServiceAccounts(scope.Project)
+		},
+		func(sa iam.ServiceAccount) []string {
+			return []string{sa.Email, sa.DisplayName, strconv.FormatBool(sa.Disabled)}
+		}),
+
+	newResource("net", "networks",
+		[]string{"NAME", "MODE", "IPV4_RANGE", "GATEWAY"},
+		func(ctx context.Context, scope Scope) ([]net.Network, error) {
+			c, err := net.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListNetworks(scope.Project)
+		},
+		func(n net.Network) []string {
+			return []string{n.Name, n.Mode, n.IPv4Range, n.GatewayIPv4}
+		}),
+
+	newResource("secrets", "secrets",
+		[]string{"NAME", "VERSIONS", "REPLICATION", "CREATED"},
+		func(ctx context.Context, scope Scope) ([]secrets.Secret, error) {
+			c, err := secrets.NewClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListSecrets(scope.Project)
+		},
+		func(s secrets.Secret) []string {
+			return []string{s.Name, strconv.Itoa(s.VersionCount), s.Replication, formatTime(s.CreateTime)}
+		}),
+}
+
+// lookupResource finds a resource type. An empty name selects the
+// service's default resource; singular names ("instance") are accepted.
+func lookupResource(service, name string) (resource, error) {
+	var known []string
+	for _, r := range resources {
+		if r.Service != service {
+			continue
+		}
+		if name == "" || name == r.Name || name+"s" == r.Name {
+			return r, nil
+		}
+		known = append(known, r.Name)
+	}
+	if len(known) == 0 {
+		return resource{}, fmt.Errorf("unknown service %q (available: %s)", service, strings.Join(serviceNames(), ", "))
+	}
+	return resource{}, fmt.Errorf("unknown %s resource %q (available: %s)", service, name, strings.Join(known, ", "))
+}
+
+// serviceNames returns the services "tgcp get" supports, in registry order
+func serviceNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, r := range resources {
+		if !seen[r.Service] {
+			seen[r.Service] = true
+			names = append(names, r.Service)
+		}
+	}
+	return names
+}
+
+func lastSegment(s string) string {
+	return s[strings.LastIndex(s, "/")+1:]
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,12 +17,199 @@ type Config struct {
 	Zone     string         `yaml:"zone"`
 	UI       UIConfig       `yaml:"ui"`
 	Features FeaturesConfig `yaml:"features"`
+	Cache    CacheConfig    `yaml:"cache"`
+	Retry    RetryConfig    `yaml:"retry"`
+
+	// RateLimits sets requests per second per API host
+	// (e.g. "logging.googleapis.com") or "default" for all other hosts.
+	RateLimits map[string]RateLimitConfig `yaml:"rate_limits"`
+
+	// Endpoints overrides the API endpoint per service short name
+	// (e.g. "pubsub", "gcs"), typically to target a local emulator.
+	Endpoints map[string]EndpointConfig `yaml:"endpoints"`
+
+	// ImpersonateServiceAccount makes all API calls as this service account
+	ImpersonateServiceAccount string `yaml:"impersonate_service_account"`
+
+	// ReadOnly disables every mutating action (also set by --read-only)
+	ReadOnly bool `yaml:"read_only"`
+
+	// ReadOnlyProjects are always read-only, whatever the profile
+	ReadOnlyProjects []string `yaml:"read_only_projects"`
+
+	// ProtectedProjects require typing the resource name to confirm an action
+	ProtectedProjects []string `yaml:"protected_projects"`
+
+	// Search sets what the resource search covers beyond the current project
+	Search SearchConfig `yaml:"search"`
+
+	// Tables holds the column layout and sort of each table, keyed by table
+	// name (e.g. "gce-instances"). tgcp writes it when a layout changes.
+	Tables map[string]TableLayout `yaml:"tables"`
+
+	// Keys rebinds actions by name, e.g. "ssh: [S]" (see the README)
+	Keys map[string]KeyList `yaml:"keys"`
+
+	// Bookmarks name deep links (e.g. "sql/orders-db") to open with
+	// --open <name> or from the command palette
+	Bookmarks map[string]string `yaml:"bookmarks"`
+
+	// Themes defines custom color themes by name, selected with ui.theme.
+	// Themes can also be kept in ~/.tgcp/themes/<name>.yaml.
+	Themes map[string]ThemeConfig `yaml:"themes"`
+
+	// Profiles are named environments selectable with --profile or the palette
+	Profiles       map[string]ProfileConfig `yaml:"profiles"`
+	DefaultProfile string                   `yaml:"default_profile"`
+
+	// ActiveProfile is the profile applied by WithProfile, if any
+	ActiveProfile string `yaml:"-"`
+
+	base *Config // Config as loaded, before any profile was applied
+
+	// impersonationPinned is set by SetImpersonationFlag; profiles then
+	// keep the flag's account
+	impersonationPinned bool
+}
+
+// ProfileConfig bundles the settings of one environment. Empty fields
+// fall back to the top-level values.
+type ProfileConfig struct {
+	Project                   string `yaml:"project"`
+	Region                    string `yaml:"region"`
+	Zone                      string `yaml:"zone"`
+	ImpersonateServiceAccount string `yaml:"impersonate_service_account"`
+	ReadOnly                  bool   `yaml:"read_only"`
+	Protected                 bool   `yaml:"protected"` // Confirm actions by typing the resource name
+	Accent                    string `yaml:"accent"`    // lipgloss color, e.g. "196" or "#ff5f00"
 }
 
 type UIConfig struct {
-	SidebarVisible  bool   `yaml:"sidebar_visible"`
-	RefreshInterval int    `yaml:"refresh_interval"`
-	DefaultView     string `yaml:"default_view"`
+	SidebarVisible   bool           `yaml:"sidebar_visible"`
+	RefreshInterval  *int           `yaml:"refresh_interval"`  // Seconds between background refreshes; 0 turns them off. Unset, each service refreshes as often as its cache expires
+	RefreshIntervals map[string]int `yaml:"refresh_intervals"` // Per service short name, overriding refresh_interval
+	DefaultView      string         `yaml:"default_view"`
+	Theme            string         `yaml:"theme"` // "dark" (default), "light", "high-contrast" or a custom theme
+}
+
+// CacheConfig controls the persistent on-disk cache under ~/.tgcp/cache
+type CacheConfig struct {
+	Disk      bool `yaml:"disk"`
+	MaxSizeMB int  `yaml:"max_size_mb"`
+}
+
+// RetryConfig tunes retries of transient API failures. Zero values use the
+// defaults, except max_retries: 0, which turns retries off.
+type RetryConfig struct {
+	MaxRetries    *int `yaml:"max_retries"` // nil when not set
+	BaseDelayMs   int  `yaml:"base_delay_ms"`
+	MaxDelayMs    int  `yaml:"max_delay_ms"`
+	MaxRetryAfter int  `yaml:"max_retry_after"` // seconds
+}
+
+// RateLimitConfig is a token bucket limit for one API host
+type RateLimitConfig struct {
+	RPS   float64 `yaml:"rps"`
+	Burst int     `yaml:"burst"`
+}
+
+// SearchConfig sets the scope of the "all projects" resource search
+type SearchConfig struct {
+	Projects []string `yaml:"projects"` // Searched along with the current project
+	Scope    string   `yaml:"scope"`    // Cloud Asset scope, e.g. "organizations/123"; replaces Projects
+}
+
+// TableLayout is the saved column layout of one table. Columns are named by
+// their header; columns not listed in Order keep their default position
+// after the listed ones.
+type TableLayout struct {
+	Order    []string `yaml:"order,omitempty"`
+	Hidden   []string `yaml:"hidden,omitempty"`
+	SortBy   string   `yaml:"sort_by,omitempty"`
+	SortDesc bool     `yaml:"sort_desc,omitempty"`
+}
+
+// KeyList is the keys bound to an action. A single key may be written as
+// a plain string instead of a list; an empty list unbinds the action.
+type KeyList []string
+
+// UnmarshalYAML accepts either "x" or [x, ctrl+x]
+func (k *KeyList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*k = nil
+		if value.Value != "" {
+			*k = KeyList{value.Value}
+		}
+		return nil
+	}
+	var keys []string
+	if err := value.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// ThemeConfig is a color theme. Colors are lipgloss colors: ANSI-256
+// numbers such as "39" or hex such as "#268bd2". Colors left empty are
+// taken from Base, a built-in or custom theme ("dark" by default).
+type ThemeConfig struct {
+	Base string `yaml:"base"`
+
+	Primary   string `yaml:"primary"` // Titles, focused borders and the table cursor text
+	Accent    string `yaml:"accent"`  // Highlights and selected items; a profile's accent replaces it
+	Text      string `yaml:"text"`
+	Muted     string `yaml:"muted"`
+	Border    string `yaml:"border"`
+	Success   string `yaml:"success"`
+	Warning   string `yaml:"warning"`
+	Error     string `yaml:"error"`
+	Info      string `yaml:"info"`
+	BadgeText string `yaml:"badge_text"` // Text on colored badges
+
+	HeaderBg    string `yaml:"header_bg"`   // Table headers
+	SelectedFg  string `yaml:"selected_fg"` // Selected row of the focused table
+	SelectedBg  string `yaml:"selected_bg"`
+	BlurredFg   string `yaml:"blurred_fg"` // Selected row of an unfocused table
+	BlurredBg   string `yaml:"blurred_bg"`
+	StatusBarFg string `yaml:"statusbar_fg"`
+	StatusBarBg string `yaml:"statusbar_bg"`
+	PanelBg     string `yaml:"panel_bg"` // Toasts and the operations panel
+}
+
+// Inherit fills the colors t leaves empty from base
+func (t ThemeConfig) Inherit(base ThemeConfig) ThemeConfig {
+	pick := func(c *string, fallback string) {
+		if *c == "" {
+			*c = fallback
+		}
+	}
+	pick(&t.Primary, base.Primary)
+	pick(&t.Accent, base.Accent)
+	pick(&t.Text, base.Text)
+	pick(&t.Muted, base.Muted)
+	pick(&t.Border, base.Border)
+	pick(&t.Success, base.Success)
+	pick(&t.Warning, base.Warning)
+	pick(&t.Error, base.Error)
+	pick(&t.Info, base.Info)
+	pick(&t.BadgeText, base.BadgeText)
+	pick(&t.HeaderBg, base.HeaderBg)
+	pick(&t.SelectedFg, base.SelectedFg)
+	pick(&t.SelectedBg, base.SelectedBg)
+	pick(&t.BlurredFg, base.BlurredFg)
+	pick(&t.BlurredBg, base.BlurredBg)
+	pick(&t.StatusBarFg, base.StatusBarFg)
+	pick(&t.StatusBarBg, base.StatusBarBg)
+	pick(&t.PanelBg, base.PanelBg)
+	return t
+}
+
+// EndpointConfig points a single service at a custom API endpoint.
+// When Emulator is set, requests are sent without Google credentials.
+type EndpointConfig struct {
+	URL      string `yaml:"url"`
+	Emulator bool   `yaml:"emulator"`
 }
 
 type FeaturesConfig struct {
@@ -29,26 +220,99 @@ type FeaturesConfig struct {
 func DefaultConfig() *Config {
 	return &Config{
 		UI: UIConfig{
-			SidebarVisible:  true,
-			RefreshInterval: 30,
-			DefaultView:     "home",
+			SidebarVisible: true,
+			DefaultView:    "home",
 		},
 		Features: FeaturesConfig{
 			EnableGCE:      true,
 			EnableCloudSQL: true,
 		},
+		Cache: CacheConfig{
+			Disk:      true,
+			MaxSizeMB: 100,
+		},
+	}
+}
+
+// WithProfile returns a copy of the loaded config with the named profile
+// applied on top. Profiles never stack: switching from one profile to
+// another starts again from the loaded values.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	base := c
+	if c.base != nil {
+		base = c.base
+	}
+
+	p, ok := base.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+
+	out := *base
+	out.base = base
+	out.ActiveProfile = name
+	if p.Project != "" {
+		out.Project = p.Project
+	}
+	if p.Region != "" {
+		out.Region = p.Region
+	}
+	if p.Zone != "" {
+		out.Zone = p.Zone
+	}
+	if p.ImpersonateServiceAccount != "" && !base.impersonationPinned {
+		out.ImpersonateServiceAccount = p.ImpersonateServiceAccount
+	}
+	return &out, nil
+}
+
+// SetImpersonationFlag applies --impersonate-service-account. Call it on
+// the loaded config, before WithProfile: the account then wins over every
+// profile's, including after a runtime profile switch.
+func (c *Config) SetImpersonationFlag(account string) {
+	if account == "" {
+		return
 	}
+	c.ImpersonateServiceAccount = account
+	c.impersonationPinned = true
+}
+
+// Profile returns the active profile's settings
+func (c *Config) Profile() (ProfileConfig, bool) {
+	if c.ActiveProfile == "" {
+		return ProfileConfig{}, false
+	}
+	p, ok := c.Profiles[c.ActiveProfile]
+	return p, ok
+}
+
+// ProfileNames returns the configured profile names, sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Path returns the location of the config file, ~/.tgcprc
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".tgcprc"), nil
 }
 
 func LoadConfig() (*Config, error) {
 	cfg := DefaultConfig()
 
-	home, err := os.UserHomeDir()
+	configPath, err := Path()
 	if err != nil {
 		return cfg, err
 	}
 
-	configPath := filepath.Join(home, ".tgcprc")
 	data, err := os.ReadFile(configPath)
 	if os.IsNotExist(err) {
 		return cfg, nil // Use defaults
@@ -63,3 +327,141 @@ func LoadConfig() (*Config, error) {
 
 	return cfg, nil
 }
+
+// ThemesDir returns the directory of theme files, ~/.tgcp/themes
+func ThemesDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".tgcp", "themes"), nil
+}
+
+// LoadThemes returns the themes defined in ~/.tgcprc together with those
+// in the *.yaml files of dir, each named after its file. A theme in
+// ~/.tgcprc wins over a file of the same name. Files that cannot be read
+// are skipped and reported in the error.
+func (c *Config) LoadThemes(dir string) (map[string]ThemeConfig, error) {
+	themes := make(map[string]ThemeConfig, len(c.Themes))
+	for name, t := range c.Themes {
+		themes[name] = t
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return themes, err
+	}
+	var problems []string
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".yaml")
+		if _, ok := themes[name]; ok {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		var t ThemeConfig
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		themes[name] = t
+	}
+
+	if len(problems) > 0 {
+		return themes, fmt.Errorf("themes: %s", strings.Join(problems, "; "))
+	}
+	return themes, nil
+}
+
+// SaveTables writes the table layouts to the config file. Only the "tables"
+// key is replaced; the rest of the file, comments included, is kept.
+func SaveTables(tables map[string]TableLayout) error {
+	configPath, err := Path()
+	if err != nil {
+		return err
+	}
+	return saveKey(configPath, "tables", tables)
+}
+
+// saveKey sets one top-level key of a YAML file, creating the file if needed
+func saveKey(path, key string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a YAML mapping", path)
+	}
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+	replaced := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			root.Content[i+1] = &node
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &node)
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, out.Bytes())
+}
+
+// writeFileAtomic replaces a file by writing a temp file next to it and
+// renaming it over the original, so a crash or a concurrent reader never
+// sees a truncated file. A symlinked file, e.g. from a dotfiles repo, is
+// replaced at its target and keeps its permissions.
+func writeFileAtomic(path string, data []byte) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWithProfile(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Project = "my-dev"
+	cfg.Region = "us-central1"
+	cfg.Profiles = map[string]ProfileConfig{
+		"prod":    {Project: "acme-prod", ImpersonateServiceAccount: "deploy@acme-prod.iam.gserviceaccount.com", ReadOnly: true},
+		"sandbox": {Region: "europe-west1", Zone: "europe-west1-b"},
+	}
+
+	prod, err := cfg.WithProfile("prod")
+	if err != nil {
+		t.Fatalf("WithProfile(prod) error = %v", err)
+	}
+	if prod.Project != "acme-prod" || prod.Region != "us-central1" || prod.ImpersonateServiceAccount == "" {
+		t.Errorf("WithProfile(prod) = %+v", prod)
+	}
+
+	// Switching from prod must not inherit prod's project or identity
+	sandbox, err := prod.WithProfile("sandbox")
+	if err != nil {
+		t.Fatalf("WithProfile(sandbox) error = %v", err)
+	}
+	if sandbox.Project != "my-dev" || sandbox.Region != "europe-west1" || sandbox.Zone != "europe-west1-b" || sandbox.ImpersonateServiceAccount != "" {
+		t.Errorf("WithProfile(sandbox) = %+v", sandbox)
+	}
+	if p, ok := sandbox.Profile(); !ok || p.ReadOnly {
+		t.Errorf("sandbox.Profile() = %+v, %v", p, ok)
+	}
+
+	if _, err := cfg.WithProfile("missing"); err == nil {
+		t.Error("WithProfile(missing) expected error")
+	}
+
+	// --impersonate-service-account wins over profiles, also after a switch
+	cfg.SetImpersonationFlag("me@my-dev.iam.gserviceaccount.com")
+	prod, _ = cfg.WithProfile("prod")
+	sandbox, _ = prod.WithProfile("sandbox")
+	for _, c := range []*Config{prod, sandbox} {
+		if c.ImpersonateServiceAccount != "me@my-dev.iam.gserviceaccount.com" {
+			t.Errorf("%s: ImpersonateServiceAccount = %q, want the flag's", c.ActiveProfile, c.ImpersonateServiceAccount)
+		}
+	}
+}
+
+func TestSaveKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".tgcprc")
+	orig := "# my settings\nproject: acme\ntables:\n  old: {}\nui:\n  sidebar_visible: false\n"
+	if err := os.WriteFile(path, []byte(orig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tables := map[string]TableLayout{"disks": {Hidden: []string{"Type"}, SortBy: "Size", SortDesc: true}}
+	if err := saveKey(path, "tables", tables); err != nil {
+		t.Fatalf("saveKey error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# my settings") || strings.Contains(string(data), "old:") {
+		t.Errorf("saved file:\n%s", data)
+	}
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Project != "acme" || cfg.UI.SidebarVisible || !reflect.DeepEqual(cfg.Tables, tables) {
+		t.Errorf("reloaded config = %+v", cfg)
+	}
+
+	// A missing file is created
+	fresh := filepath.Join(t.TempDir(), ".tgcprc")
+	if err := saveKey(fresh, "tables", tables); err != nil {
+		t.Fatalf("saveKey(new file) error = %v", err)
+	}
+
+	// A symlinked file is replaced at its target, keeping its permissions,
+	// and no temp file is left behind
+	dir := t.TempDir()
+	target := filepath.Join(dir, "tgcprc")
+	link := filepath.Join(dir, ".tgcprc")
+	if err := os.WriteFile(target, []byte(orig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip(err)
+	}
+	if err := saveKey(link, "tables", tables); err != nil {
+		t.Fatalf("saveKey(symlink) error = %v", err)
+	}
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symlink replaced: %v", err)
+	}
+	if fi, err := os.Stat(target); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("target mode = %v, %v", fi.Mode(), err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("files left in dir: %v", entries)
+	}
+}
+
+func TestKeyList(t *testing.T) {
+	var cfg Config
+	data := "keys:\n  ssh: S\n  logs: [l, ctrl+l]\n  k9s: []\n  help: \"\"\n"
+	if err := yaml.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]KeyList{"ssh": {"S"}, "logs": {"l", "ctrl+l"}, "k9s": {}, "help": nil}
+	if !reflect.DeepEqual(cfg.Keys, want) {
+		t.Errorf("Keys = %#v, want %#v", cfg.Keys, want)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"solarized.yaml": "base: light\nselected_bg: \"#268bd2\"\n",
+		"mine.yaml":      "accent: \"201\"\n",
+		"broken.yaml":    "accent: [\n",
+		"notes.txt":      "not a theme",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Themes = map[string]ThemeConfig{"mine": {Accent: "99"}}
+	themes, err := cfg.LoadThemes(dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("LoadThemes error = %v, want broken.yaml reported", err)
+	}
+	want := map[string]ThemeConfig{
+		"solarized": {Base: "light", SelectedBg: "#268bd2"},
+		"mine":      {Accent: "99"}, // ~/.tgcprc wins over the file
+	}
+	if !reflect.DeepEqual(themes, want) {
+		t.Errorf("themes = %+v, want %+v", themes, want)
+	}
+
+	got := ThemeConfig{Accent: "1"}.Inherit(ThemeConfig{Accent: "2", Text: "3"})
+	if got.Accent != "1" || got.Text != "3" {
+		t.Errorf("Inherit = %+v", got)
+	}
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/utils"
+)
+
+// Audit Trail
+//
+// Every mutating action appends two JSON lines to ~/.tgcp/audit.jsonl: one
+// before the API call and one with its outcome. Both share an ActionID so an
+// action that never finished (crash, kill) is still visible as started.
+
+// Audit phases
+const (
+	AuditStarted  = "started"
+	AuditFinished = "finished"
+)
+
+// Audit results
+const (
+	AuditSuccess = "success"
+	AuditError   = "error"
+	AuditBlocked = "blocked" // Refused by read-only mode
+)
+
+// AuditAction describes a mutating action on one resource
+type AuditAction struct {
+	Service  string // Service short name, e.g. "gce"
+	Project  string
+	Resource string // e.g. "us-central1-a/web-1"
+	Action   string // e.g. "start", "stop", "snapshot"
+}
+
+// AuditEntry is one line of the audit log
+type AuditEntry struct {
+	Time         time.Time `json:"time"`
+	ActionID     string    `json:"action_id"`
+	Phase        string    `json:"phase"`
+	Identity     string    `json:"identity"`               // The signed-in user
+	Impersonated string    `json:"impersonated,omitempty"` // Service account the call ran as, if any
+	Host         string    `json:"host"`
+	Project      string    `json:"project"`
+	Service      string    `json:"service"`
+	Resource     string    `json:"resource"`
+	Action       string    `json:"action"`
+	Result       string    `json:"result,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	OperationID  string    `json:"operation_id,omitempty"`
+}
+
+var auditLog struct {
+	mu           sync.Mutex
+	path         string // Empty means DefaultAuditPath
+	identity     string
+	impersonated string
+}
+
+// DefaultAuditPath returns ~/.tgcp/audit.jsonl
+func DefaultAuditPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".tgcp", "audit.jsonl"), nil
+}
+
+// SetAuditPath overrides where the audit log is written
+func SetAuditPath(path string) {
+	auditLog.mu.Lock()
+	defer auditLog.mu.Unlock()
+	auditLog.path = path
+}
+
+// SetAuditIdentity records who performs actions from now on: the signed-in
+// user and, under impersonation, the service account the calls run as, so
+// actions through a shared account can still be traced to a person.
+// Call it after authenticating and after every profile switch.
+func SetAuditIdentity(user, impersonated string) {
+	auditLog.mu.Lock()
+	defer auditLog.mu.Unlock()
+	auditLog.identity, auditLog.impersonated = user, impersonated
+}
+
+func auditPath() (string, error) {
+	if auditLog.path != "" {
+		return auditLog.path, nil
+	}
+	return DefaultAuditPath()
+}
+
+// RunAudited performs a mutating action. It refuses actions blocked by
+// read-only mode and appends an audit line before and after the call.
+// call returns the long-running operation ID, if the API created one.
+//
+// Actions replayed from a cassette are not audited since nothing happened.
+func RunAudited(a AuditAction, call func() (operationID string, err error)) (string, error) {
+	if IsReplaying() {
+		if err := CheckMutation(a.Project, a.Action+" "+a.Resource); err != nil {
+			return "", err
+		}
+		return call()
+	}
+
+	entry := AuditEntry{
+		ActionID: newActionID(),
+		Phase:    AuditStarted,
+		Project:  a.Project,
+		Service:  a.Service,
+		Resource: a.Resource,
+		Action:   a.Action,
+	}
+
+	if err := CheckMutation(a.Project, a.Action+" "+a.Resource); err != nil {
+		entry.Phase, entry.Result, entry.Error = AuditFinished, AuditBlocked, err.Error()
+		appendAudit(entry)
+		return "", err
+	}
+
+	appendAudit(entry)
+	opID, err := call()
+
+	entry.Phase, entry.Result, entry.OperationID = AuditFinished, AuditSuccess, opID
+	if err != nil {
+		entry.Result, entry.Error = AuditError, err.Error()
+	}
+	appendAudit(entry)
+	return opID, err
+}
+
+// appendAudit writes one line. Failures are logged but never block the
+// action: an unwritable home directory must not make tgcp unusable.
+func appendAudit(e AuditEntry) {
+	auditLog.mu.Lock()
+	defer auditLog.mu.Unlock()
+
+	e.Time = time.Now().UTC()
+	e.Identity, e.Impersonated = auditLog.identity, auditLog.impersonated
+	e.Host, _ = os.Hostname()
+
+	path, err := auditPath()
+	if err != nil {
+		utils.Log("Audit log unavailable: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		utils.Log("Audit log unavailable: %v", err)
+		return
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		utils.Log("Failed to encode audit entry: %v", err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		utils.Log("Audit log unavailable: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		utils.Log("Failed to write audit entry: %v", err)
+	}
+}
+
+// ReadAuditLog returns up to limit of the most recent audit entries,
+// oldest first. A missing log is not an error.
+func ReadAuditLog(limit int) ([]AuditEntry, error) {
+	auditLog.mu.Lock()
+	path, err := auditPath()
+	auditLog.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip a torn or hand-edited line
+		}
+		entries = append(entries, e)
+		if limit > 0 && len(entries) > 2*limit {
+			entries = append(entries[:0], entries[len(entries)-limit:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
+
+func newActionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
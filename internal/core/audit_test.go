@@ -0,0 +1,53 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func TestRunAudited(t *testing.T) {
+	SetAuditPath(filepath.Join(t.TempDir(), "audit.jsonl"))
+	SetAuditIdentity("dev@example.com", "deploy@acme-dev.iam.gserviceaccount.com")
+	defer SetAuditPath("")
+	defer SetGuardrails(config.DefaultConfig())
+
+	action := AuditAction{Service: "gce", Project: "acme-dev", Resource: "us-central1-a/web-1", Action: "stop"}
+	opID, err := RunAudited(action, func() (string, error) { return "operation-123", nil })
+	if err != nil || opID != "operation-123" {
+		t.Fatalf("RunAudited() = %q, %v", opID, err)
+	}
+
+	// Blocked actions never reach the API but are still recorded
+	cfg := config.DefaultConfig()
+	cfg.ReadOnly = true
+	SetGuardrails(cfg)
+	called := false
+	if _, err := RunAudited(action, func() (string, error) { called = true; return "", nil }); !errors.Is(err, ErrReadOnly) || called {
+		t.Fatalf("RunAudited() in read-only mode = %v, called = %v", err, called)
+	}
+
+	entries, err := ReadAuditLog(0)
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("ReadAuditLog() returned %d entries, want 3", len(entries))
+	}
+	started, finished := entries[0], entries[1]
+	if started.Phase != AuditStarted || finished.Phase != AuditFinished || started.ActionID != finished.ActionID {
+		t.Errorf("entries do not pair up: %+v, %+v", started, finished)
+	}
+	if finished.Result != AuditSuccess || finished.OperationID != "operation-123" || finished.Identity != "dev@example.com" || finished.Impersonated != "deploy@acme-dev.iam.gserviceaccount.com" {
+		t.Errorf("finished entry = %+v", finished)
+	}
+	if entries[2].Result != AuditBlocked {
+		t.Errorf("blocked entry = %+v", entries[2])
+	}
+
+	if last, _ := ReadAuditLog(1); len(last) != 1 || last[0].Result != AuditBlocked {
+		t.Errorf("ReadAuditLog(1) = %+v", last)
+	}
+}
@@ -15,10 +15,19 @@ import (
 
 // AuthState holds the authentication information
 type AuthState struct {
-	Authenticated bool
-	UserEmail     string
-	ProjectID     string
-	Error         error
+	Authenticated       bool
+	UserEmail           string
+	ImpersonatedAccount string // Service account all API calls run as, if any
+	ProjectID           string
+	Error               error
+}
+
+// EffectiveIdentity returns the identity API calls are made with
+func (a AuthState) EffectiveIdentity() string {
+	if a.ImpersonatedAccount != "" {
+		return a.ImpersonatedAccount
+	}
+	return a.UserEmail
 }
 
 // Authenticate performs the ADC check and project detection
@@ -38,6 +47,23 @@ func Authenticate(ctx context.Context, projectOverride string) AuthState {
 		return state
 	}
 
+	// Impersonation is verified up front so a missing
+	// roles/iam.serviceAccountTokenCreator shows on the auth screen
+	// instead of as a failure in every service.
+	if target := ImpersonatedAccount(); target != "" {
+		ts, err := impersonatedTokenSource(ctx)
+		if err == nil {
+			_, err = ts.Token()
+		}
+		if err != nil {
+			utils.Log("Impersonation failed: %v", err)
+			state.Error = fmt.Errorf("could not impersonate %s: %w", target, err)
+			return state
+		}
+		state.ImpersonatedAccount = target
+		utils.Log("Impersonating service account: %s", target)
+	}
+
 	state.Authenticated = true
 
 	// 2. Determine Project ID
@@ -119,6 +145,6 @@ func Authenticate(ctx context.Context, projectOverride string) AuthState {
 		}
 	}
 
-	utils.Log("Auth complete. User: %s, Project: %s", state.UserEmail, state.ProjectID)
+	utils.Log("Auth complete. User: %s, Identity: %s, Project: %s", state.UserEmail, state.EffectiveIdentity(), state.ProjectID)
 	return state
 }
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bulk Actions
+//
+// A bulk action runs the same action on several resources at once, e.g.
+// stopping the marked VMs. RunBulk submits every item in parallel; each one
+// reports a BulkItemMsg. Items that start a long-running operation are then
+// followed by the operation tracker like any other, so the operations panel
+// shows per-item progress. Once every item has finished, a single
+// BulkDoneMsg summarizes the batch instead of one toast per resource.
+
+// maxBulkErrors is how many failures the summary toast spells out
+const maxBulkErrors = 3
+
+// BulkItem is one resource of a bulk action
+type BulkItem struct {
+	Resource string
+
+	// Run performs the action and returns the operation to follow, if the
+	// API created one
+	Run func() (Operation, OperationPoller, error)
+}
+
+// BulkItemMsg reports that one item was submitted, or failed to be
+type BulkItemMsg struct {
+	Bulk     string
+	Resource string
+	Op       Operation
+	Poll     OperationPoller
+	Err      error
+}
+
+// BulkResult is the outcome of one item
+type BulkResult struct {
+	Resource string
+	Err      error
+}
+
+// BulkDoneMsg reports a bulk action whose items have all finished
+type BulkDoneMsg struct {
+	Service     string
+	Project     string
+	Description string // e.g. "Stopping 30 instances"
+	Results     []BulkResult
+	Elapsed     time.Duration
+}
+
+// Failed returns the results of the items that failed
+func (m BulkDoneMsg) Failed() []BulkResult {
+	var failed []BulkResult
+	for _, r := range m.Results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// BulkProgress describes a running bulk action
+type BulkProgress struct {
+	Description string
+	Total       int
+	Done        int
+	Failed      int
+	Started     time.Time
+}
+
+type bulkRun struct {
+	BulkDoneMsg
+	total   int
+	started time.Time
+}
+
+var bulks struct {
+	mu   sync.Mutex
+	runs map[string]*bulkRun
+	next int
+}
+
+// RunBulk submits every item in parallel and returns the command that does
+// it. The caller confirms the action first.
+func RunBulk(service, project, description string, items []BulkItem) tea.Cmd {
+	if len(items) == 0 {
+		return nil
+	}
+
+	bulks.mu.Lock()
+	if bulks.runs == nil {
+		bulks.runs = make(map[string]*bulkRun)
+	}
+	bulks.next++
+	id := fmt.Sprintf("bulk-%d", bulks.next)
+	bulks.runs[id] = &bulkRun{
+		BulkDoneMsg: BulkDoneMsg{Service: service, Project: project, Description: description},
+		total:       len(items),
+		started:     time.Now(),
+	}
+	bulks.mu.Unlock()
+
+	cmds := make([]tea.Cmd, len(items))
+	for i, item := range items {
+		item := item
+		cmds[i] = func() tea.Msg {
+			op, poll, err := item.Run()
+			op.Bulk = id
+			return BulkItemMsg{Bulk: id, Resource: item.Resource, Op: op, Poll: poll, Err: err}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// HandleBulkItem follows the operation a submitted item started, or counts
+// the item as finished if there is none to follow
+func HandleBulkItem(msg BulkItemMsg) tea.Cmd {
+	if msg.Err == nil && msg.Op.ID != "" && msg.Poll != nil {
+		return TrackOperation(msg.Op, msg.Poll)
+	}
+	return finishBulkItem(msg.Bulk, msg.Resource, msg.Err)
+}
+
+// BulkOperationDone counts a finished operation of a bulk action
+func BulkOperationDone(op Operation) tea.Cmd {
+	return finishBulkItem(op.Bulk, op.Resource, op.Err)
+}
+
+// finishBulkItem records an item's result and returns the BulkDoneMsg
+// once it was the last one
+func finishBulkItem(id, resource string, err error) tea.Cmd {
+	bulks.mu.Lock()
+	defer bulks.mu.Unlock()
+
+	run, ok := bulks.runs[id]
+	if !ok {
+		return nil
+	}
+	run.Results = append(run.Results, BulkResult{Resource: resource, Err: err})
+	if len(run.Results) < run.total {
+		return nil
+	}
+
+	delete(bulks.runs, id)
+	done := run.BulkDoneMsg
+	done.Elapsed = time.Since(run.started)
+	sort.Slice(done.Results, func(i, j int) bool { return done.Results[i].Resource < done.Results[j].Resource })
+	return func() tea.Msg { return done }
+}
+
+// PendingBulks returns the running bulk actions, oldest first
+func PendingBulks() []BulkProgress {
+	bulks.mu.Lock()
+	defer bulks.mu.Unlock()
+
+	out := make([]BulkProgress, 0, len(bulks.runs))
+	for _, run := range bulks.runs {
+		p := BulkProgress{Description: run.Description, Total: run.total, Started: run.started}
+		for _, r := range run.Results {
+			p.Done++
+			if r.Err != nil {
+				p.Failed++
+			}
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Started.Before(out[j].Started) })
+	return out
+}
+
+// BulkToast summarizes a finished bulk action for a toast notification
+func BulkToast(msg BulkDoneMsg) ToastMsg {
+	elapsed := msg.Elapsed.Round(time.Second)
+	failed := msg.Failed()
+	if len(failed) == 0 {
+		return ToastMsg{
+			Message:  fmt.Sprintf("%s: all %d done in %s", msg.Description, len(msg.Results), elapsed),
+			Type:     ToastSuccess,
+			Duration: 5 * time.Second,
+		}
+	}
+
+	var reasons []string
+	for i, r := range failed {
+		if i == maxBulkErrors {
+			reasons = append(reasons, fmt.Sprintf("and %d more", len(failed)-maxBulkErrors))
+			break
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %v", r.Resource, r.Err))
+	}
+	return ToastMsg{
+		Message: fmt.Sprintf("%s: %d done, %d failed in %s (%s)",
+			msg.Description, len(msg.Results)-len(failed), len(failed), elapsed, strings.Join(reasons, "; ")),
+		Type:     ToastError,
+		Duration: 10 * time.Second,
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRunBulk(t *testing.T) {
+	poll := func(ctx context.Context) (bool, error, error) { return true, nil, nil }
+	items := []BulkItem{
+		{Resource: "web-1", Run: func() (Operation, OperationPoller, error) {
+			return Operation{ID: "operation-web-1", Service: "gce", Resource: "web-1"}, poll, nil
+		}},
+		{Resource: "web-2", Run: func() (Operation, OperationPoller, error) {
+			return Operation{Resource: "web-2"}, nil, errors.New("permission denied")
+		}},
+		{Resource: "web-3", Run: func() (Operation, OperationPoller, error) {
+			return Operation{Resource: "web-3"}, nil, nil
+		}},
+	}
+
+	batch, ok := RunBulk("gce", "p", "Stopping 3 instances", items)().(tea.BatchMsg)
+	if !ok || len(batch) != 3 {
+		t.Fatalf("RunBulk() = %v, want a batch of 3", batch)
+	}
+	if got := PendingBulks(); len(got) != 1 || got[0].Total != 3 || got[0].Done != 0 {
+		t.Fatalf("PendingBulks() = %+v", got)
+	}
+
+	tracked := false
+	for _, cmd := range batch {
+		msg := cmd().(BulkItemMsg)
+		if msg.Op.Bulk != msg.Bulk {
+			t.Errorf("%s: operation not tagged with its bulk action", msg.Resource)
+		}
+		if next := HandleBulkItem(msg); msg.Resource == "web-1" {
+			if next == nil {
+				t.Fatal("the operation of web-1 should be tracked")
+			}
+			tracked = true
+		} else if next != nil {
+			t.Fatalf("%s finished the bulk action early", msg.Resource)
+		}
+	}
+	if got := PendingBulks(); got[0].Done != 2 || got[0].Failed != 1 {
+		t.Errorf("PendingBulks() = %+v, want 2 done, 1 failed", got)
+	}
+
+	// The tracked operation finishing completes the bulk action
+	opDone, ok := pollOperation("gce/operation-web-1").(OperationDoneMsg)
+	if !tracked || !ok {
+		t.Fatal("expected the operation of web-1 to finish")
+	}
+	cmd := BulkOperationDone(opDone.Op)
+	if cmd == nil {
+		t.Fatal("BulkOperationDone() should finish the bulk action")
+	}
+	done := cmd().(BulkDoneMsg)
+	if done.Service != "gce" || len(done.Results) != 3 || len(done.Failed()) != 1 {
+		t.Errorf("BulkDoneMsg = %+v", done)
+	}
+	if len(PendingBulks()) != 0 {
+		t.Error("finished bulk action still pending")
+	}
+	if BulkOperationDone(opDone.Op) != nil {
+		t.Error("a finished bulk action should ignore late results")
+	}
+}
+
+func TestBulkToast(t *testing.T) {
+	ok := BulkToast(BulkDoneMsg{
+		Description: "Stopping 2 instances",
+		Results:     []BulkResult{{Resource: "a"}, {Resource: "b"}},
+		Elapsed:     41 * time.Second,
+	})
+	if ok.Type != ToastSuccess || ok.Message != "Stopping 2 instances: all 2 done in 41s" {
+		t.Errorf("success toast = %+v", ok)
+	}
+
+	var results []BulkResult
+	for _, r := range []string{"a", "b", "c", "d", "e"} {
+		results = append(results, BulkResult{Resource: r, Err: errors.New("quota exceeded")})
+	}
+	failed := BulkToast(BulkDoneMsg{Description: "Stopping 6 instances", Results: append(results, BulkResult{Resource: "f"})})
+	if failed.Type != ToastError {
+		t.Errorf("failure toast type = %v", failed.Type)
+	}
+	for _, want := range []string{"1 done, 5 failed", "a: quota exceeded", "c: quota exceeded", "and 2 more"} {
+		if !strings.Contains(failed.Message, want) {
+			t.Errorf("failure toast %q missing %q", failed.Message, want)
+		}
+	}
+}
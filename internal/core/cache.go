@@ -1,37 +1,93 @@
 package core
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/utils"
 )
 
 // CacheItem holds the value and expiration time
 type CacheItem struct {
 	Value      interface{}
 	Expiration time.Time
+	StoredAt   time.Time
+}
+
+// inflightFetch tracks a fetch in progress so concurrent callers share its result
+type inflightFetch struct {
+	done  chan struct{}
+	value interface{}
+	err   error
 }
 
-// Cache provides a thread-safe in-memory cache
+// Cache provides a thread-safe in-memory cache, optionally backed by a
+// DiskCache. Keys are scoped to the active project so switching projects
+// never serves another project's data.
 type Cache struct {
-	items map[string]CacheItem
-	mu    sync.RWMutex
+	items    map[string]CacheItem
+	inflight map[string]*inflightFetch
+	mu       sync.RWMutex
+	project  string
+	disk     *DiskCache
 }
 
 // NewCache creates a new Cache instance
 func NewCache() *Cache {
 	return &Cache{
-		items: make(map[string]CacheItem),
+		items:    make(map[string]CacheItem),
+		inflight: make(map[string]*inflightFetch),
 	}
 }
 
-// Set adds an item to the cache with a TTL
-func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
+// AttachDisk enables write-through persistence to the given disk cache
+func (c *Cache) AttachDisk(d *DiskCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disk = d
+}
+
+// SetProject scopes subsequent cache operations to projectID
+func (c *Cache) SetProject(projectID string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.project = projectID
+}
+
+// scopedKey prefixes key with the active project. Caller must hold c.mu.
+func (c *Cache) scopedKey(key string) string {
+	return c.project + "/" + key
+}
+
+// Set adds an item to the cache with a TTL
+func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
+	c.mu.RLock()
+	project := c.project
+	c.mu.RUnlock()
+	c.setForProject(project, key, value, ttl)
+}
 
-	c.items[key] = CacheItem{
+// setForProject stores value under project even if the active project has
+// changed since the caller started fetching it.
+func (c *Cache) setForProject(project, key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	now := time.Now()
+	c.items[project+"/"+key] = CacheItem{
 		Value:      value,
-		Expiration: time.Now().Add(ttl),
+		Expiration: now.Add(ttl),
+		StoredAt:   now,
+	}
+	disk := c.disk
+	c.mu.Unlock()
+
+	// Write-through outside the lock; callers run inside tea.Cmd goroutines
+	if disk != nil {
+		if err := disk.Save(project, key, value); err != nil {
+			utils.Log("Disk cache write failed: %v", err)
+		}
 	}
 }
 
@@ -40,7 +96,7 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	item, found := c.items[key]
+	item, found := c.items[c.scopedKey(key)]
 	if !found {
 		return nil, false
 	}
@@ -52,16 +108,186 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 	return item.Value, true
 }
 
+// UpdatedAt returns when the value for key was last stored, even if it has expired
+func (c *Cache) UpdatedAt(key string) (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, found := c.items[c.scopedKey(key)]
+	if !found {
+		return time.Time{}, false
+	}
+	return item.StoredAt, true
+}
+
+// Age returns how long ago the value for key was stored
+func (c *Cache) Age(key string) (time.Duration, bool) {
+	updated, ok := c.UpdatedAt(key)
+	if !ok {
+		return 0, false
+	}
+	return time.Since(updated), true
+}
+
 // Delete removes an item from the cache
 func (c *Cache) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.items, key)
+	delete(c.items, c.scopedKey(key))
 }
 
-// Flush removes all items from the cache
+// Flush removes all in-memory items from the cache. Persisted entries are kept.
 func (c *Cache) Flush() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.items = make(map[string]CacheItem)
 }
+
+// InvalidateProject drops every memory and disk entry for the active project
+func (c *Cache) InvalidateProject() error {
+	c.mu.Lock()
+	prefix := c.scopedKey("")
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.items, key)
+		}
+	}
+	disk, project := c.disk, c.project
+	c.mu.Unlock()
+
+	if disk != nil {
+		return disk.InvalidateProject(project)
+	}
+	return nil
+}
+
+// GetOrFetch returns the cached value for key, calling fetch on a miss.
+//
+//   - A fresh value is returned without calling fetch unless force is set.
+//   - Concurrent calls for the same key share a single fetch, so a background
+//     tick racing a manual refresh results in one API call.
+//   - An expired value is refetched by the first caller to find it, which
+//     waits for the result: it's the only one that can hand the fresh value
+//     to its view. Non-forced callers arriving while that fetch runs get the
+//     expired value immediately instead of waiting too.
+//
+// A nil cache simply calls fetch.
+func GetOrFetch[T any](c *Cache, key string, ttl time.Duration, force bool, fetch func() (T, error)) (T, error) {
+	if c == nil {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	project := c.project
+	scoped := c.scopedKey(key)
+	item, found := c.items[scoped]
+	cached, typed := item.Value.(T)
+	if found && typed && !force && time.Now().Before(item.Expiration) {
+		c.mu.Unlock()
+		return cached, nil
+	}
+
+	call, running := c.inflight[scoped]
+	if running && found && typed && !force {
+		c.mu.Unlock()
+		return cached, nil
+	}
+	if !running {
+		call = &inflightFetch{done: make(chan struct{})}
+		c.inflight[scoped] = call
+	}
+	c.mu.Unlock()
+
+	if running {
+		<-call.done
+	} else {
+		call.value, call.err = fetch()
+		if call.err == nil {
+			c.setForProject(project, key, call.value, ttl)
+		}
+		c.mu.Lock()
+		delete(c.inflight, scoped)
+		c.mu.Unlock()
+		close(call.done)
+	}
+
+	var zero T
+	if call.err != nil {
+		return zero, call.err
+	}
+	value, ok := call.value.(T)
+	if !ok {
+		return zero, fmt.Errorf("cache entry %s has unexpected type %T", key, call.value)
+	}
+	return value, nil
+}
+
+// LastUpdatedCmd reports when the cached value for key was fetched so the
+// status bar can show its age. It returns nil when key is not cached.
+func LastUpdatedCmd(c *Cache, key string) tea.Cmd {
+	if c == nil {
+		return nil
+	}
+	updated, ok := c.UpdatedAt(key)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg { return LastUpdatedMsg(updated) }
+}
+
+// LoadPersisted reads the last value saved to disk for key in the active project.
+// Returns the time it was saved, or ok=false when there is no disk layer or entry.
+func LoadPersisted[T any](c *Cache, key string) (value T, savedAt time.Time, ok bool) {
+	if c == nil {
+		return value, savedAt, false
+	}
+	c.mu.RLock()
+	disk, project := c.disk, c.project
+	c.mu.RUnlock()
+	if disk == nil {
+		return value, savedAt, false
+	}
+	savedAt, ok = disk.Load(project, key, &value)
+	return value, savedAt, ok
+}
+
+// PreloadPersisted wraps a fetch command so that, on a memory cache miss, the
+// last persisted value for key is rendered first (marked stale in the status
+// bar) while fetch refreshes it in the background.
+//
+// Example:
+//
+//	return tea.Batch(
+//	    s.spinner.Start(""),
+//	    core.PreloadPersisted(s.cache, key, func(v []Instance) tea.Msg { return instancesMsg(v) }, s.fetchInstancesCmd(true)),
+//	)
+func PreloadPersisted[T any](c *Cache, key string, toMsg func(T) tea.Msg, fetch tea.Cmd) tea.Cmd {
+	if c == nil {
+		return fetch
+	}
+	if _, found := c.Get(key); found {
+		return fetch
+	}
+
+	stale := func() tea.Msg {
+		value, savedAt, ok := LoadPersisted[T](c, key)
+		if !ok {
+			return tea.BatchMsg{}
+		}
+		return tea.BatchMsg{
+			func() tea.Msg { return toMsg(value) },
+			func() tea.Msg { return StaleDataMsg{Key: key, SavedAt: savedAt} },
+		}
+	}
+
+	fresh := func() tea.Msg {
+		msg := fetch()
+		return tea.BatchMsg{
+			func() tea.Msg { return msg },
+			func() tea.Msg { return StaleDataMsg{Key: key} },
+		}
+	}
+
+	// Sequence guarantees the stale value is delivered before the fresh one
+	return tea.Sequence(stale, fresh)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrFetchCoalesces(t *testing.T) {
+	c := NewCache()
+	var calls int32
+	release := make(chan struct{})
+	fetch := func() ([]string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []string{"vm-1"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := GetOrFetch(c, "gce_instances", time.Minute, true, fetch)
+			if err != nil || len(got) != 1 {
+				t.Errorf("GetOrFetch() = %v, %v", got, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+	if _, ok := c.Age("gce_instances"); !ok {
+		t.Error("Age() found no entry after fetch")
+	}
+}
+
+func TestGetOrFetchServesStaleWhileRefreshing(t *testing.T) {
+	c := NewCache()
+	c.Set("sql_instances", []string{"old"}, -time.Second) // already expired
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	var first []string
+	go func() {
+		defer close(done)
+		first, _ = GetOrFetch(c, "sql_instances", time.Minute, false, func() ([]string, error) {
+			<-release
+			return []string{"new"}, nil
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	got, err := GetOrFetch(c, "sql_instances", time.Minute, false, func() ([]string, error) {
+		return nil, errors.New("should not be called")
+	})
+	if err != nil || got[0] != "old" {
+		t.Errorf("GetOrFetch() during refresh = %v, %v; want stale value", got, err)
+	}
+
+	close(release)
+	<-done
+	if len(first) == 0 || first[0] != "new" {
+		t.Errorf("first caller got %v, want the refetched value", first)
+	}
+	if got, _ := GetOrFetch(c, "sql_instances", time.Minute, false, func() ([]string, error) {
+		return nil, errors.New("should not be called")
+	}); got[0] != "new" {
+		t.Errorf("GetOrFetch() after refresh = %v, want new", got)
+	}
+}
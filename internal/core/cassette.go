@@ -0,0 +1,379 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yogirk/tgcp/internal/utils"
+)
+
+// Cassette Mode
+//
+// A cassette is a JSON file holding every API request/response pair seen by
+// the HTTP clients built in this package. In record mode the interactions are
+// captured (with credentials scrubbed) and written on exit; in replay mode the
+// whole UI is served from the file with no credentials or network access.
+//
+// Replay matches requests on method, URL and body. Repeated identical requests
+// are answered in recorded order, and the last answer is reused once the
+// sequence is exhausted so background refreshes keep working.
+
+// CassetteVersion is the on-disk format version
+const CassetteVersion = 1
+
+// scrubbedHeaders are never written to a cassette
+var scrubbedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Goog-Api-Key",
+	"Proxy-Authorization",
+}
+
+// scrubbedQueryParams are removed from recorded URLs
+var scrubbedQueryParams = []string{"access_token", "key"}
+
+// Cassette is the serialized form of a recorded session
+type Cassette struct {
+	Version      int           `json:"version"`
+	Project      string        `json:"project"`
+	UserEmail    string        `json:"user_email"`
+	RecordedAt   time.Time     `json:"recorded_at"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Request    RecordedRequest  `json:"request"`
+	Response   RecordedResponse `json:"response"`
+	DurationMs int64            `json:"duration_ms"`
+}
+
+// RecordedRequest holds the scrubbed request
+type RecordedRequest struct {
+	Method  string       `json:"method"`
+	URL     string       `json:"url"`
+	Headers http.Header  `json:"headers,omitempty"`
+	Body    RecordedBody `json:"body,omitempty"`
+}
+
+// RecordedResponse holds the scrubbed response
+type RecordedResponse struct {
+	StatusCode int          `json:"status_code"`
+	Headers    http.Header  `json:"headers,omitempty"`
+	Body       RecordedBody `json:"body,omitempty"`
+}
+
+// RecordedBody stores text bodies verbatim for readable bug reports and
+// falls back to base64 for binary payloads.
+type RecordedBody struct {
+	Text   string `json:"text,omitempty"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+func newRecordedBody(b []byte) RecordedBody {
+	if len(b) == 0 {
+		return RecordedBody{}
+	}
+	if utf8.Valid(b) {
+		return RecordedBody{Text: string(b)}
+	}
+	return RecordedBody{Base64: base64.StdEncoding.EncodeToString(b)}
+}
+
+// Bytes returns the raw body
+func (b RecordedBody) Bytes() []byte {
+	if b.Base64 != "" {
+		data, err := base64.StdEncoding.DecodeString(b.Base64)
+		if err == nil {
+			return data
+		}
+	}
+	return []byte(b.Text)
+}
+
+// cassetteState is the process-wide recorder/replayer
+type cassetteState struct {
+	mu       sync.Mutex
+	path     string
+	cassette *Cassette
+	replay   bool
+	index    map[string][]int // request key -> interaction indexes
+	cursor   map[string]int   // request key -> next position in index
+}
+
+var activeCassette *cassetteState
+
+// StartRecording enables record mode. Interactions are written to path by SaveCassette.
+func StartRecording(path string) {
+	activeCassette = &cassetteState{
+		path: path,
+		cassette: &Cassette{
+			Version:    CassetteVersion,
+			RecordedAt: time.Now(),
+		},
+	}
+	utils.Log("Recording API traffic to %s", path)
+}
+
+// StartReplay loads a cassette and enables replay mode
+func StartReplay(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+	if c.Version != CassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d", c.Version)
+	}
+
+	state := &cassetteState{
+		path:     path,
+		cassette: &c,
+		replay:   true,
+		index:    make(map[string][]int),
+		cursor:   make(map[string]int),
+	}
+	for i, in := range c.Interactions {
+		key := interactionKey(in.Request.Method, in.Request.URL, in.Request.Body.Bytes())
+		state.index[key] = append(state.index[key], i)
+	}
+
+	activeCassette = state
+	utils.Log("Replaying %d API interactions from %s", len(c.Interactions), path)
+	return &c, nil
+}
+
+// IsReplaying reports whether API traffic is served from a cassette
+func IsReplaying() bool {
+	return activeCassette != nil && activeCassette.replay
+}
+
+// IsRecording reports whether API traffic is being captured
+func IsRecording() bool {
+	return activeCassette != nil && !activeCassette.replay
+}
+
+// SetCassetteIdentity stores the session's project and user in the cassette
+// so a replay can restore the same header without credentials.
+func SetCassetteIdentity(project, userEmail string) {
+	if !IsRecording() {
+		return
+	}
+	activeCassette.mu.Lock()
+	defer activeCassette.mu.Unlock()
+	activeCassette.cassette.Project = project
+	activeCassette.cassette.UserEmail = userEmail
+}
+
+// SaveCassette writes recorded interactions to disk. It is a no-op outside record mode.
+func SaveCassette() error {
+	if !IsRecording() {
+		return nil
+	}
+	activeCassette.mu.Lock()
+	defer activeCassette.mu.Unlock()
+
+	data, err := json.MarshalIndent(activeCassette.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(activeCassette.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	utils.Log("Saved %d API interactions to %s", len(activeCassette.cassette.Interactions), activeCassette.path)
+	return nil
+}
+
+// ReplayAuthState builds an AuthState from the loaded cassette instead of
+// looking up Application Default Credentials.
+func ReplayAuthState(projectOverride string) AuthState {
+	state := AuthState{Authenticated: true, UserEmail: "replay"}
+	if activeCassette != nil {
+		state.ProjectID = activeCassette.cassette.Project
+		if activeCassette.cassette.UserEmail != "" {
+			state.UserEmail = activeCassette.cassette.UserEmail + " (replay)"
+		}
+	}
+	if projectOverride != "" {
+		state.ProjectID = projectOverride
+	}
+	return state
+}
+
+// cassetteTransport wraps next with the recorder, or replaces it entirely
+// with the replayer, depending on the active cassette mode.
+func cassetteTransport(next http.RoundTripper) http.RoundTripper {
+	if activeCassette == nil {
+		return next
+	}
+	if activeCassette.replay {
+		return &ReplayTransport{state: activeCassette}
+	}
+	return &RecordingTransport{Next: next, state: activeCassette}
+}
+
+// RecordingTransport captures every request/response pair passing through it
+type RecordingTransport struct {
+	Next  http.RoundTripper
+	state *cassetteState
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		// Network failures are not recorded; replay would only surface them as misses
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.state.mu.Lock()
+	t.state.cassette.Interactions = append(t.state.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     scrubURL(req.URL),
+			Headers: scrubHeaders(req.Header),
+			Body:    newRecordedBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    scrubHeaders(resp.Header),
+			Body:       newRecordedBody(respBody),
+		},
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+	t.state.mu.Unlock()
+
+	return resp, nil
+}
+
+// ReplayTransport answers requests from a loaded cassette without touching the network
+type ReplayTransport struct {
+	state *cassetteState
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recordedURL := scrubURL(req.URL)
+	key := interactionKey(req.Method, recordedURL, reqBody)
+
+	t.state.mu.Lock()
+	positions := t.state.index[key]
+	var in *Interaction
+	if len(positions) > 0 {
+		pos := t.state.cursor[key]
+		if pos >= len(positions) {
+			pos = len(positions) - 1
+		} else {
+			t.state.cursor[key] = pos + 1
+		}
+		in = &t.state.cassette.Interactions[positions[pos]]
+	}
+	t.state.mu.Unlock()
+
+	if in == nil {
+		utils.Log("Replay miss: %s %s", req.Method, recordedURL)
+		// Shaped like a Google API error so services render it like any other failure
+		body := fmt.Sprintf(`{"error":{"code":404,"message":"tgcp replay: no recorded response for %s %s","status":"NOT_FOUND"}}`,
+			req.Method, jsonEscape(recordedURL))
+		return newReplayResponse(req, http.StatusNotFound, http.Header{"Content-Type": {"application/json"}}, []byte(body)), nil
+	}
+
+	return newReplayResponse(req, in.Response.StatusCode, in.Response.Headers.Clone(), in.Response.Body.Bytes()), nil
+}
+
+func newReplayResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// drainRequestBody reads the request body and replaces it so it can be sent again
+func drainRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func interactionKey(method, rawURL string, body []byte) string {
+	key := method + " " + rawURL
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		key += " " + hex.EncodeToString(sum[:8])
+	}
+	return key
+}
+
+func scrubURL(u *url.URL) string {
+	clean := *u
+	q := clean.Query()
+	for _, p := range scrubbedQueryParams {
+		q.Del(p)
+	}
+	clean.RawQuery = q.Encode()
+	clean.User = nil
+	return clean.String()
+}
+
+func scrubHeaders(h http.Header) http.Header {
+	clean := h.Clone()
+	for _, name := range scrubbedHeaders {
+		clean.Del(name)
+	}
+	return clean
+}
+
+func jsonEscape(s string) string {
+	b, _ := json.Marshal(s)
+	return strings.Trim(string(b), `"`)
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	defer func() { activeCassette = nil }()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "session=secret")
+		io.WriteString(w, `{"items":["vm-`+r.URL.Query().Get("zone")+`"]}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	// Record
+	StartRecording(path)
+	SetCassetteIdentity("demo-project", "dev@example.com")
+	client := &http.Client{Transport: wrapTransport(http.DefaultTransport)}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/instances?zone=a&access_token=secret", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("record request failed: %v", err)
+	}
+	resp.Body.Close()
+	if err := SaveCassette(); err != nil {
+		t.Fatalf("SaveCassette() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("cassette contains unscrubbed credentials:\n%s", data)
+	}
+
+	// Replay
+	if _, err := StartReplay(path); err != nil {
+		t.Fatalf("StartReplay() error = %v", err)
+	}
+	if got := ReplayAuthState("").ProjectID; got != "demo-project" {
+		t.Errorf("ReplayAuthState().ProjectID = %q, want demo-project", got)
+	}
+
+	client, err = NewHTTPClient(context.Background())
+	if err != nil {
+		t.Fatalf("NewHTTPClient() in replay error = %v", err)
+	}
+	for i := 0; i < 2; i++ { // the last answer is reused once exhausted
+		resp, err = client.Get(srv.URL + "/instances?zone=a")
+		if err != nil {
+			t.Fatalf("replay request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != `{"items":["vm-a"]}` {
+			t.Errorf("replay body = %s", body)
+		}
+	}
+
+	resp, _ = client.Get(srv.URL + "/instances?zone=b")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("replay miss status = %d, want 404", resp.StatusCode)
+	}
+	if calls != 1 {
+		t.Errorf("server called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/utils"
+)
+
+// pruneInterval throttles how often the size cap is enforced after writes
+const pruneInterval = 30 * time.Second
+
+// DiskCache persists cache entries under ~/.tgcp/cache/<project>/ so the UI
+// can render the last known data immediately on a cold start.
+//
+// Entries are stored as one JSON file per key. The total size of the cache
+// directory is capped; the least recently written files are evicted first.
+type DiskCache struct {
+	dir       string
+	maxBytes  int64
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// diskEntry is the on-disk representation of a cached value
+type diskEntry struct {
+	Key     string          `json:"key"`
+	SavedAt time.Time       `json:"saved_at"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// DefaultCacheDir returns ~/.tgcp/cache
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".tgcp", "cache"), nil
+}
+
+// NewDiskCache creates the cache directory and enforces the size cap once.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	d := &DiskCache{dir: dir, maxBytes: maxBytes}
+	d.prune()
+	return d, nil
+}
+
+// Save writes value (JSON encoded) for the given project and key
+func (d *DiskCache) Save(project, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry %s: %w", key, err)
+	}
+	data, err := json.Marshal(diskEntry{Key: key, SavedAt: time.Now(), Value: raw})
+	if err != nil {
+		return err
+	}
+
+	projectDir := d.projectDir(project)
+	if err := os.MkdirAll(projectDir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temp file of our own first, so neither a crash nor another
+	// tgcp saving the same key leaves a truncated entry
+	name := entryFileName(key)
+	tmp, err := os.CreateTemp(projectDir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(projectDir, name)); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	due := time.Since(d.lastPrune) > pruneInterval
+	d.mu.Unlock()
+	if due {
+		d.prune()
+	}
+	return nil
+}
+
+// Load decodes the persisted value for project/key into out.
+// Returns the time the value was saved and whether an entry was found.
+func (d *DiskCache) Load(project, key string, out interface{}) (time.Time, bool) {
+	data, err := os.ReadFile(filepath.Join(d.projectDir(project), entryFileName(key)))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return time.Time{}, false
+	}
+	if err := json.Unmarshal(entry.Value, out); err != nil {
+		utils.Log("Discarding unreadable cache entry %s: %v", key, err)
+		return time.Time{}, false
+	}
+	return entry.SavedAt, true
+}
+
+// InvalidateProject removes every persisted entry for a project
+func (d *DiskCache) InvalidateProject(project string) error {
+	return os.RemoveAll(d.projectDir(project))
+}
+
+// prune evicts the oldest entries until the cache fits within maxBytes
+func (d *DiskCache) prune() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastPrune = time.Now()
+
+	if d.maxBytes <= 0 {
+		return
+	}
+
+	type fileInfo struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []fileInfo
+	var total int64
+
+	filepath.Walk(d.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		files = append(files, fileInfo{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	if total <= d.maxBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files {
+		if total <= d.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+	utils.Log("Disk cache pruned to %d bytes", total)
+}
+
+func (d *DiskCache) projectDir(project string) string {
+	if project == "" {
+		project = "_default"
+	}
+	// Project IDs are already path-safe, but never let a value escape the cache dir
+	project = strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(project)
+	return filepath.Join(d.dir, project)
+}
+
+// entryFileName hashes the cache key so keys like "dataproc:<project>:<region>"
+// map to portable file names.
+func entryFileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:12]) + ".json"
+}
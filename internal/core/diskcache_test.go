@@ -0,0 +1,40 @@
+package core
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDiskCacheConcurrentSave(t *testing.T) {
+	d, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Writers sharing a key must each use their own temp file
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := d.Save("acme", "gce:instances", strings.Repeat(string(rune('a'+i)), 4096)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var got string
+	if _, ok := d.Load("acme", "gce:instances", &got); !ok || len(got) != 4096 || strings.Trim(got, got[:1]) != "" {
+		t.Errorf("Load() = %d bytes, %v; want one writer's whole value", len(got), ok)
+	}
+	files, err := os.ReadDir(d.projectDir("acme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("cache dir holds %d files, want just the entry", len(files))
+	}
+}
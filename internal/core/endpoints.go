@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/yogirk/tgcp/internal/config"
+	"github.com/yogirk/tgcp/internal/utils"
+	"google.golang.org/api/option"
+)
+
+// Endpoint describes where a service client sends its API requests.
+type Endpoint struct {
+	URL      string // Base URL passed to option.WithEndpoint
+	Emulator bool   // Emulators are dialed without credentials
+}
+
+// emulatorEnvVars maps service short names to the standard environment
+// variables exported by the gcloud emulators and fake-gcs-server.
+var emulatorEnvVars = map[string]string{
+	"pubsub":    "PUBSUB_EMULATOR_HOST",
+	"firestore": "FIRESTORE_EMULATOR_HOST",
+	"datastore": "DATASTORE_EMULATOR_HOST",
+	"spanner":   "SPANNER_EMULATOR_HOST",
+	"gcs":       "STORAGE_EMULATOR_HOST",
+}
+
+// emulatorRESTPorts maps the gRPC port an emulator variable names by
+// default to the port where the same emulator serves REST, which is what
+// tgcp's clients speak. Other ports are used as given.
+var emulatorRESTPorts = map[string][2]string{
+	"spanner": {"9010", "9020"},
+}
+
+// grpcOnlyEmulatorEnvVars are emulator variables tgcp cannot honor because
+// the emulator has no REST API. They are rejected rather than ignored so
+// tgcp never silently talks to the real API instead.
+var grpcOnlyEmulatorEnvVars = map[string]string{
+	"bigtable": "BIGTABLE_EMULATOR_HOST",
+}
+
+// endpointPaths holds the API base path for services whose REST root is not "/".
+var endpointPaths = map[string]string{
+	"gcs": "storage/v1/",
+}
+
+var (
+	endpointOverrides = make(map[string]Endpoint)
+	endpointsMu       sync.RWMutex
+)
+
+// SetEndpoints installs the endpoint overrides from ~/.tgcprc.
+// It should be called once at startup, before any service client is created.
+func SetEndpoints(endpoints map[string]config.EndpointConfig) {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+
+	endpointOverrides = make(map[string]Endpoint, len(endpoints))
+	for service, ep := range endpoints {
+		if ep.URL == "" {
+			continue
+		}
+		endpointOverrides[service] = Endpoint{
+			URL:      normalizeEndpoint(service, ep.URL, ep.Emulator),
+			Emulator: ep.Emulator,
+		}
+	}
+}
+
+// ResolveEndpoint returns the endpoint override for a service, if any.
+// Priority: *_EMULATOR_HOST environment variable > ~/.tgcprc > default Google endpoint.
+func ResolveEndpoint(service string) (Endpoint, bool) {
+	if envVar, ok := emulatorEnvVars[service]; ok {
+		if host := os.Getenv(envVar); host != "" {
+			if ports, ok := emulatorRESTPorts[service]; ok {
+				if h, port, found := strings.Cut(host, ":"); found && port == ports[0] {
+					host = h + ":" + ports[1]
+				}
+			}
+			return Endpoint{
+				URL:      normalizeEndpoint(service, host, true),
+				Emulator: true,
+			}, true
+		}
+	}
+
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	ep, ok := endpointOverrides[service]
+	return ep, ok
+}
+
+// normalizeEndpoint turns "localhost:8085" style hosts into a base URL
+// with a scheme, the service API path and a trailing slash.
+func normalizeEndpoint(service, raw string, emulator bool) string {
+	url := strings.TrimSpace(raw)
+	if !strings.Contains(url, "://") {
+		scheme := "https://"
+		if emulator {
+			scheme = "http://"
+		}
+		url = scheme + url
+	}
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	if path, ok := endpointPaths[service]; ok && !strings.HasSuffix(url, path) {
+		url += path
+	}
+	return url
+}
+
+// ClientOptions returns the option set used to build a Google API client for
+// the given service. Requests go through the same transport chain as
+// NewHTTPClient, and the endpoint is overridden when one is configured.
+// Emulator endpoints skip authentication entirely. The service is the short
+// name of a tgcp service, or of an API a service uses besides its own
+// (e.g. "datastore", "billing").
+//
+// Example:
+//
+//	opts, err := core.ClientOptions(ctx, "pubsub", pubsub.PubsubScope)
+//	if err != nil {
+//	    return nil, err
+//	}
+//	svc, err := pubsub.NewService(ctx, opts...)
+func ClientOptions(ctx context.Context, service string, scopes ...string) ([]option.ClientOption, error) {
+	ep, hasOverride := ResolveEndpoint(service)
+	if envVar, ok := grpcOnlyEmulatorEnvVars[service]; ok && !hasOverride && os.Getenv(envVar) != "" {
+		return nil, fmt.Errorf("%s is set, but that emulator only serves gRPC and tgcp uses the REST API; unset it or configure endpoints.%s", envVar, service)
+	}
+
+	var httpClient *http.Client
+	if hasOverride && ep.Emulator {
+		httpClient = &http.Client{Transport: wrapTransport(http.DefaultTransport)}
+	} else {
+		client, err := NewHTTPClient(ctx, scopes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http client: %w", err)
+		}
+		httpClient = client
+	}
+	httpClient.Transport = &InspectorTransport{Next: httpClient.Transport, Service: service}
+
+	opts := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if hasOverride {
+		utils.Log("Using custom endpoint for %s: %s (emulator: %t)", service, ep.URL, ep.Emulator)
+		opts = append(opts, option.WithEndpoint(ep.URL))
+	}
+	return opts, nil
+}
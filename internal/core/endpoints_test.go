@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func TestResolveEndpoint(t *testing.T) {
+	SetEndpoints(map[string]config.EndpointConfig{
+		"pubsub": {URL: "localhost:9999", Emulator: true},
+		"gce":    {URL: "https://compute.example.com/compute/v1"},
+	})
+	defer SetEndpoints(nil)
+
+	tests := []struct {
+		name      string
+		service   string
+		env       string
+		wantURL   string
+		wantEmu   bool
+		wantFound bool
+	}{
+		{"config emulator", "pubsub", "", "http://localhost:9999/", true, true},
+		{"env wins over config", "pubsub", "localhost:8085", "http://localhost:8085/", true, true},
+		{"private endpoint", "gce", "", "https://compute.example.com/compute/v1/", false, true},
+		{"gcs api path", "gcs", "localhost:4443", "http://localhost:4443/storage/v1/", true, true},
+		{"spanner rest port", "spanner", "localhost:9010", "http://localhost:9020/", true, true},
+		{"spanner custom port", "spanner", "localhost:7000", "http://localhost:7000/", true, true},
+		{"no override", "sql", "", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if envVar, ok := emulatorEnvVars[tt.service]; ok {
+				t.Setenv(envVar, tt.env)
+			}
+			ep, found := ResolveEndpoint(tt.service)
+			if found != tt.wantFound || ep.URL != tt.wantURL || ep.Emulator != tt.wantEmu {
+				t.Errorf("ResolveEndpoint(%q) = %+v, %v; want %q, %v, %v", tt.service, ep, found, tt.wantURL, tt.wantEmu, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestClientOptionsGRPCOnlyEmulator(t *testing.T) {
+	t.Setenv("BIGTABLE_EMULATOR_HOST", "localhost:8086")
+	if _, err := ClientOptions(context.Background(), "bigtable"); err == nil {
+		t.Error("ClientOptions(bigtable) with BIGTABLE_EMULATOR_HOST set: expected error")
+	}
+
+	// A REST endpoint configured explicitly is used instead
+	SetEndpoints(map[string]config.EndpointConfig{"bigtable": {URL: "localhost:9999", Emulator: true}})
+	defer SetEndpoints(nil)
+	if _, err := ClientOptions(context.Background(), "bigtable"); err != nil {
+		t.Errorf("ClientOptions(bigtable) with endpoint configured: %v", err)
+	}
+}
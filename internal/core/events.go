@@ -1,6 +1,10 @@
 package core
 
-import "time"
+import (
+	"time"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
 
 // StatusMsg updates the status bar message
 type StatusMsg struct {
@@ -16,6 +20,7 @@ type SwitchToLogsMsg struct {
 	Filter string
 	Source string // The short name of the service initiating the switch
 	Heading string // Optional heading to display (e.g. resource name)
+	Project string // Empty for the current project
 }
 
 // SwitchToServiceMsg requests a context switch to a specific service
@@ -23,6 +28,19 @@ type SwitchToServiceMsg struct {
 	Service string // The short name of the service to switch to
 }
 
+// NavigateBackMsg requests a return to the previous place in the
+// navigation history
+type NavigateBackMsg struct{}
+
+// OpenResourceMsg requests a switch to a service showing one of its
+// resources, in another project if needed. An empty Ref shows the list.
+type OpenResourceMsg struct {
+	Service string // The short name of the owning service
+	Project string // Empty for the current project
+	Ref     services.ResourceRef
+	Action  string // Optional services.CommandProvider action to run on it
+}
+
 // ToastType defines the visual style of a toast notification
 type ToastType int
 
@@ -45,3 +63,10 @@ type LoadingMsg struct {
 	IsLoading bool
 	Message   string // Optional custom message (empty = use playful messages)
 }
+
+// StaleDataMsg marks the displayed data as served from the disk cache.
+// A zero SavedAt clears the marker once fresh data has arrived.
+type StaleDataMsg struct {
+	Key     string
+	SavedAt time.Time
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+// ErrReadOnly is returned by mutating actions while read-only mode applies
+var ErrReadOnly = errors.New("read-only mode")
+
+var (
+	guardrailsMu      sync.RWMutex
+	readOnlyAll       bool
+	readOnlyReason    string
+	readOnlyProjects  = make(map[string]bool)
+	protectedAll      bool
+	protectedProjects = make(map[string]bool)
+)
+
+// SetGuardrails installs the read-only and protected-project policy from the
+// active configuration. Call it again after switching profiles.
+func SetGuardrails(cfg *config.Config) {
+	guardrailsMu.Lock()
+	defer guardrailsMu.Unlock()
+
+	profile, hasProfile := cfg.Profile()
+	readOnlyAll, readOnlyReason = false, ""
+	switch {
+	case hasProfile && profile.ReadOnly:
+		readOnlyAll, readOnlyReason = true, fmt.Sprintf("profile %q is read-only", cfg.ActiveProfile)
+	case cfg.ReadOnly:
+		readOnlyAll, readOnlyReason = true, "read-only mode is enabled"
+	}
+	protectedAll = hasProfile && profile.Protected
+
+	readOnlyProjects = make(map[string]bool, len(cfg.ReadOnlyProjects))
+	for _, p := range cfg.ReadOnlyProjects {
+		readOnlyProjects[p] = true
+	}
+	protectedProjects = make(map[string]bool, len(cfg.ProtectedProjects))
+	for _, p := range cfg.ProtectedProjects {
+		protectedProjects[p] = true
+	}
+}
+
+// ReadOnlyReason explains why mutating actions are unavailable in a
+// project. ok is false when mutations are allowed.
+func ReadOnlyReason(projectID string) (reason string, ok bool) {
+	guardrailsMu.RLock()
+	defer guardrailsMu.RUnlock()
+
+	if readOnlyAll {
+		return readOnlyReason, true
+	}
+	if readOnlyProjects[projectID] {
+		return fmt.Sprintf("project %s is read-only", projectID), true
+	}
+	return "", false
+}
+
+// CheckMutation returns an error wrapping ErrReadOnly if action may not run
+// in the project. Every mutating command calls it before its API request.
+func CheckMutation(projectID, action string) error {
+	if reason, ok := ReadOnlyReason(projectID); ok {
+		return fmt.Errorf("%w: cannot %s (%s)", ErrReadOnly, action, reason)
+	}
+	return nil
+}
+
+// IsProtected reports whether actions in a project require typing the
+// resource name to confirm
+func IsProtected(projectID string) bool {
+	guardrailsMu.RLock()
+	defer guardrailsMu.RUnlock()
+	return protectedAll || protectedProjects[projectID]
+}
+
+// ReadOnlyToast reports a blocked action to the user
+func ReadOnlyToast(reason string) tea.Cmd {
+	return func() tea.Msg {
+		return ToastMsg{Message: "Read-only: " + reason, Type: ToastError}
+	}
+}
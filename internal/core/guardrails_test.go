@@ -0,0 +1,41 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func TestGuardrails(t *testing.T) {
+	defer SetGuardrails(config.DefaultConfig())
+
+	cfg := config.DefaultConfig()
+	cfg.ReadOnlyProjects = []string{"acme-prod"}
+	cfg.ProtectedProjects = []string{"acme-billing"}
+	SetGuardrails(cfg)
+
+	if err := CheckMutation("acme-dev", "stop instance web-1"); err != nil {
+		t.Errorf("CheckMutation(acme-dev) = %v, want nil", err)
+	}
+	if err := CheckMutation("acme-prod", "stop instance web-1"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("CheckMutation(acme-prod) = %v, want ErrReadOnly", err)
+	}
+	if !IsProtected("acme-billing") || IsProtected("acme-dev") {
+		t.Error("IsProtected should only match acme-billing")
+	}
+
+	// A read-only profile covers every project it switches to
+	cfg.Profiles = map[string]config.ProfileConfig{"prod": {ReadOnly: true, Protected: true}}
+	prod, err := cfg.WithProfile("prod")
+	if err != nil {
+		t.Fatalf("WithProfile(prod) error = %v", err)
+	}
+	SetGuardrails(prod)
+	if reason, ok := ReadOnlyReason("acme-dev"); !ok || reason != `profile "prod" is read-only` {
+		t.Errorf("ReadOnlyReason(acme-dev) = %q, %v", reason, ok)
+	}
+	if !IsProtected("acme-dev") {
+		t.Error("protected profile should protect every project")
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/impersonate"
+)
+
+// cloudPlatformScope is requested when a caller does not name narrower scopes
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+var (
+	impersonationMu sync.RWMutex
+	impersonateSA   string
+	delegateChain   []string
+)
+
+// SetImpersonation makes every API client mint tokens for a service account
+// through the IAM Credentials API instead of using the caller's credentials.
+//
+// Like gcloud's --impersonate-service-account, account may be a comma-separated
+// delegation chain; the last entry is the target and the others are delegates.
+// An empty account disables impersonation.
+func SetImpersonation(account string) {
+	var chain []string
+	for _, a := range strings.Split(account, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			chain = append(chain, a)
+		}
+	}
+
+	impersonationMu.Lock()
+	defer impersonationMu.Unlock()
+	impersonateSA, delegateChain = "", nil
+	if len(chain) > 0 {
+		impersonateSA = chain[len(chain)-1]
+		delegateChain = chain[:len(chain)-1]
+	}
+}
+
+// ImpersonatedAccount returns the service account being impersonated, if any
+func ImpersonatedAccount() string {
+	impersonationMu.RLock()
+	defer impersonationMu.RUnlock()
+	return impersonateSA
+}
+
+// impersonatedTokenSource returns a token source for the impersonated account,
+// or nil when impersonation is disabled.
+func impersonatedTokenSource(ctx context.Context, scopes ...string) (oauth2.TokenSource, error) {
+	impersonationMu.RLock()
+	target, delegates := impersonateSA, delegateChain
+	impersonationMu.RUnlock()
+
+	if target == "" {
+		return nil, nil
+	}
+	if len(scopes) == 0 {
+		scopes = []string{cloudPlatformScope}
+	}
+
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: target,
+		Scopes:          scopes,
+		Delegates:       delegates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", target, err)
+	}
+	return ts, nil
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// API Call Inspector
+//
+// InspectorTransport sits on top of every service client's transport chain and
+// records one APICall per request into a process-wide ring buffer. The lower
+// layers (rate limiter, retry) report into a per-request callStats carried on
+// the request context, so a single entry shows the whole story of a call.
+
+// maxAPICalls bounds the inspector history
+const maxAPICalls = 500
+
+// maxErrorBody is how much of an error response is kept to extract its message
+const maxErrorBody = 4 << 10
+
+// APICall is a single recorded HTTP call
+type APICall struct {
+	Time          time.Time
+	Service       string // Service short name, e.g. "gce"
+	Method        string
+	Host          string
+	Path          string
+	Status        int    // Zero when the request failed before a response
+	Error         string // Transport error or API error message
+	Latency       time.Duration
+	Retries       int
+	RateLimitWait time.Duration
+	Size          int64 // Response body bytes read
+}
+
+var apiCalls struct {
+	mu    sync.Mutex
+	calls []APICall
+	next  int
+}
+
+func recordAPICall(call APICall) {
+	apiCalls.mu.Lock()
+	defer apiCalls.mu.Unlock()
+	if len(apiCalls.calls) < maxAPICalls {
+		apiCalls.calls = append(apiCalls.calls, call)
+		return
+	}
+	apiCalls.calls[apiCalls.next] = call
+	apiCalls.next = (apiCalls.next + 1) % maxAPICalls
+}
+
+// RecentAPICalls returns the recorded calls, newest first
+func RecentAPICalls() []APICall {
+	apiCalls.mu.Lock()
+	defer apiCalls.mu.Unlock()
+
+	n := len(apiCalls.calls)
+	out := make([]APICall, 0, n)
+	for i := 0; i < n; i++ {
+		// Walk backwards from the most recently written slot
+		idx := (apiCalls.next - 1 - i + n) % n
+		out = append(out, apiCalls.calls[idx])
+	}
+	return out
+}
+
+// callStats collects what the lower transports did for one request
+type callStats struct {
+	mu      sync.Mutex
+	retries int
+	wait    time.Duration
+}
+
+type callStatsKey struct{}
+
+func callStatsFrom(ctx context.Context) *callStats {
+	cs, _ := ctx.Value(callStatsKey{}).(*callStats)
+	return cs
+}
+
+func (cs *callStats) addRetry() {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.retries++
+}
+
+func (cs *callStats) addWait(d time.Duration) {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.wait += d
+}
+
+// InspectorTransport records every request passing through it
+type InspectorTransport struct {
+	Next    http.RoundTripper
+	Service string
+}
+
+func (t *InspectorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cs := &callStats{}
+	req = req.WithContext(context.WithValue(req.Context(), callStatsKey{}, cs))
+
+	call := APICall{
+		Time:    time.Now(),
+		Service: t.Service,
+		Method:  req.Method,
+		Host:    req.URL.Host,
+		Path:    req.URL.Path,
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	call.Latency = time.Since(call.Time)
+	cs.mu.Lock()
+	call.Retries, call.RateLimitWait = cs.retries, cs.wait
+	cs.mu.Unlock()
+
+	if err != nil {
+		call.Error = err.Error()
+		recordAPICall(call)
+		return nil, err
+	}
+
+	call.Status = resp.StatusCode
+	// Recorded once the caller finishes reading, so Size is the real body size
+	resp.Body = &inspectedBody{ReadCloser: resp.Body, call: call, keepBody: resp.StatusCode >= 400}
+	return resp, nil
+}
+
+// inspectedBody counts response bytes and records the call on EOF or Close
+type inspectedBody struct {
+	io.ReadCloser
+	call     APICall
+	keepBody bool
+	head     []byte
+	once     sync.Once
+}
+
+func (b *inspectedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.call.Size += int64(n)
+	if b.keepBody && len(b.head) < maxErrorBody {
+		b.head = append(b.head, p[:min(n, maxErrorBody-len(b.head))]...)
+	}
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *inspectedBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *inspectedBody) finish() {
+	b.once.Do(func() {
+		if b.keepBody {
+			b.call.Error = apiErrorMessage(b.head)
+		}
+		recordAPICall(b.call)
+	})
+}
+
+// apiErrorMessage extracts the message from a Google API error body
+func apiErrorMessage(body []byte) string {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error.Message != "" {
+		return payload.Error.Message
+	}
+	return ""
+}
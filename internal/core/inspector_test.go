@@ -0,0 +1,42 @@
+package core
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInspectorTransportRecordsCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/denied" {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"error":{"code":403,"message":"Required 'compute.instances.list' permission"}}`)
+			return
+		}
+		io.WriteString(w, `{"items":[]}`)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &InspectorTransport{Next: wrapTransport(http.DefaultTransport), Service: "gce"}}
+	for _, path := range []string{"/instances", "/denied"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	calls := RecentAPICalls()
+	if len(calls) < 2 {
+		t.Fatalf("RecentAPICalls() returned %d calls, want 2", len(calls))
+	}
+	denied, ok := calls[0], calls[1]
+	if denied.Path != "/denied" || denied.Status != http.StatusForbidden || denied.Error != "Required 'compute.instances.list' permission" {
+		t.Errorf("newest call = %+v", denied)
+	}
+	if ok.Service != "gce" || ok.Status != http.StatusOK || ok.Size != int64(len(`{"items":[]}`)) {
+		t.Errorf("first call = %+v", ok)
+	}
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/services"
+)
+
+// ParseLink turns a deep link, as given to --open or saved as a bookmark,
+// into the message that opens it. Links have the forms
+//
+//	gce                            a service's list
+//	sql/orders-db                  a resource, by name or ID
+//	gce/instances/my-vm            a resource of one kind, for services with several
+//	logs?filter=severity>=ERROR    a log query
+//
+// Any link may add project=<id> to open it in another project, and resource
+// links location=<zone or region> to pick between resources with the same
+// name. Query values are URL-encoded.
+func ParseLink(link string) (tea.Msg, error) {
+	path, query, _ := strings.Cut(strings.TrimSpace(link), "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("link %q: %w", link, err)
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	service := parts[0]
+	if service == "" {
+		return nil, fmt.Errorf("link %q: no service", link)
+	}
+	project := params.Get("project")
+
+	if service == "logs" {
+		if len(parts) > 1 {
+			return nil, fmt.Errorf("link %q: logs take a filter, e.g. logs?filter=severity>=ERROR", link)
+		}
+		return SwitchToLogsMsg{Filter: params.Get("filter"), Project: project}, nil
+	}
+
+	ref := services.ResourceRef{Location: params.Get("location")}
+	switch len(parts) {
+	case 1:
+	case 2:
+		ref.Name = parts[1]
+	case 3:
+		ref.Collection, ref.Name = parts[1], parts[2]
+	default:
+		return nil, fmt.Errorf("link %q: expected service[/kind]/name", link)
+	}
+	ref.ID = ref.Name
+	return OpenResourceMsg{Service: service, Project: project, Ref: ref}, nil
+}
+
+// OpenNotFound reports a resource that was asked to be opened but is not
+// in the list that just loaded. The list counts as loaded once every cache
+// key holds a fetched value; until then it may be the copy persisted from
+// an earlier session, and the fetch is still to come. Services whose lists
+// aren't cached pass no keys.
+func OpenNotFound(p *services.PendingOpen, c *Cache, keys ...string) tea.Cmd {
+	if c != nil {
+		for _, key := range keys {
+			if _, found := c.Get(key); !found {
+				return nil
+			}
+		}
+	}
+	ref, ok := p.Missed()
+	if !ok {
+		return nil
+	}
+	name := ref.Name
+	if name == "" {
+		name = ref.ID
+	}
+	toast := ToastMsg{Message: fmt.Sprintf("%q not found", name), Type: ToastError}
+	return func() tea.Msg { return toast }
+}
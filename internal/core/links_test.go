@@ -0,0 +1,80 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/services"
+)
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		link string
+		want tea.Msg
+	}{
+		{"gce", OpenResourceMsg{Service: "gce"}},
+		{"sql/orders-db", OpenResourceMsg{Service: "sql", Ref: services.ResourceRef{ID: "orders-db", Name: "orders-db"}}},
+		{"/gce/instances/web/?location=us-east1-b&project=acme", OpenResourceMsg{
+			Service: "gce",
+			Project: "acme",
+			Ref:     services.ResourceRef{Collection: "instances", ID: "web", Name: "web", Location: "us-east1-b"},
+		}},
+		{"logs?filter=severity>=ERROR%20AND%20resource.type%3D%22gce_instance%22", SwitchToLogsMsg{
+			Filter: `severity>=ERROR AND resource.type="gce_instance"`,
+		}},
+		{"logs?project=acme", SwitchToLogsMsg{Project: "acme"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLink(tt.link)
+		if err != nil {
+			t.Errorf("ParseLink(%q) error = %v", tt.link, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLink(%q) = %#v, want %#v", tt.link, got, tt.want)
+		}
+	}
+
+	for _, link := range []string{"", "?project=acme", "logs/errors", "gce/a/b/c", "gce?project=%zz"} {
+		if _, err := ParseLink(link); err == nil {
+			t.Errorf("ParseLink(%q) expected error", link)
+		}
+	}
+}
+
+func TestAddBookmarks(t *testing.T) {
+	nav := NewNavigation()
+	base := len(nav.BaseCommands)
+	nav.AddBookmarks(map[string]string{"prod-errors": "logs?filter=severity>=ERROR", "db": "sql/orders-db"})
+
+	if len(nav.Commands) != base+2 || nav.Commands[base].Name != "Bookmark: db" {
+		t.Fatalf("commands = %v", nav.Commands[base:])
+	}
+	if route := nav.Commands[base+1].Action(); route.ID != OpenLinkPrefix+"logs?filter=severity>=ERROR" {
+		t.Errorf("bookmark route = %+v", route)
+	}
+}
+
+func TestOpenNotFound(t *testing.T) {
+	c := NewCache()
+	var p services.PendingOpen
+	p.Set(services.ResourceRef{ID: "typo-db", Name: "typo-db"})
+
+	if cmd := OpenNotFound(&p, c, "sql_instances"); cmd != nil {
+		t.Fatal("reported before the list was fetched")
+	}
+	c.Set("sql_instances", []string{"orders-db"}, time.Minute)
+	cmd := OpenNotFound(&p, c, "sql_instances")
+	if cmd == nil {
+		t.Fatal("expected a toast once the list was fetched")
+	}
+	if toast, ok := cmd().(ToastMsg); !ok || toast.Type != ToastError || !strings.Contains(toast.Message, "typo-db") {
+		t.Errorf("toast = %+v", cmd())
+	}
+	if cmd := OpenNotFound(&p, c, "sql_instances"); cmd != nil {
+		t.Error("the request should be dropped once reported")
+	}
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"sort"
+
 	"github.com/sahilm/fuzzy"
+	"github.com/yogirk/tgcp/internal/services"
 )
 
 // ViewType enumerates available views
@@ -13,6 +16,24 @@ const (
 	ViewResourceDetail
 	ViewHelp
 	ViewProjectSwitcher
+	ViewProfileSwitcher
+)
+
+// Route.ID prefixes for palette entries generated by the switchers
+const (
+	SwitchProjectPrefix = "SWITCH_PROJECT:"
+	SwitchProfilePrefix = "SWITCH_PROFILE:"
+	OpenLinkPrefix      = "OPEN_LINK:" // Followed by a deep link, see ParseLink
+)
+
+// Route.IDs of home routes that run an action instead of navigating
+const (
+	RouteClearCache     = "CLEAR_CACHE"      // Drop the active project's cache
+	RouteRateLimitStats = "RATE_LIMIT_STATS" // Show per-host rate limiter waits
+	RouteAPIInspector   = "API_INSPECTOR"    // Open the API call inspector
+	RouteAuditLog       = "AUDIT_LOG"        // Open the local audit trail viewer
+	RouteExportTable    = "EXPORT_TABLE"     // Export the current service table
+	RouteSearch         = "SEARCH"           // Search resources across services
 )
 
 // Route represents a navigational destination
@@ -20,6 +41,25 @@ type Route struct {
 	View    ViewType
 	Service string // e.g., "gce", "sql"
 	ID      string // resource ID or Project ID
+
+	// Set on history entries, to return to where the service was
+	Project  string
+	Location services.Location
+}
+
+// historyLimit caps the number of places kept to go back to
+const historyLimit = 50
+
+// Label names a history entry in the breadcrumb trail, in the form of a
+// deep link, e.g. "gce/web-1"
+func (r Route) Label() string {
+	if r.View == ViewHome || r.Service == "" {
+		return "home"
+	}
+	if name := r.Location.Resource.Name; name != "" {
+		return r.Service + "/" + name
+	}
+	return r.Service
 }
 
 // Command represents an actionable command in the palette
@@ -38,10 +78,14 @@ type SuggestionMatch struct {
 // NavigationModel manages routing and command palette state
 type NavigationModel struct {
 	CurrentRoute Route
-	History      []Route
+	History      []Route // Places to go back to, most recent last
+	Future       []Route // Places to go forward to after going back, next last
 	Commands     []Command
 	BaseCommands []Command // Persist default commands
 
+	// Commands on the resources loaded when the palette opened
+	ResourceCommands []Command
+
 	// Palette State
 	PaletteActive bool
 	Query         string
@@ -64,6 +108,7 @@ func NewNavigation() NavigationModel {
 func defaultCommands() []Command {
 	return []Command{
 		{Name: "GCP: Switch Project", Description: "Switch active Google Cloud Project", Action: func() Route { return Route{View: ViewProjectSwitcher} }},
+		{Name: "Profile: Switch", Description: "Switch to a named profile from ~/.tgcprc", Action: func() Route { return Route{View: ViewProfileSwitcher} }},
 		{Name: "Home", Description: "Go to Home Screen", Action: func() Route { return Route{View: ViewHome} }},
 
 		// Compute
@@ -90,10 +135,72 @@ func defaultCommands() []Command {
 		{Name: "IAM: List Service Accounts", Description: "List IAM Service Accounts", Action: func() Route { return Route{View: ViewServiceList, Service: "iam"} }},
 		{Name: "VPC: List Networks", Description: "List VPC Networks", Action: func() Route { return Route{View: ViewServiceList, Service: "net"} }},
 
+		{Name: "Cache: Clear Project Cache", Description: "Drop cached data for the current project", Action: func() Route { return Route{View: ViewHome, ID: RouteClearCache} }},
+		{Name: "API: Call Inspector", Description: "Inspect recent API calls (Ctrl+g)", Action: func() Route { return Route{View: ViewHome, ID: RouteAPIInspector} }},
+		{Name: "Audit: Action History", Description: "Show start/stop and other actions from ~/.tgcp/audit.jsonl", Action: func() Route { return Route{View: ViewHome, ID: RouteAuditLog} }},
+		{Name: "Search: Resources", Description: "Find resources by name, IP or label across services", Action: func() Route { return Route{View: ViewHome, ID: RouteSearch} }},
+		{Name: "Export: Current Table", Description: "Save the visible table as CSV, JSON or Markdown (Ctrl+e)", Action: func() Route { return Route{View: ViewHome, ID: RouteExportTable} }},
+		{Name: "API: Rate Limit Stats", Description: "Show rate limiter wait time per API host", Action: func() Route { return Route{View: ViewHome, ID: RouteRateLimitStats} }},
 		{Name: "Help", Description: "Show Help Screen", Action: func() Route { return Route{View: ViewHelp} }},
 	}
 }
 
+// Push records the place being left for another view. It starts a new
+// branch of history, dropping the places to go forward to.
+func (m *NavigationModel) Push(from Route) {
+	m.Future = nil
+	if n := len(m.History); n > 0 && m.History[n-1] == from {
+		return
+	}
+	m.History = append(m.History, from)
+	if len(m.History) > historyLimit {
+		m.History = m.History[len(m.History)-historyLimit:]
+	}
+}
+
+// Back returns the previous place, keeping current to go forward to
+func (m *NavigationModel) Back(current Route) (Route, bool) {
+	n := len(m.History)
+	if n == 0 {
+		return Route{}, false
+	}
+	to := m.History[n-1]
+	m.History = m.History[:n-1]
+	m.Future = append(m.Future, current)
+	return to, true
+}
+
+// Forward returns the place left by going back, keeping current to go back to
+func (m *NavigationModel) Forward(current Route) (Route, bool) {
+	n := len(m.Future)
+	if n == 0 {
+		return Route{}, false
+	}
+	to := m.Future[n-1]
+	m.Future = m.Future[:n-1]
+	m.History = append(m.History, current)
+	return to, true
+}
+
+// AddBookmarks adds a palette command for each bookmark, sorted by name
+func (m *NavigationModel) AddBookmarks(bookmarks map[string]string) {
+	names := make([]string, 0, len(bookmarks))
+	for name := range bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		id := OpenLinkPrefix + bookmarks[name]
+		m.BaseCommands = append(m.BaseCommands, Command{
+			Name:        "Bookmark: " + name,
+			Description: "Open " + bookmarks[name],
+			Action:      func() Route { return Route{View: ViewHome, ID: id} },
+		})
+	}
+	m.Commands = m.BaseCommands
+}
+
 // SetCommands updates the available commands (e.g. for switching context)
 func (m *NavigationModel) SetCommands(cmds []Command) {
 	m.Commands = cmds
@@ -103,11 +210,18 @@ func (m *NavigationModel) SetCommands(cmds []Command) {
 
 // RestoreBaseCommands resets to default commands
 func (m *NavigationModel) RestoreBaseCommands() {
-	m.Commands = m.BaseCommands
+	m.Commands = append(m.BaseCommands[:len(m.BaseCommands):len(m.BaseCommands)], m.ResourceCommands...)
 	m.Selection = 0
 	m.FilterCommands("")
 }
 
+// SetResourceCommands replaces the commands on resources offered next to
+// the default ones
+func (m *NavigationModel) SetResourceCommands(cmds []Command) {
+	m.ResourceCommands = cmds
+	m.RestoreBaseCommands()
+}
+
 // FilterCommands updates suggestions based on input query
 func (m *NavigationModel) FilterCommands(query string) {
 	m.Query = query
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
+func TestNavigationHistory(t *testing.T) {
+	home := Route{View: ViewHome}
+	vm := Route{View: ViewServiceList, Service: "gce", Location: services.Location{Resource: services.ResourceRef{ID: "1", Name: "web-1"}}}
+	logs := Route{View: ViewServiceList, Service: "logs", Location: services.Location{Filter: "severity>=ERROR"}}
+	gke := Route{View: ViewServiceList, Service: "gke"}
+
+	var m NavigationModel
+	m.Push(home)
+	m.Push(vm)
+	m.Push(vm) // Same place twice is kept once
+	m.Push(logs)
+	if len(m.History) != 3 {
+		t.Fatalf("History = %v, want 3 places", m.History)
+	}
+
+	// At gke: back to logs, then the VM
+	if to, ok := m.Back(gke); !ok || to != logs {
+		t.Fatalf("Back = %v, %v, want logs", to, ok)
+	}
+	if to, ok := m.Back(logs); !ok || to != vm {
+		t.Fatalf("Back = %v, %v, want vm", to, ok)
+	}
+	if to, ok := m.Forward(vm); !ok || to != logs {
+		t.Fatalf("Forward = %v, %v, want logs", to, ok)
+	}
+	if got := len(m.Future); got != 1 {
+		t.Fatalf("Future = %v, want gke", m.Future)
+	}
+
+	// A new jump drops the places to go forward to
+	m.Push(logs)
+	if _, ok := m.Forward(home); ok {
+		t.Error("Forward after Push should have nowhere to go")
+	}
+
+	if got := vm.Label(); got != "gce/web-1" {
+		t.Errorf("Label = %q, want gce/web-1", got)
+	}
+	if got := home.Label(); got != "home" {
+		t.Errorf("Label = %q, want home", got)
+	}
+}
+
+func TestNavigationHistoryLimit(t *testing.T) {
+	var m NavigationModel
+	for i := 0; i < historyLimit+10; i++ {
+		m.Push(Route{View: ViewServiceList, Service: "gce", ID: strconv.Itoa(i)})
+	}
+	if len(m.History) != historyLimit {
+		t.Errorf("len(History) = %d, want %d", len(m.History), historyLimit)
+	}
+}
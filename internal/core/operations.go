@@ -0,0 +1,209 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/api/compute/v1"
+)
+
+// Long-running Operation Tracker
+//
+// Start/stop style API calls return as soon as the API has accepted the
+// request. The services hand the returned operation to TrackOperation with a
+// poller for their API (compute zone operations, sqladmin operations, ...).
+// The tracker polls in the background until the operation is done and then
+// emits an OperationDoneMsg, which the UI turns into a toast and a refresh
+// of the affected service.
+
+const (
+	operationPollInterval = 2 * time.Second
+	operationTimeout      = 30 * time.Minute
+	maxPollFailures       = 5 // Consecutive failed polls before giving up
+)
+
+// OperationPoller checks an operation once. It returns done=true when the
+// operation has finished; opErr is the operation's own failure, if any.
+// A non-nil err means the status could not be read and the poll is retried.
+type OperationPoller func(ctx context.Context) (done bool, opErr error, err error)
+
+// StatusPoller builds a poller for APIs whose operations report a status,
+// "DONE" once finished, and a list of errors: get returns the status and the
+// first error message, "" for none
+func StatusPoller(get func(ctx context.Context) (status, opErr string, err error)) OperationPoller {
+	return func(ctx context.Context) (bool, error, error) {
+		status, opErr, err := get(ctx)
+		if err != nil {
+			return false, nil, err
+		}
+		if status != "DONE" {
+			return false, nil, nil
+		}
+		if opErr != "" {
+			return true, errors.New(opErr), nil
+		}
+		return true, nil, nil
+	}
+}
+
+// ZoneOperationPoller follows a Compute Engine zone operation, e.g. an
+// instance start or a disk snapshot
+func ZoneOperationPoller(svc *compute.Service, projectID, zone, name string) OperationPoller {
+	return StatusPoller(func(ctx context.Context) (string, string, error) {
+		op, err := svc.ZoneOperations.Get(projectID, zone, name).Context(ctx).Do()
+		if err != nil {
+			return "", "", err
+		}
+		if op.Error != nil && len(op.Error.Errors) > 0 {
+			return op.Status, op.Error.Errors[0].Message, nil
+		}
+		return op.Status, "", nil
+	})
+}
+
+// Operation is a long-running operation being tracked
+type Operation struct {
+	ID          string // Operation name as returned by the API
+	Service     string // Service short name, e.g. "gce"
+	Project     string
+	Resource    string // e.g. "web-1"
+	Description string // e.g. "Stopping instance web-1"
+	Started     time.Time
+	Finished    time.Time
+	Err         error  // Set when the operation failed or could not be followed
+	Bulk        string // ID of the bulk action the operation belongs to, if any
+}
+
+// Elapsed returns how long the operation has been running, or ran for
+func (o Operation) Elapsed() time.Duration {
+	if !o.Finished.IsZero() {
+		return o.Finished.Sub(o.Started)
+	}
+	return time.Since(o.Started)
+}
+
+// OperationPendingMsg re-arms the poll timer of an unfinished operation
+type OperationPendingMsg struct {
+	Key string
+}
+
+// OperationDoneMsg reports a finished (or abandoned) operation
+type OperationDoneMsg struct {
+	Op Operation
+}
+
+type trackedOperation struct {
+	Operation
+	poll     OperationPoller
+	failures int
+}
+
+var operations struct {
+	mu  sync.Mutex
+	ops map[string]*trackedOperation
+}
+
+// TrackOperation starts polling an operation and returns the command that
+// drives it. Operations without an ID (APIs that complete synchronously)
+// are not tracked.
+func TrackOperation(op Operation, poll OperationPoller) tea.Cmd {
+	if op.ID == "" || poll == nil {
+		return nil
+	}
+	if op.Started.IsZero() {
+		op.Started = time.Now()
+	}
+
+	operations.mu.Lock()
+	if operations.ops == nil {
+		operations.ops = make(map[string]*trackedOperation)
+	}
+	key := op.Service + "/" + op.ID
+	operations.ops[key] = &trackedOperation{Operation: op, poll: poll}
+	operations.mu.Unlock()
+
+	return pollOperationCmd(key)
+}
+
+// ContinuePolling schedules the next poll of a pending operation
+func ContinuePolling(msg OperationPendingMsg) tea.Cmd {
+	return pollOperationCmd(msg.Key)
+}
+
+// PendingOperations returns the unfinished operations, oldest first
+func PendingOperations() []Operation {
+	operations.mu.Lock()
+	defer operations.mu.Unlock()
+
+	out := make([]Operation, 0, len(operations.ops))
+	for _, t := range operations.ops {
+		out = append(out, t.Operation)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Started.Before(out[j].Started) })
+	return out
+}
+
+func pollOperationCmd(key string) tea.Cmd {
+	return tea.Tick(operationPollInterval, func(time.Time) tea.Msg {
+		return pollOperation(key)
+	})
+}
+
+// pollOperation runs one poll and decides whether to keep going
+func pollOperation(key string) tea.Msg {
+	operations.mu.Lock()
+	t, ok := operations.ops[key]
+	operations.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	done, opErr, err := t.poll(ctx)
+	cancel()
+
+	operations.mu.Lock()
+	defer operations.mu.Unlock()
+
+	switch {
+	case err != nil:
+		t.failures++
+		if t.failures < maxPollFailures {
+			return OperationPendingMsg{Key: key}
+		}
+		t.Err = fmt.Errorf("lost track of operation: %w", err)
+	case done:
+		t.Err = opErr
+	case time.Since(t.Started) > operationTimeout:
+		t.Err = fmt.Errorf("still running after %s", operationTimeout)
+	default:
+		t.failures = 0
+		return OperationPendingMsg{Key: key}
+	}
+
+	t.Finished = time.Now()
+	delete(operations.ops, key)
+	return OperationDoneMsg{Op: t.Operation}
+}
+
+// OperationToast describes a finished operation for a toast notification
+func OperationToast(op Operation) ToastMsg {
+	elapsed := op.Elapsed().Round(time.Second)
+	if op.Err != nil {
+		return ToastMsg{
+			Message:  fmt.Sprintf("%s failed after %s: %v", op.Description, elapsed, op.Err),
+			Type:     ToastError,
+			Duration: 8 * time.Second,
+		}
+	}
+	return ToastMsg{
+		Message:  fmt.Sprintf("%s: done in %s", op.Description, elapsed),
+		Type:     ToastSuccess,
+		Duration: 5 * time.Second,
+	}
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPollOperation(t *testing.T) {
+	polls := 0
+	poll := func(ctx context.Context) (bool, error, error) {
+		polls++
+		switch polls {
+		case 1:
+			return false, nil, nil
+		case 2:
+			return false, nil, errors.New("503 backend unavailable")
+		default:
+			return true, errors.New("ZONE_RESOURCE_POOL_EXHAUSTED"), nil
+		}
+	}
+
+	if cmd := TrackOperation(Operation{Service: "gce"}, poll); cmd != nil {
+		t.Error("TrackOperation() without an ID should not track")
+	}
+	if cmd := TrackOperation(Operation{ID: "operation-1", Service: "gce", Description: "Starting instance web-1"}, poll); cmd == nil {
+		t.Fatal("TrackOperation() returned nil cmd")
+	}
+	if n := len(PendingOperations()); n != 1 {
+		t.Fatalf("PendingOperations() = %d, want 1", n)
+	}
+
+	// Pending, then a failed poll that is retried, then done with an error
+	for i := 0; i < 2; i++ {
+		if _, ok := pollOperation("gce/operation-1").(OperationPendingMsg); !ok {
+			t.Fatalf("poll %d: expected OperationPendingMsg", i+1)
+		}
+	}
+	done, ok := pollOperation("gce/operation-1").(OperationDoneMsg)
+	if !ok {
+		t.Fatal("poll 3: expected OperationDoneMsg")
+	}
+	if done.Op.Err == nil || done.Op.Finished.IsZero() {
+		t.Errorf("finished operation = %+v", done.Op)
+	}
+	if OperationToast(done.Op).Type != ToastError {
+		t.Error("OperationToast() for a failed operation should be an error")
+	}
+	if n := len(PendingOperations()); n != 0 {
+		t.Errorf("PendingOperations() after completion = %d, want 0", n)
+	}
+}
+
+func TestStatusPoller(t *testing.T) {
+	tests := []struct {
+		status, opErr string
+		err           error
+		wantDone      bool
+		wantOpErr     bool
+		wantErr       bool
+	}{
+		{status: "RUNNING", wantDone: false},
+		{status: "DONE", wantDone: true},
+		{status: "DONE", opErr: "quota exceeded", wantDone: true, wantOpErr: true},
+		{err: errors.New("timeout"), wantErr: true},
+	}
+	for _, tt := range tests {
+		poll := StatusPoller(func(ctx context.Context) (string, string, error) { return tt.status, tt.opErr, tt.err })
+		done, opErr, err := poll(context.Background())
+		if done != tt.wantDone || (opErr != nil) != tt.wantOpErr || (err != nil) != tt.wantErr {
+			t.Errorf("%+v: got done=%v opErr=%v err=%v", tt, done, opErr, err)
+		}
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/yogirk/tgcp/internal/utils"
 	"google.golang.org/api/cloudresourcemanager/v1"
-	"google.golang.org/api/option"
 )
 
 // Project represents a GCP project
@@ -38,7 +37,11 @@ func (pm *ProjectManager) ListProjects(ctx context.Context) ([]Project, error) {
 	}
 
 	utils.Log("Fetching projects via Cloud Resource Manager API...")
-	svc, err := cloudresourcemanager.NewService(ctx, option.WithScopes(cloudresourcemanager.CloudPlatformScope))
+	opts, err := ClientOptions(ctx, "projects", cloudresourcemanager.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := cloudresourcemanager.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource manager client: %w", err)
 	}
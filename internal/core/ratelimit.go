@@ -0,0 +1,248 @@
+package core
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+// TokenBucket implements a token bucket rate limiting algorithm.
+// It allows a certain number of requests per second (rate) with a burst capacity.
+//
+// Algorithm:
+//   - Tokens are added to the bucket at a constant rate
+//   - Each request consumes one token
+//   - If tokens are available, the request proceeds immediately
+//   - If no tokens are available, the request waits until tokens are refilled
+//
+// Thread-safe: Uses mutex to protect concurrent access to token state.
+type TokenBucket struct {
+	rate       float64 // tokens per second
+	burst      float64 // maximum tokens (burst capacity)
+	tokens     float64 // current number of tokens
+	lastRefill time.Time
+	mu         sync.Mutex
+}
+
+// NewTokenBucket creates a new token bucket rate limiter.
+//
+// Parameters:
+//   - rate: Tokens added per second (e.g., 10.0 = 10 requests/second)
+//   - burst: Maximum tokens (e.g., 20.0 = can handle 20 requests in quick succession)
+//
+// Example:
+//
+//	limiter := NewTokenBucket(10.0, 20.0) // 10 req/s, burst of 20
+func NewTokenBucket(rate, burst float64) *TokenBucket {
+	return &TokenBucket{
+		rate:       rate,
+		burst:      burst,
+		tokens:     burst,
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is cancelled.
+// It automatically refills tokens based on elapsed time since last refill.
+//
+// Returns:
+//   - nil if a token was successfully acquired
+//   - context.Err() if the context was cancelled
+//
+// Example:
+//
+//	if err := limiter.Wait(ctx); err != nil {
+//	    return err // Context cancelled
+//	}
+//	// Token acquired, proceed with request
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	for {
+		now := time.Now()
+		// Refill
+		elapsed := now.Sub(tb.lastRefill).Seconds()
+		tb.tokens = math.Min(tb.burst, tb.tokens+(elapsed*tb.rate))
+		tb.lastRefill = now
+
+		if tb.tokens >= 1.0 {
+			tb.tokens -= 1.0
+			return nil
+		}
+
+		// Wait for enough tokens
+		missing := 1.0 - tb.tokens
+		waitTime := time.Duration((missing / tb.rate) * float64(time.Second))
+
+		// Unlock to wait
+		tb.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			tb.mu.Lock() // Re-lock just to defer unlock safely, though we return error
+			return ctx.Err()
+		case <-time.After(waitTime):
+			// Continue loop to re-check/claim
+		}
+		tb.mu.Lock()
+	}
+}
+
+// --- Per-Host Limiter Registry ---
+
+// Default limits for hosts without an entry in ~/.tgcprc
+const (
+	defaultHostRate  = 10.0
+	defaultHostBurst = 20.0
+)
+
+// defaultLimitKey is the rate_limits entry applied to unlisted hosts
+const defaultLimitKey = "default"
+
+// HostLimiterStats is a snapshot of one host's limiter
+type HostLimiterStats struct {
+	Host      string
+	Rate      float64
+	Burst     float64
+	Requests  int64
+	Waits     int64 // Requests that had to wait for a token
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// AvgWait returns the mean wait across requests that waited
+func (s HostLimiterStats) AvgWait() time.Duration {
+	if s.Waits == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Waits)
+}
+
+// hostLimiter pairs a shared bucket with its wait statistics
+type hostLimiter struct {
+	bucket *TokenBucket
+
+	mu    sync.Mutex
+	stats HostLimiterStats
+}
+
+func (h *hostLimiter) record(wait time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stats.Requests++
+	// Sub-millisecond waits are just lock contention, not throttling
+	if wait >= time.Millisecond {
+		h.stats.Waits++
+		h.stats.TotalWait += wait
+		if wait > h.stats.MaxWait {
+			h.stats.MaxWait = wait
+		}
+	}
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*hostLimiter)
+	hostLimits = make(map[string]config.RateLimitConfig)
+)
+
+// SetRateLimits installs per-host limits from ~/.tgcprc, keyed by API host
+// (e.g. "compute.googleapis.com") or "default". Existing limiters are reset.
+func SetRateLimits(limits map[string]config.RateLimitConfig) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	hostLimits = make(map[string]config.RateLimitConfig, len(limits))
+	for host, l := range limits {
+		hostLimits[strings.ToLower(host)] = l
+	}
+	limiters = make(map[string]*hostLimiter)
+}
+
+// limiterFor returns the process-wide limiter for host, creating it on first use
+func limiterFor(host string) *hostLimiter {
+	host = strings.ToLower(host)
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	if l, ok := limiters[host]; ok {
+		return l
+	}
+
+	rate, burst := defaultHostRate, defaultHostBurst
+	for _, key := range []string{defaultLimitKey, host} {
+		if cfg, ok := hostLimits[key]; ok {
+			if cfg.RPS > 0 {
+				rate = cfg.RPS
+			}
+			if cfg.Burst > 0 {
+				burst = float64(cfg.Burst)
+			}
+		}
+	}
+
+	l := &hostLimiter{
+		bucket: NewTokenBucket(rate, burst),
+		stats:  HostLimiterStats{Host: host, Rate: rate, Burst: burst},
+	}
+	limiters[host] = l
+	return l
+}
+
+// RateLimitStats returns a snapshot of every host limiter, busiest first
+func RateLimitStats() []HostLimiterStats {
+	limitersMu.Lock()
+	all := make([]*hostLimiter, 0, len(limiters))
+	for _, l := range limiters {
+		all = append(all, l)
+	}
+	limitersMu.Unlock()
+
+	stats := make([]HostLimiterStats, 0, len(all))
+	for _, l := range all {
+		l.mu.Lock()
+		stats = append(stats, l.stats)
+		l.mu.Unlock()
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalWait != stats[j].TotalWait {
+			return stats[i].TotalWait > stats[j].TotalWait
+		}
+		return stats[i].Host < stats[j].Host
+	})
+	return stats
+}
+
+// RateLimitTransport waits for a token before forwarding each request.
+// When Limiter is nil, the process-wide bucket for the request's host is used,
+// so every client talking to the same API shares one quota.
+type RateLimitTransport struct {
+	Next    http.RoundTripper
+	Limiter *TokenBucket
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Limiter != nil {
+		if err := t.Limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		return t.Next.RoundTrip(req)
+	}
+
+	limiter := limiterFor(req.URL.Host)
+	start := time.Now()
+	if err := limiter.bucket.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	wait := time.Since(start)
+	limiter.record(wait)
+	callStatsFrom(req.Context()).addWait(wait)
+	return t.Next.RoundTrip(req)
+}
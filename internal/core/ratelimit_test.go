@@ -0,0 +1,34 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func TestLimiterForSharesPerHost(t *testing.T) {
+	SetRateLimits(map[string]config.RateLimitConfig{
+		"default":                {RPS: 5},
+		"logging.googleapis.com": {RPS: 2, Burst: 4},
+	})
+	defer SetRateLimits(nil)
+
+	if limiterFor("compute.googleapis.com") != limiterFor("Compute.googleapis.com") {
+		t.Error("limiterFor returned different limiters for the same host")
+	}
+
+	tests := []struct {
+		host      string
+		wantRate  float64
+		wantBurst float64
+	}{
+		{"compute.googleapis.com", 5, defaultHostBurst},
+		{"logging.googleapis.com", 2, 4},
+	}
+	for _, tt := range tests {
+		l := limiterFor(tt.host)
+		if l.stats.Rate != tt.wantRate || l.stats.Burst != tt.wantBurst {
+			t.Errorf("limiterFor(%q) = %v/%v, want %v/%v", tt.host, l.stats.Rate, l.stats.Burst, tt.wantRate, tt.wantBurst)
+		}
+	}
+}
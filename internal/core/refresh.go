@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+// RefreshMode is how a service refreshes in the background, switched at
+// runtime with keys.RefreshMode
+type RefreshMode int
+
+const (
+	RefreshAuto   RefreshMode = iota // Every refresh interval
+	RefreshWatch                     // Every WatchInterval, e.g. while a VM boots
+	RefreshPaused                    // Only on demand
+)
+
+// WatchInterval is the refresh cadence in watch mode
+const WatchInterval = 2 * time.Second
+
+// DefaultRefreshInterval is the cadence of a service without a default of
+// its own while ui.refresh_interval is unset
+const DefaultRefreshInterval = 30 * time.Second
+
+// refreshClockInterval is how often the schedule checks for a due refresh
+const refreshClockInterval = time.Second
+
+// RefreshClockMsg drives the RefreshSchedule
+type RefreshClockMsg time.Time
+
+// RefreshTickMsg tells the service on screen to reload its data in the
+// background: from the API, bypassing the cache, without a loading spinner
+type RefreshTickMsg struct{}
+
+// RefreshClock ticks once, see RefreshSchedule.Due
+func RefreshClock() tea.Cmd {
+	return tea.Tick(refreshClockInterval, func(t time.Time) tea.Msg {
+		return RefreshClockMsg(t)
+	})
+}
+
+// RefreshSchedule decides when the service on screen reloads its data: at
+// its entry in ui.refresh_intervals, or else every ui.refresh_interval
+// seconds, unless switched to watch mode or paused. While refresh_interval
+// is unset each service uses its own default cadence. Services off screen
+// don't refresh.
+type RefreshSchedule struct {
+	global    time.Duration
+	globalSet bool // Whether ui.refresh_interval is set, 0 included
+	defaults  map[string]time.Duration
+	overrides map[string]time.Duration
+	modes     map[string]RefreshMode
+
+	service string    // Service on screen at the last clock tick
+	last    time.Time // Its last refresh, or when it came on screen
+}
+
+// NewRefreshSchedule creates a schedule from the UI configuration and the
+// default cadence of each service, keyed by short name
+func NewRefreshSchedule(ui config.UIConfig, defaults map[string]time.Duration) RefreshSchedule {
+	r := RefreshSchedule{defaults: defaults, modes: make(map[string]RefreshMode)}
+	r.Configure(ui)
+	return r
+}
+
+// Configure applies the refresh intervals of a configuration, e.g. after a
+// profile switch, keeping the modes set at runtime
+func (r *RefreshSchedule) Configure(ui config.UIConfig) {
+	r.global, r.globalSet = DefaultRefreshInterval, ui.RefreshInterval != nil
+	if r.globalSet {
+		r.global = time.Duration(*ui.RefreshInterval) * time.Second
+	}
+	r.overrides = make(map[string]time.Duration, len(ui.RefreshIntervals))
+	for service, seconds := range ui.RefreshIntervals {
+		r.overrides[service] = time.Duration(seconds) * time.Second
+	}
+}
+
+// Interval returns how often a service refreshes, 0 for never
+func (r RefreshSchedule) Interval(service string) time.Duration {
+	switch r.modes[service] {
+	case RefreshWatch:
+		return WatchInterval
+	case RefreshPaused:
+		return 0
+	}
+	if d, ok := r.overrides[service]; ok {
+		return d
+	}
+	if r.globalSet {
+		return r.global
+	}
+	if d, ok := r.defaults[service]; ok {
+		return d
+	}
+	return r.global
+}
+
+// Mode returns the refresh mode of a service
+func (r RefreshSchedule) Mode(service string) RefreshMode {
+	return r.modes[service]
+}
+
+// Cycle switches a service from auto-refresh to watch mode, then to paused,
+// then back, and returns the new mode. The new cadence starts with a
+// refresh at the next clock tick.
+func (r *RefreshSchedule) Cycle(service string) RefreshMode {
+	mode := (r.modes[service] + 1) % 3
+	if mode == RefreshAuto {
+		delete(r.modes, service)
+	} else {
+		r.modes[service] = mode
+	}
+	if service == r.service {
+		r.last = time.Time{}
+	}
+	return mode
+}
+
+// Due is called on every clock tick with the service on screen, "" for
+// none, and reports whether it should refresh now. A service that just came
+// on screen has been refreshed when it was opened, so its interval starts.
+func (r *RefreshSchedule) Due(service string, now time.Time) bool {
+	if service != r.service {
+		r.service, r.last = service, now
+		return false
+	}
+	d := r.Interval(service)
+	if service == "" || d <= 0 || now.Sub(r.last) < d {
+		return false
+	}
+	r.last = now
+	return true
+}
+
+// Label describes the cadence of a service for the status bar
+func (r RefreshSchedule) Label(service string) string {
+	switch r.modes[service] {
+	case RefreshWatch:
+		return "WATCH " + FormatInterval(WatchInterval)
+	case RefreshPaused:
+		return "PAUSED"
+	}
+	d := r.Interval(service)
+	if d <= 0 {
+		return "auto-refresh off"
+	}
+	return "every " + FormatInterval(d)
+}
+
+// FormatInterval renders an interval compactly, e.g. "30s", "5m" or "1m30s"
+func FormatInterval(d time.Duration) string {
+	m, s := int(d/time.Minute), int(d%time.Minute/time.Second)
+	switch {
+	case m == 0:
+		return fmt.Sprintf("%ds", s)
+	case s == 0:
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dm%ds", m, s)
+}
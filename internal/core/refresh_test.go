@@ -0,0 +1,103 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func TestRefreshSchedule(t *testing.T) {
+	r := NewRefreshSchedule(config.UIConfig{
+		RefreshIntervals: map[string]int{"gcs": 300, "iam": 0},
+	}, map[string]time.Duration{"gce": 30 * time.Second, "logs": 10 * time.Second, "iam": time.Minute})
+
+	for service, want := range map[string]time.Duration{
+		"gce":  30 * time.Second,       // Default
+		"logs": 10 * time.Second,       // Default
+		"gcs":  5 * time.Minute,        // Configured
+		"iam":  0,                      // Configured over the default
+		"new":  DefaultRefreshInterval, // No default
+	} {
+		if got := r.Interval(service); got != want {
+			t.Errorf("Interval(%q) = %v, want %v", service, got, want)
+		}
+	}
+
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	// The interval starts when a service comes on screen
+	if r.Due("gce", at(0)) {
+		t.Error("Due on the first tick for gce")
+	}
+	if r.Due("gce", at(29*time.Second)) {
+		t.Error("Due before the interval")
+	}
+	if !r.Due("gce", at(30*time.Second)) {
+		t.Error("not Due after the interval")
+	}
+	if r.Due("gce", at(31*time.Second)) {
+		t.Error("Due right after a refresh")
+	}
+
+	// Watch mode refreshes at the next tick, then every WatchInterval
+	if mode := r.Cycle("gce"); mode != RefreshWatch {
+		t.Fatalf("Cycle = %v, want RefreshWatch", mode)
+	}
+	if !r.Due("gce", at(32*time.Second)) || r.Due("gce", at(33*time.Second)) || !r.Due("gce", at(34*time.Second)) {
+		t.Error("watch mode should refresh every 2s")
+	}
+	if got := r.Label("gce"); got != "WATCH 2s" {
+		t.Errorf("Label = %q, want WATCH 2s", got)
+	}
+
+	// Paused never refreshes
+	if mode := r.Cycle("gce"); mode != RefreshPaused {
+		t.Fatalf("Cycle = %v, want RefreshPaused", mode)
+	}
+	if r.Due("gce", at(time.Hour)) {
+		t.Error("Due while paused")
+	}
+
+	// Modes are per service and survive a configuration change, and a set
+	// refresh_interval applies over the services' defaults
+	minute := 60
+	r.Configure(config.UIConfig{RefreshInterval: &minute})
+	if r.Mode("gce") != RefreshPaused || r.Interval("gcs") != time.Minute || r.Interval("logs") != time.Minute {
+		t.Errorf("after Configure: gce mode %v, gcs interval %v, logs interval %v", r.Mode("gce"), r.Interval("gcs"), r.Interval("logs"))
+	}
+	if mode := r.Cycle("gce"); mode != RefreshAuto {
+		t.Fatalf("Cycle = %v, want RefreshAuto", mode)
+	}
+	if got := r.Label("gce"); got != "every 1m" {
+		t.Errorf("Label = %q, want every 1m", got)
+	}
+
+	// refresh_interval: 0 turns off every service not listed
+	off := 0
+	r.Configure(config.UIConfig{RefreshInterval: &off, RefreshIntervals: map[string]int{"logs": 5}})
+	if r.Interval("gce") != 0 || r.Interval("logs") != 5*time.Second {
+		t.Errorf("all off: gce interval %v, logs interval %v", r.Interval("gce"), r.Interval("logs"))
+	}
+	if got := r.Label("gce"); got != "auto-refresh off" {
+		t.Errorf("Label = %q, want auto-refresh off", got)
+	}
+
+	// Nothing refreshes off screen
+	if r.Due("", at(2*time.Hour)) || r.Due("", at(3*time.Hour)) {
+		t.Error("Due with no service on screen")
+	}
+}
+
+func TestFormatInterval(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		2 * time.Second:  "2s",
+		5 * time.Minute:  "5m",
+		90 * time.Second: "1m30s",
+	} {
+		if got := FormatInterval(d); got != want {
+			t.Errorf("FormatInterval(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
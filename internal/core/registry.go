@@ -18,6 +18,7 @@ type ServiceRegistry struct {
 	initialized    map[string]string            // Maps service name to projectID it was initialized with
 	mu             sync.RWMutex                 // Protects services map and initialized map
 	projectID      string                       // Current project ID for lazy initialization
+	region, zone   string                       // Default location passed to services.Regional
 }
 
 // NewServiceRegistry creates a new service registry
@@ -42,6 +43,7 @@ func (r *ServiceRegistry) InitializeAll(ctx context.Context, projectID string) m
 	defer r.mu.Unlock()
 	
 	r.projectID = projectID
+	r.cache.SetProject(projectID)
 	svcMap := make(map[string]services.Service)
 	
 	// Create service instances but don't initialize them yet
@@ -54,6 +56,24 @@ func (r *ServiceRegistry) InitializeAll(ctx context.Context, projectID string) m
 	return svcMap
 }
 
+// SetLocation sets the default region and zone of services, e.g. the active
+// profile's. Services pick them up when next initialized or reinitialized.
+func (r *ServiceRegistry) SetLocation(region, zone string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.region, r.zone = region, zone
+}
+
+// applyLocation passes the default location to a service that uses one
+func (r *ServiceRegistry) applyLocation(svc services.Service) {
+	if regional, ok := svc.(services.Regional); ok {
+		r.mu.RLock()
+		region, zone := r.region, r.zone
+		r.mu.RUnlock()
+		regional.SetLocation(region, zone)
+	}
+}
+
 // GetOrInitializeService gets a service from the map, initializing it lazily if needed
 // This is the key method for lazy initialization - services are only initialized when first accessed
 func (r *ServiceRegistry) GetOrInitializeService(ctx context.Context, name string) (services.Service, error) {
@@ -85,6 +105,7 @@ func (r *ServiceRegistry) GetOrInitializeService(ctx context.Context, name strin
 		// If service was initialized with a different project ID, we need to reinit
 		if isInitialized && initProjectID != projectID {
 			// Project changed - use Reinit to properly reset and reinitialize
+			r.applyLocation(svc)
 			if err := svc.Reinit(ctx, projectID); err != nil {
 				return svc, err
 			}
@@ -94,6 +115,7 @@ func (r *ServiceRegistry) GetOrInitializeService(ctx context.Context, name strin
 			r.mu.Unlock()
 		} else if !isInitialized {
 			// Service not yet initialized - initialize it
+			r.applyLocation(svc)
 			if err := svc.InitService(ctx, projectID); err != nil {
 				return svc, err // Return service even if init fails, let caller handle error
 			}
@@ -112,6 +134,7 @@ func (r *ServiceRegistry) GetOrInitializeService(ctx context.Context, name strin
 func (r *ServiceRegistry) ReinitializeAll(ctx context.Context, projectID string, svcMap map[string]services.Service) {
 	r.mu.Lock()
 	r.projectID = projectID
+	r.cache.SetProject(projectID)
 	// Clear initialization tracking - services will be reinitialized on next access
 	// or we can reinit them now if they're already in the map
 	r.mu.Unlock()
@@ -125,6 +148,7 @@ func (r *ServiceRegistry) ReinitializeAll(ctx context.Context, projectID string,
 		
 		if wasInitialized {
 			// Use the new Reinit() method for cleaner project switching
+			r.applyLocation(svc)
 			if err := svc.Reinit(ctx, projectID); err != nil {
 				// Log error but continue with other services
 				// The service will remain in its previous state if reinit fails
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
+// regionalService records the location it was initialized with
+type regionalService struct {
+	services.Service
+	region, zone string
+	initRegion   string // Region at the last InitService or Reinit
+}
+
+func (s *regionalService) SetLocation(region, zone string) { s.region, s.zone = region, zone }
+
+func (s *regionalService) InitService(ctx context.Context, projectID string) error {
+	s.initRegion = s.region
+	return nil
+}
+
+func (s *regionalService) Reinit(ctx context.Context, projectID string) error {
+	return s.InitService(ctx, projectID)
+}
+
+func TestRegistryPassesLocation(t *testing.T) {
+	r := NewServiceRegistry(NewCache())
+	r.Register("dataproc", func(*Cache) services.Service { return &regionalService{} })
+	r.SetLocation("us-central1", "us-central1-a")
+	svcMap := r.InitializeAll(context.Background(), "acme")
+
+	svc, err := r.GetOrInitializeService(context.Background(), "dataproc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.(*regionalService); got.initRegion != "us-central1" || got.zone != "us-central1-a" {
+		t.Errorf("initialized with %q/%q, want the registry's location", got.initRegion, got.zone)
+	}
+
+	// A profile switch sets a new location before reinitializing
+	r.SetLocation("europe-west1", "")
+	r.ReinitializeAll(context.Background(), "acme-prod", svcMap)
+	if got := svc.(*regionalService); got.initRegion != "europe-west1" || got.zone != "" {
+		t.Errorf("reinitialized with %q/%q, want europe-west1", got.initRegion, got.zone)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
+// BuildResourceCommands returns palette commands for the resources the
+// services have loaded: "Open <kind>: <name>" for the services that can
+// open their resources, and the actions of services.CommandProvider
+// services, e.g. "SSH: web-01". Their routes have View ViewResourceDetail,
+// the action as ID and the resource as Location.
+func BuildResourceCommands(svcs map[string]services.Service) []Command {
+	names := make([]string, 0, len(svcs))
+	for name := range svcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var cmds []Command
+	for _, name := range names {
+		svc := svcs[name]
+		provider, canList := svc.(services.ResourceProvider)
+		_, canOpen := svc.(services.ResourceOpener)
+		if canList && canOpen {
+			for _, r := range provider.Resources() {
+				m := r.Meta()
+				cmds = append(cmds, resourceCommand(name, "Open "+kindNoun(m.Kind), "", svc.Name(), m))
+			}
+		}
+		if p, ok := svc.(services.CommandProvider); ok {
+			for _, c := range p.ResourceCommands() {
+				cmds = append(cmds, resourceCommand(name, c.Title, c.Action, c.Description, c.Resource))
+			}
+		}
+	}
+	return cmds
+}
+
+func resourceCommand(service, title, action, description string, m services.ResourceMeta) Command {
+	route := Route{
+		View:     ViewResourceDetail,
+		Service:  service,
+		ID:       action,
+		Project:  m.Project,
+		Location: services.Location{Resource: services.RefOf(m)},
+	}
+	if m.Location != "" && m.Location != "global" {
+		description += " (" + m.Location + ")"
+	}
+	return Command{
+		Name:        title + ": " + m.Name,
+		Description: description,
+		Action:      func() Route { return route },
+	}
+}
+
+// kindNoun turns a kind such as "iam.googleapis.com/ServiceAccount" into
+// "service account"
+func kindNoun(kind string) string {
+	var b strings.Builder
+	for i, r := range services.LastSegment(kind) {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
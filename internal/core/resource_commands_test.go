@@ -0,0 +1,70 @@
+package core
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/services"
+)
+
+type fakeResource services.ResourceMeta
+
+func (r fakeResource) Meta() services.ResourceMeta { return services.ResourceMeta(r) }
+
+// fakeService opens its resources and offers one action on them
+type fakeService struct {
+	services.Service
+	resources []fakeResource
+}
+
+func (s fakeService) Name() string { return "Fake Engine" }
+
+func (s fakeService) Resources() []services.Resource { return services.AsResources(s.resources) }
+
+func (s fakeService) OpenResource(services.ResourceRef) tea.Cmd { return nil }
+
+func (s fakeService) ResourceCommands() []services.ResourceCommand {
+	return services.NewResourceCommands("SSH", "ssh", "SSH into the VM", s.resources)
+}
+
+func (s fakeService) RunCommand(string, services.ResourceRef) tea.Cmd { return nil }
+
+func TestBuildResourceCommands(t *testing.T) {
+	vm := fakeResource{Kind: "compute.googleapis.com/Instance", ID: "1", Name: "web-01", Project: "acme", Location: "us-east1-b"}
+	sa := fakeResource{Kind: "iam.googleapis.com/ServiceAccount", Name: "deploy", Location: "global"}
+	svcs := map[string]services.Service{
+		"gce": fakeService{resources: []fakeResource{vm}},
+		"iam": fakeService{resources: []fakeResource{sa}},
+	}
+
+	cmds := BuildResourceCommands(svcs)
+	want := []struct{ name, desc, action string }{
+		{"Open instance: web-01", "Fake Engine (us-east1-b)", ""},
+		{"SSH: web-01", "SSH into the VM (us-east1-b)", "ssh"},
+		{"Open service account: deploy", "Fake Engine", ""},
+		{"SSH: deploy", "SSH into the VM", "ssh"},
+	}
+	if len(cmds) != len(want) {
+		t.Fatalf("got %d commands, want %d", len(cmds), len(want))
+	}
+	for i, w := range want {
+		if cmds[i].Name != w.name || cmds[i].Description != w.desc {
+			t.Errorf("command %d = %q / %q, want %q / %q", i, cmds[i].Name, cmds[i].Description, w.name, w.desc)
+		}
+		if route := cmds[i].Action(); route.View != ViewResourceDetail || route.ID != w.action {
+			t.Errorf("command %d route = %+v, want action %q", i, route, w.action)
+		}
+	}
+
+	route := cmds[1].Action()
+	if route.Service != "gce" || route.Project != "acme" || !route.Location.Resource.Matches(vm.Meta()) {
+		t.Errorf("route = %+v, want web-01 in gce", route)
+	}
+
+	var nav NavigationModel
+	nav.SetResourceCommands(cmds)
+	nav.FilterCommands("ssh web")
+	if len(nav.Suggestions) == 0 || nav.Suggestions[0].Name != "SSH: web-01" {
+		t.Errorf("suggestions for %q = %v, want SSH: web-01 first", "ssh web", nav.Suggestions)
+	}
+}
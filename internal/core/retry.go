@@ -0,0 +1,216 @@
+package core
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/config"
+	"github.com/yogirk/tgcp/internal/utils"
+)
+
+// RetryPolicy controls how RetryTransport retries transient failures
+type RetryPolicy struct {
+	MaxRetries    int           // Retries after the first attempt
+	BaseDelay     time.Duration // Backoff ceiling for the first retry, doubled per attempt
+	MaxDelay      time.Duration // Upper bound for any single backoff
+	MaxRetryAfter time.Duration // Longer Retry-After hints are not waited for
+}
+
+// DefaultRetryPolicy returns the policy used when ~/.tgcprc has no retry section
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:    3,
+		BaseDelay:     200 * time.Millisecond,
+		MaxDelay:      10 * time.Second,
+		MaxRetryAfter: 60 * time.Second,
+	}
+}
+
+var (
+	retryPolicyMu sync.RWMutex
+	retryPolicy   = DefaultRetryPolicy()
+)
+
+// SetRetryPolicy installs the retry settings from ~/.tgcprc.
+// Unset and zero values keep the defaults; max_retries: 0 disables retries.
+func SetRetryPolicy(cfg config.RetryConfig) {
+	p := DefaultRetryPolicy()
+	if cfg.MaxRetries != nil && *cfg.MaxRetries >= 0 {
+		p.MaxRetries = *cfg.MaxRetries
+	}
+	if cfg.BaseDelayMs > 0 {
+		p.BaseDelay = time.Duration(cfg.BaseDelayMs) * time.Millisecond
+	}
+	if cfg.MaxDelayMs > 0 {
+		p.MaxDelay = time.Duration(cfg.MaxDelayMs) * time.Millisecond
+	}
+	if cfg.MaxRetryAfter > 0 {
+		p.MaxRetryAfter = time.Duration(cfg.MaxRetryAfter) * time.Second
+	}
+
+	retryPolicyMu.Lock()
+	defer retryPolicyMu.Unlock()
+	retryPolicy = p
+}
+
+func currentRetryPolicy() RetryPolicy {
+	retryPolicyMu.RLock()
+	defer retryPolicyMu.RUnlock()
+	return retryPolicy
+}
+
+type retrySafeKey struct{}
+
+// WithRetrySafe marks requests made with ctx as safe to retry even when their
+// HTTP method is not idempotent (e.g. read-only POST queries).
+func WithRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+// isRetrySafe reports whether req may be sent more than once.
+// Idempotent methods always are. Other methods must be marked with
+// WithRetrySafe or carry a requestId, which GCP uses to deduplicate mutations.
+func isRetrySafe(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if safe, _ := req.Context().Value(retrySafeKey{}).(bool); safe {
+		return true
+	}
+	return req.URL.Query().Get("requestId") != ""
+}
+
+// --- Retry Transport ---
+
+// RetryTransport retries transient failures with jittered exponential backoff.
+//
+// Retry conditions:
+//   - Network errors (connection failures, timeouts)
+//   - HTTP 429 (Too Many Requests)
+//   - HTTP 5xx (Server Errors)
+//
+// Only requests that are safe to repeat are retried (see isRetrySafe), so a
+// mutation such as Instances.Start is never submitted twice.
+//
+// A Retry-After header on 429/503 responses takes precedence over the backoff.
+// If the server asks for a longer wait than Policy.MaxRetryAfter, the response
+// is returned immediately instead of holding the UI.
+type RetryTransport struct {
+	Next http.RoundTripper
+
+	// Policy overrides the process-wide policy set by SetRetryPolicy
+	Policy *RetryPolicy
+}
+
+// RoundTrip executes the HTTP request with automatic retry on transient failures.
+// Returns the last response/error if all retries are exhausted.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := currentRetryPolicy()
+	if t.Policy != nil {
+		policy = *t.Policy
+	}
+
+	maxRetries := policy.MaxRetries
+	if !isRetrySafe(req) {
+		maxRetries = 0
+	}
+
+	var resp *http.Response
+	var err error
+
+	for i := 0; ; i++ {
+		if i > 0 {
+			callStatsFrom(req.Context()).addRetry()
+		}
+
+		resp, err = t.Next.RoundTrip(req)
+
+		// Success or non-retryable status
+		if err == nil && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		if err != nil && req.Context().Err() != nil {
+			return nil, err
+		}
+		if i >= maxRetries {
+			break
+		}
+
+		delay := backoffDelay(policy, i)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if retryAfter > policy.MaxRetryAfter {
+					utils.Log("Not retrying %s %s: Retry-After %s exceeds limit", req.Method, req.URL.Host, retryAfter)
+					break
+				}
+				delay = retryAfter
+			}
+		}
+
+		// The request body was consumed; give up, with the last response
+		// still readable, if it can't be replayed
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				break
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				break
+			}
+			req.Body = body
+		}
+		if resp != nil {
+			resp.Body.Close() // Close body before retrying
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+			// Continue and retry
+		}
+	}
+
+	return resp, err
+}
+
+// backoffDelay returns a "full jitter" delay: a random duration between zero
+// and BaseDelay*2^attempt, capped at MaxDelay. Spreading retries out keeps
+// concurrent refreshes from hitting a quota-limited API in lockstep.
+func backoffDelay(p RetryPolicy, attempt int) time.Duration {
+	ceiling := p.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// parseRetryAfter understands both forms allowed by RFC 9110:
+// delay-seconds ("120") and an HTTP-date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		d := at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
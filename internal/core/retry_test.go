@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func TestRetryTransport(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxRetryAfter: time.Second}
+
+	tests := []struct {
+		name      string
+		method    string
+		url       string
+		ctx       context.Context
+		wantCalls int
+	}{
+		{"GET is retried", "GET", "/instances", context.Background(), 3},
+		{"POST is not retried", "POST", "/instances/vm/start", context.Background(), 1},
+		{"POST with requestId is retried", "POST", "/instances/vm/start?requestId=abc", context.Background(), 3},
+		{"POST marked safe is retried", "POST", "/query", WithRetrySafe(context.Background()), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: &RetryTransport{Next: http.DefaultTransport, Policy: policy}}
+			req, _ := http.NewRequestWithContext(tt.ctx, tt.method, srv.URL+tt.url, strings.NewReader("{}"))
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if calls != tt.wantCalls {
+				t.Errorf("server called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportUnreplayableBody(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, "backend unavailable")
+	}))
+	defer srv.Close()
+
+	transport := &RetryTransport{Next: http.DefaultTransport, Policy: &RetryPolicy{MaxRetries: 3, MaxRetryAfter: time.Second}}
+	req, _ := http.NewRequest("PUT", srv.URL, io.NopCloser(strings.NewReader("{}")))
+	req.GetBody = nil
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "backend unavailable" {
+		t.Errorf("body = %q, %v; want the API error", body, err)
+	}
+	if calls != 1 {
+		t.Errorf("server called %d times, want 1", calls)
+	}
+}
+
+func TestSetRetryPolicy(t *testing.T) {
+	defer SetRetryPolicy(config.RetryConfig{})
+
+	zero, five := 0, 5
+	tests := []struct {
+		name string
+		cfg  config.RetryConfig
+		want int
+	}{
+		{"unset keeps the default", config.RetryConfig{}, DefaultRetryPolicy().MaxRetries},
+		{"zero disables retries", config.RetryConfig{MaxRetries: &zero}, 0},
+		{"explicit value", config.RetryConfig{MaxRetries: &five}, 5},
+	}
+	for _, tt := range tests {
+		SetRetryPolicy(tt.cfg)
+		if got := currentRetryPolicy().MaxRetries; got != tt.want {
+			t.Errorf("%s: MaxRetries = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -60,8 +60,8 @@ func CheckForUpdates(currentVersion string) tea.Cmd {
 			CheckedAt:      time.Now(),
 		}
 
-		// Skip check for dev builds
-		if currentVersion == "dev" || currentVersion == "" {
+		// Skip check for dev builds and offline cassette replays
+		if currentVersion == "dev" || currentVersion == "" || IsReplaying() {
 			info.Available = false
 			return UpdateCheckedMsg{UpdateInfo: info}
 		}
@@ -0,0 +1,94 @@
+package keys
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyNames are how keys are shown in help, by their tea.KeyMsg string
+var keyNames = map[string]string{
+	"enter":     "Enter",
+	"esc":       "Esc",
+	"tab":       "Tab",
+	"shift+tab": "Shift+Tab",
+	" ":         "Space",
+	"up":        "↑",
+	"down":      "↓",
+	"left":      "←",
+	"right":     "→",
+	"pgup":      "PgUp",
+	"pgdown":    "PgDn",
+	"home":      "Home",
+	"end":       "End",
+	"backspace": "Backspace",
+	"delete":    "Delete",
+}
+
+// displayKey formats a key for help, e.g. "ctrl+g" as "Ctrl+g"
+func displayKey(k string) string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	for _, mod := range []string{"ctrl+", "alt+", "shift+"} {
+		if rest, ok := strings.CutPrefix(k, mod); ok {
+			return strings.ToUpper(mod[:1]) + mod[1:] + displayKey(rest)
+		}
+	}
+	return k
+}
+
+func helpKey(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		shown[i] = displayKey(k)
+	}
+	return strings.Join(shown, "/")
+}
+
+// As returns a copy of a binding described differently in help, e.g.
+// Select as "Detail" in a list of instances
+func As(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// HelpLine renders bindings as a status bar hint, e.g. "r:Refresh  /:Filter"
+func HelpLine(bindings ...key.Binding) string {
+	hints := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		hints = append(hints, b.Help().Key+":"+b.Help().Desc)
+	}
+	return strings.Join(hints, "  ")
+}
+
+// HelpItem is one line of the help overlay
+type HelpItem struct {
+	Keys string
+	Desc string
+}
+
+// HelpSection is one column of the help overlay
+type HelpSection struct {
+	Title string
+	Items []HelpItem
+}
+
+// HelpSections returns every binding by section, for the help overlay
+func HelpSections() []HelpSection {
+	sections := []HelpSection{{Title: SectionGlobal}, {Title: SectionNavigation}, {Title: SectionActions}, {Title: SectionDialogs}}
+	for _, e := range registry {
+		if !e.binding.Enabled() {
+			continue
+		}
+		for i := range sections {
+			if sections[i].Title == e.section {
+				sections[i].Items = append(sections[i].Items, HelpItem{Keys: e.binding.Help().Key, Desc: e.desc})
+			}
+		}
+	}
+	return sections
+}
@@ -0,0 +1,248 @@
+// Package keys is the registry of tgcp's keybindings. Every action a key
+// triggers is a named key.Binding: ~/.tgcprc can rebind it by name, and the
+// status bar hints and the help overlay are generated from the bindings so
+// they always show the keys in effect.
+package keys
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+// Sections of the help overlay
+const (
+	SectionGlobal     = "Global"
+	SectionNavigation = "Navigation"
+	SectionActions    = "Actions"
+	SectionDialogs    = "Dialogs"
+)
+
+// Global bindings are handled before the current view sees a key
+var (
+	Palette        = newBinding("Command", ":")
+	Help           = newBinding("Help", "?")
+	Inspector      = newBinding("Inspector", "ctrl+g")
+	Export         = newBinding("Export", "ctrl+e")
+	Sidebar        = newBinding("Sidebar", "tab")
+	ForceQuit      = newBinding("Force Quit", "ctrl+c")
+	HistoryBack    = newBinding("Back", "alt+left")
+	HistoryForward = newBinding("Forward", "alt+right")
+	RefreshMode    = newBinding("Auto-refresh", "ctrl+r")
+)
+
+// Navigation bindings
+var (
+	Quit         = newBinding("Quit", "q")
+	Up           = newBinding("Up", "up", "k")
+	Down         = newBinding("Down", "down", "j")
+	Select       = newBinding("Select", "enter")
+	Back         = newBinding("Back", "esc", "q")
+	Filter       = newBinding("Filter", "/")
+	FocusSidebar = newBinding("Sidebar", "left")
+	FocusMain    = newBinding("Main", "right", "l")
+	Sort         = newBinding("Sort", "o")
+	ReverseSort  = newBinding("Reverse", "O")
+	Columns      = newBinding("Columns", "C")
+	Mark         = newBinding("Mark", " ")
+	MarkAll      = newBinding("Mark All", "*")
+)
+
+// Service actions. The same key may do different things in different
+// services, e.g. "s" starts a VM but snapshots a disk.
+var (
+	Refresh           = newBinding("Refresh", "r")
+	Start             = newBinding("Start", "s")
+	Stop              = newBinding("Stop", "x")
+	SSH               = newBinding("SSH", "h")
+	Logs              = newBinding("Logs", "l")
+	Snapshot          = newBinding("Snapshot", "s")
+	K9s               = newBinding("k9s", "K")
+	SwitchTab         = newBinding("Tabs", "[", "]")
+	ShowSubscriptions = newBinding("Switch to Subs", "s")
+	ShowTopics        = newBinding("Switch to Topics", "t")
+	OlderLogs         = newBinding("Older", "p")
+	NewerLogs         = newBinding("Newer", "n")
+	Versions          = newBinding("Versions", "v")
+)
+
+// Dialog and overlay bindings. The inspector, audit log, export and column
+// overlays see keys before the global bindings; confirmation dialogs are
+// part of the service views.
+var (
+	Confirm        = newBinding("Confirm", "y", "enter")
+	Cancel         = newBinding("Cancel", "n", "esc", "q")
+	ConfirmTyped   = newBinding("Confirm", "enter")
+	CancelTyped    = newBinding("Cancel", "esc")
+	Top            = newBinding("Top", "g")
+	NextService    = newBinding("Next Service", "tab")
+	PrevService    = newBinding("Previous Service", "shift+tab")
+	ExportCSV      = newBinding("CSV", "c")
+	ExportJSON     = newBinding("JSON", "j")
+	ExportMarkdown = newBinding("Markdown", "m")
+	NextFormat     = newBinding("Next Format", "tab", "right", "l")
+	PrevFormat     = newBinding("Previous Format", "shift+tab", "left", "h")
+	ExportFull     = newBinding("All Fields", "a")
+	ExportFile     = newBinding("Write File", "enter", "w")
+	ExportStdout   = newBinding("Print on Exit", "s")
+	MoveUp         = newBinding("Move Up", "shift+up", "K")
+	MoveDown       = newBinding("Move Down", "shift+down", "J")
+	ToggleColumn   = newBinding("Show/Hide", " ", "x")
+	SortColumn     = newBinding("Sort", "s")
+)
+
+// entry registers a binding under the name ~/.tgcprc uses for it
+type entry struct {
+	name     string
+	section  string
+	desc     string // Description in the help overlay
+	binding  *key.Binding
+	defaults []string
+	overlay  bool // Seen before the global bindings, so it may reuse their keys
+}
+
+var registry = []*entry{
+	{name: "palette", section: SectionGlobal, desc: "Command Palette", binding: &Palette},
+	{name: "help", section: SectionGlobal, desc: "Toggle Help", binding: &Help},
+	{name: "inspector", section: SectionGlobal, desc: "API Call Inspector", binding: &Inspector},
+	{name: "export", section: SectionGlobal, desc: "Export Table", binding: &Export},
+	{name: "sidebar", section: SectionGlobal, desc: "Toggle Sidebar", binding: &Sidebar},
+	{name: "force_quit", section: SectionGlobal, desc: "Force Quit", binding: &ForceQuit},
+	{name: "history_back", section: SectionGlobal, desc: "Previous Place", binding: &HistoryBack},
+	{name: "history_forward", section: SectionGlobal, desc: "Next Place", binding: &HistoryForward},
+	{name: "refresh_mode", section: SectionGlobal, desc: "Auto-refresh / Watch / Pause", binding: &RefreshMode},
+
+	{name: "quit", section: SectionNavigation, desc: "Quit / Home", binding: &Quit},
+	{name: "up", section: SectionNavigation, desc: "Move Up", binding: &Up},
+	{name: "down", section: SectionNavigation, desc: "Move Down", binding: &Down},
+	{name: "select", section: SectionNavigation, desc: "Select / Details", binding: &Select},
+	{name: "back", section: SectionNavigation, desc: "Go Back", binding: &Back},
+	{name: "filter", section: SectionNavigation, desc: "Filter Items", binding: &Filter},
+	{name: "focus_sidebar", section: SectionNavigation, desc: "Focus Sidebar", binding: &FocusSidebar},
+	{name: "focus_main", section: SectionNavigation, desc: "Focus Main View", binding: &FocusMain},
+	{name: "sort", section: SectionNavigation, desc: "Sort Column", binding: &Sort},
+	{name: "reverse_sort", section: SectionNavigation, desc: "Reverse Sort", binding: &ReverseSort},
+	{name: "columns", section: SectionNavigation, desc: "Edit Columns", binding: &Columns},
+	{name: "mark", section: SectionNavigation, desc: "Mark Row", binding: &Mark},
+	{name: "mark_all", section: SectionNavigation, desc: "Mark All", binding: &MarkAll},
+
+	{name: "refresh", section: SectionActions, desc: "Refresh Data", binding: &Refresh},
+	{name: "start", section: SectionActions, desc: "Start Resource", binding: &Start},
+	{name: "stop", section: SectionActions, desc: "Stop Resource", binding: &Stop},
+	{name: "ssh", section: SectionActions, desc: "SSH Connect", binding: &SSH},
+	{name: "logs", section: SectionActions, desc: "View Logs", binding: &Logs},
+	{name: "snapshot", section: SectionActions, desc: "Snapshot Disk", binding: &Snapshot},
+	{name: "k9s", section: SectionActions, desc: "Launch k9s", binding: &K9s},
+	{name: "switch_tab", section: SectionActions, desc: "Switch Tab", binding: &SwitchTab},
+	{name: "show_subscriptions", section: SectionActions, desc: "Pub/Sub Subscriptions", binding: &ShowSubscriptions},
+	{name: "show_topics", section: SectionActions, desc: "Pub/Sub Topics", binding: &ShowTopics},
+	{name: "older_logs", section: SectionActions, desc: "Older Log Entries", binding: &OlderLogs},
+	{name: "newer_logs", section: SectionActions, desc: "Newer Log Entries", binding: &NewerLogs},
+	{name: "versions", section: SectionActions, desc: "Secret Versions", binding: &Versions},
+
+	{name: "confirm", section: SectionDialogs, desc: "Confirm Action", binding: &Confirm},
+	{name: "cancel", section: SectionDialogs, desc: "Cancel / Close Dialog", binding: &Cancel},
+	{name: "confirm_typed", section: SectionDialogs, desc: "Confirm Typed Name", binding: &ConfirmTyped},
+	{name: "cancel_typed", section: SectionDialogs, desc: "Cancel Typed Name", binding: &CancelTyped},
+	{name: "top", section: SectionDialogs, desc: "Inspector/Audit: Top", binding: &Top, overlay: true},
+	{name: "next_service", section: SectionDialogs, desc: "Inspector: Next Service", binding: &NextService, overlay: true},
+	{name: "prev_service", section: SectionDialogs, desc: "Inspector: Prev Service", binding: &PrevService, overlay: true},
+	{name: "export_csv", section: SectionDialogs, desc: "Export: CSV", binding: &ExportCSV, overlay: true},
+	{name: "export_json", section: SectionDialogs, desc: "Export: JSON", binding: &ExportJSON, overlay: true},
+	{name: "export_markdown", section: SectionDialogs, desc: "Export: Markdown", binding: &ExportMarkdown, overlay: true},
+	{name: "next_format", section: SectionDialogs, desc: "Export: Next Format", binding: &NextFormat, overlay: true},
+	{name: "prev_format", section: SectionDialogs, desc: "Export: Prev Format", binding: &PrevFormat, overlay: true},
+	{name: "export_full", section: SectionDialogs, desc: "Export: All Fields", binding: &ExportFull, overlay: true},
+	{name: "export_file", section: SectionDialogs, desc: "Export: Write File", binding: &ExportFile, overlay: true},
+	{name: "export_stdout", section: SectionDialogs, desc: "Export: Print on Exit", binding: &ExportStdout, overlay: true},
+	{name: "move_up", section: SectionDialogs, desc: "Columns: Move Up", binding: &MoveUp, overlay: true},
+	{name: "move_down", section: SectionDialogs, desc: "Columns: Move Down", binding: &MoveDown, overlay: true},
+	{name: "toggle_column", section: SectionDialogs, desc: "Columns: Show/Hide", binding: &ToggleColumn, overlay: true},
+	{name: "sort_column", section: SectionDialogs, desc: "Columns: Sort By", binding: &SortColumn, overlay: true},
+}
+
+func init() {
+	for _, e := range registry {
+		e.defaults = e.binding.Keys()
+	}
+}
+
+func newBinding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKey(keys), desc))
+}
+
+// Configure resets every binding to its default keys, then applies the
+// overrides from ~/.tgcprc, keyed by action name. An empty list of keys
+// unbinds the action. Valid overrides are
+// applied even when others are rejected; the error lists the rejected ones
+// and the global keys that a rebinding would hide.
+func Configure(overrides map[string]config.KeyList) error {
+	for _, e := range registry {
+		setKeys(e.binding, e.defaults)
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if e := lookup(name); e != nil {
+			setKeys(e.binding, overrides[name])
+		} else {
+			problems = append(problems, fmt.Sprintf("unknown action %q", name))
+		}
+	}
+	problems = append(problems, conflicts()...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("keys: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// conflicts reports keys bound both to a global action and to another
+// action, which would then never see them. Overlay bindings are exempt.
+func conflicts() []string {
+	global := make(map[string]string)
+	for _, e := range registry {
+		if e.section != SectionGlobal {
+			continue
+		}
+		for _, k := range e.binding.Keys() {
+			global[k] = e.name
+		}
+	}
+
+	var problems []string
+	for _, e := range registry {
+		if e.overlay {
+			continue
+		}
+		for _, k := range e.binding.Keys() {
+			if owner, ok := global[k]; ok && owner != e.name {
+				problems = append(problems, fmt.Sprintf("%s: %q is already %s", e.name, k, owner))
+			}
+		}
+	}
+	return problems
+}
+
+func lookup(name string) *entry {
+	for _, e := range registry {
+		if e.name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+func setKeys(b *key.Binding, keys []string) {
+	b.SetKeys(keys...)
+	b.SetHelp(helpKey(keys), b.Help().Desc)
+}
@@ -0,0 +1,79 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func press(k string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestConfigure(t *testing.T) {
+	defer Configure(nil)
+
+	err := Configure(map[string]config.KeyList{
+		"ssh":   {"S"},
+		"logs":  {"l", "ctrl+l"},
+		"k9s":   nil,
+		"bogus": {"b"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown action "bogus"`) {
+		t.Errorf("Configure() error = %v", err)
+	}
+
+	// Valid overrides apply despite the unknown one
+	if key.Matches(press("h"), SSH) || !key.Matches(press("S"), SSH) {
+		t.Errorf("ssh keys = %v", SSH.Keys())
+	}
+	if got := HelpLine(SSH, Logs, K9s); got != "S:SSH  l/Ctrl+l:Logs" {
+		t.Errorf("HelpLine() = %q", got)
+	}
+
+	// Configuring again starts from the defaults
+	if err := Configure(nil); err != nil {
+		t.Fatalf("Configure(nil) = %v", err)
+	}
+	if !key.Matches(press("h"), SSH) || !K9s.Enabled() {
+		t.Errorf("defaults not restored: ssh %v, k9s %v", SSH.Keys(), K9s.Keys())
+	}
+}
+
+func TestConfigureConflicts(t *testing.T) {
+	defer Configure(nil)
+
+	err := Configure(map[string]config.KeyList{"refresh": {"?"}})
+	if err == nil || !strings.Contains(err.Error(), `refresh: "?" is already help`) {
+		t.Errorf("Configure() error = %v", err)
+	}
+	if err := Configure(map[string]config.KeyList{"refresh": {"R"}, "help": {"r"}}); err != nil {
+		t.Errorf("swapping keys between sections: %v", err)
+	}
+
+	// Overlays see keys before the global bindings, confirmation dialogs don't
+	if err := Configure(map[string]config.KeyList{"next_format": {"tab", "ctrl+e"}}); err != nil {
+		t.Errorf("overlay binding reusing global keys: %v", err)
+	}
+	err = Configure(map[string]config.KeyList{"confirm": {"ctrl+e"}})
+	if err == nil || !strings.Contains(err.Error(), `confirm: "ctrl+e" is already export`) {
+		t.Errorf("Configure() error = %v", err)
+	}
+}
+
+func TestHelp(t *testing.T) {
+	if got := HelpLine(Back, Refresh, As(Select, "Detail"), Mark); got != "Esc/q:Back  r:Refresh  Enter:Detail  Space:Mark" {
+		t.Errorf("HelpLine() = %q", got)
+	}
+	if Select.Help().Desc != "Select" {
+		t.Error("As() changed the binding it copied")
+	}
+
+	sections := HelpSections()
+	if len(sections) != 4 || sections[0].Items[0] != (HelpItem{Keys: ":", Desc: "Command Palette"}) {
+		t.Errorf("HelpSections() = %+v", sections)
+	}
+}
@@ -0,0 +1,231 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/services"
+	"github.com/yogirk/tgcp/internal/utils"
+	cloudasset "google.golang.org/api/cloudasset/v1"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v3"
+)
+
+// maxAssetResults bounds the results fetched per scope
+const maxAssetResults = 200
+
+// errEnough stops paging once maxAssetResults are in
+var errEnough = errors.New("enough results")
+
+// ResultsMsg carries the results of a search
+type ResultsMsg struct {
+	Query   string
+	Scopes  []string // Scopes searched, told apart when the scope is toggled
+	Results []Result
+	Source  string
+	Err     error // Why Cloud Asset Inventory could not be used, if it was not
+}
+
+// AssetClient wraps the Cloud Asset Inventory API
+type AssetClient struct {
+	service *cloudasset.Service
+}
+
+// NewAssetClient initializes a new Cloud Asset client
+func NewAssetClient(ctx context.Context) (*AssetClient, error) {
+	opts, err := core.ClientOptions(ctx, "asset", cloudasset.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := cloudasset.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud asset service: %w", err)
+	}
+	return &AssetClient{service: svc}, nil
+}
+
+// Search runs searchAllResources in one scope ("projects/p",
+// "folders/123" or "organizations/123")
+func (c *AssetClient) Search(ctx context.Context, scope, query string) ([]Result, error) {
+	req := c.service.V1.SearchAllResources(scope).
+		Query(AssetQuery(query)).
+		AssetTypes(AssetTypes()...).
+		PageSize(100)
+
+	var results []Result
+	err := req.Pages(ctx, func(page *cloudasset.SearchAllResourcesResponse) error {
+		for _, r := range page.Results {
+			results = append(results, fromAsset(r, scope, query))
+			if len(results) >= maxAssetResults {
+				return errEnough
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errEnough {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SearchCmd searches every scope through Cloud Asset Inventory and returns
+// the local results instead when the API cannot be used
+func SearchCmd(query string, scopes []string, local []Result) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		client, err := NewAssetClient(ctx)
+		if err != nil {
+			return ResultsMsg{Query: query, Scopes: scopes, Results: local, Source: SourceCache, Err: err}
+		}
+
+		var results []Result
+		for _, scope := range scopes {
+			found, err := client.Search(ctx, scope, query)
+			if err != nil {
+				return ResultsMsg{Query: query, Scopes: scopes, Results: local, Source: SourceCache, Err: err}
+			}
+			results = append(results, found...)
+		}
+		resolveProjectNumbers(results, projectIDLookup(ctx))
+		sortResults(results)
+		return ResultsMsg{Query: query, Scopes: scopes, Results: results, Source: SourceAsset}
+	}
+}
+
+// resolveProjectNumbers replaces the project numbers some results carry
+// (buckets searched in a folder, secrets) with project IDs, which is what
+// switching projects needs. A number that can't be resolved is kept.
+func resolveProjectNumbers(results []Result, lookup func(number string) (string, error)) {
+	ids := make(map[string]string)
+	for i := range results {
+		number := results[i].Meta.Project
+		if !IsProjectNumber(number) {
+			continue
+		}
+		id, ok := ids[number]
+		if !ok {
+			var err error
+			if id, err = lookup(number); err != nil {
+				utils.Log("Resolving project %s: %v", number, err)
+				id = number
+			}
+			ids[number] = id
+		}
+		results[i].Meta.Project = id
+	}
+}
+
+// projectIDLookup resolves project numbers through Cloud Resource Manager
+func projectIDLookup(ctx context.Context) func(number string) (string, error) {
+	var svc *cloudresourcemanager.Service
+	return func(number string) (string, error) {
+		if svc == nil {
+			opts, err := core.ClientOptions(ctx, "projects", cloudresourcemanager.CloudPlatformReadOnlyScope)
+			if err != nil {
+				return "", err
+			}
+			if svc, err = cloudresourcemanager.NewService(ctx, opts...); err != nil {
+				return "", fmt.Errorf("failed to create resource manager client: %w", err)
+			}
+		}
+		p, err := svc.Projects.Get("projects/" + number).Context(ctx).Do()
+		if err != nil {
+			return "", err
+		}
+		return p.ProjectId, nil
+	}
+}
+
+// IsProjectNumber reports whether a project reference is a number rather
+// than an ID. IDs must start with a letter.
+func IsProjectNumber(project string) bool {
+	if project == "" {
+		return false
+	}
+	for _, r := range project {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// fromAsset converts a search result to the services' resource description
+func fromAsset(r *cloudasset.ResourceSearchResult, scope, query string) Result {
+	kind := r.AssetType
+	if alias, ok := kindAliases[kind]; ok {
+		kind = alias
+	}
+	name := r.DisplayName
+	if name == "" {
+		name = services.LastSegment(r.Name)
+	}
+
+	meta := services.ResourceMeta{
+		Kind:      kind,
+		ID:        services.LastSegment(r.Name),
+		Name:      name,
+		Project:   assetProject(r, scope),
+		Location:  r.Location,
+		State:     r.State,
+		Labels:    r.Labels,
+		Addresses: assetAddresses(r.AdditionalAttributes),
+		SelfLink:  r.Name,
+	}
+
+	why, ok := Match(meta, query)
+	if !ok {
+		why = "match" // Cloud Asset also searches fields tgcp does not model
+	}
+	return Result{Service: ServiceFor(r.AssetType), Meta: meta, Match: why, Source: SourceAsset}
+}
+
+// assetProject returns the project ID from the full resource name when it
+// has one, then from a project scope, else the project number, which
+// resolveProjectNumbers turns into an ID
+func assetProject(r *cloudasset.ResourceSearchResult, scope string) string {
+	parts := strings.Split(r.Name, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] == "projects" {
+			return parts[i+1]
+		}
+	}
+	if strings.HasPrefix(scope, "projects/") {
+		return strings.TrimPrefix(scope, "projects/")
+	}
+	return strings.TrimPrefix(r.Project, "projects/")
+}
+
+// assetAddresses collects the IP addresses from the type-specific attributes,
+// e.g. "internalIPs" of instances or "address" of forwarding rules
+func assetAddresses(raw []byte) []string {
+	var attrs map[string]interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &attrs) != nil {
+		return nil
+	}
+
+	var addrs []string
+	for k, v := range attrs {
+		key := strings.ToLower(k)
+		if !strings.HasSuffix(key, "ip") && !strings.HasSuffix(key, "ips") &&
+			!strings.HasSuffix(key, "address") && !strings.HasSuffix(key, "addresses") {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			addrs = append(addrs, v)
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					addrs = append(addrs, s)
+				}
+			}
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,223 @@
+// Package search finds resources by name, IP or label across services,
+// through Cloud Asset Inventory when available and the services' loaded
+// lists otherwise.
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
+// Where results came from
+const (
+	SourceAsset = "Cloud Asset Inventory"
+	SourceCache = "loaded service lists"
+)
+
+// kindServices maps Cloud Asset types to the service that shows them
+var kindServices = map[string]string{
+	"compute.googleapis.com/Instance":             "gce",
+	"compute.googleapis.com/Disk":                 "disks",
+	"compute.googleapis.com/Network":              "net",
+	"container.googleapis.com/Cluster":            "gke",
+	"run.googleapis.com/Service":                  "run",
+	"cloudfunctions.googleapis.com/Function":      "run",
+	"cloudfunctions.googleapis.com/CloudFunction": "run",
+	"sqladmin.googleapis.com/Instance":            "sql",
+	"storage.googleapis.com/Bucket":               "gcs",
+	"bigquery.googleapis.com/Dataset":             "bq",
+	"redis.googleapis.com/Instance":               "redis",
+	"spanner.googleapis.com/Instance":             "spanner",
+	"bigtableadmin.googleapis.com/Instance":       "bigtable",
+	"firestore.googleapis.com/Database":           "firestore",
+	"pubsub.googleapis.com/Topic":                 "pubsub",
+	"pubsub.googleapis.com/Subscription":          "pubsub",
+	"dataflow.googleapis.com/Job":                 "dataflow",
+	"dataproc.googleapis.com/Cluster":             "dataproc",
+	"iam.googleapis.com/ServiceAccount":           "iam",
+	"secretmanager.googleapis.com/Secret":         "secrets",
+	"compute.googleapis.com/Address":              "", // Listed for IP lookups, no view yet
+	"compute.googleapis.com/GlobalAddress":        "",
+	"compute.googleapis.com/ForwardingRule":       "",
+	"compute.googleapis.com/GlobalForwardingRule": "",
+}
+
+// kindAliases maps asset types that tgcp models under another kind
+var kindAliases = map[string]string{
+	"cloudfunctions.googleapis.com/CloudFunction": "cloudfunctions.googleapis.com/Function",
+}
+
+// ServiceFor returns the short name of the service that shows a kind,
+// or "" when tgcp has no view for it
+func ServiceFor(kind string) string {
+	return kindServices[kind]
+}
+
+// AssetTypes returns the asset types searched, sorted
+func AssetTypes() []string {
+	types := make([]string, 0, len(kindServices))
+	for kind := range kindServices {
+		types = append(types, kind)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Result is one matching resource
+type Result struct {
+	Service string // Owning service short name; empty when tgcp has no view for it
+	Meta    services.ResourceMeta
+	Match   string // What matched, e.g. "ip 10.4.2.17" or "label env=prod"
+	Source  string
+}
+
+// Ref points the owning service at the result
+func (r Result) Ref() services.ResourceRef {
+	return services.ResourceRef{
+		Kind:     r.Meta.Kind,
+		ID:       r.Meta.ID,
+		Name:     r.Meta.Name,
+		Location: r.Meta.Location,
+	}
+}
+
+// term is one whitespace-separated part of a query
+type term struct {
+	label string // Label key for "key=value" terms
+	value string
+}
+
+func parseQuery(query string) []term {
+	var terms []term
+	for _, field := range strings.Fields(query) {
+		if k, v, ok := strings.Cut(field, "="); ok && k != "" {
+			terms = append(terms, term{label: k, value: v})
+			continue
+		}
+		terms = append(terms, term{value: field})
+	}
+	return terms
+}
+
+// AssetQuery translates a query to the Cloud Asset search syntax:
+// "env=prod 10.4.2.17" becomes "labels.env:prod 10.4.2.17"
+func AssetQuery(query string) string {
+	var parts []string
+	for _, t := range parseQuery(query) {
+		if t.label != "" {
+			v := t.value
+			if v == "" {
+				v = "*"
+			}
+			parts = append(parts, "labels."+t.label+":"+v)
+			continue
+		}
+		parts = append(parts, t.value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Local searches the resources the services have loaded. A resource matches
+// when every term matches its name, ID, an address or a label.
+func Local(query string, providers map[string]services.ResourceProvider) []Result {
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []Result
+	for service, p := range providers {
+		for _, r := range p.Resources() {
+			meta := r.Meta()
+			if why, ok := Match(meta, query); ok {
+				results = append(results, Result{Service: service, Meta: meta, Match: why, Source: SourceCache})
+			}
+		}
+	}
+	sortResults(results)
+	return results
+}
+
+// Match reports whether every term of the query matches the resource, and
+// describes what the first term matched
+func Match(meta services.ResourceMeta, query string) (string, bool) {
+	terms := parseQuery(query)
+	if len(terms) == 0 {
+		return "", false
+	}
+	why := ""
+	for i, t := range terms {
+		reason, ok := matchTerm(meta, t)
+		if !ok {
+			return "", false
+		}
+		if i == 0 {
+			why = reason
+		}
+	}
+	return why, true
+}
+
+func matchTerm(meta services.ResourceMeta, t term) (string, bool) {
+	if t.label != "" {
+		v, ok := meta.Labels[t.label]
+		if ok && (t.value == "" || t.value == "*" || strings.EqualFold(v, t.value)) {
+			return "label " + t.label + "=" + v, true
+		}
+		return "", false
+	}
+
+	q := strings.ToLower(t.value)
+	for _, addr := range meta.Addresses {
+		if addr != "" && strings.HasPrefix(strings.ToLower(addr), q) {
+			return "ip " + addr, true
+		}
+	}
+	if strings.Contains(strings.ToLower(meta.Name), q) {
+		return "name", true
+	}
+	if strings.Contains(strings.ToLower(meta.ID), q) {
+		return "id", true
+	}
+	for k, v := range meta.Labels {
+		if strings.EqualFold(v, t.value) || strings.EqualFold(k, t.value) {
+			return "label " + k + "=" + v, true
+		}
+	}
+	return "", false
+}
+
+func sortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Meta.Project != results[j].Meta.Project {
+			return results[i].Meta.Project < results[j].Meta.Project
+		}
+		if results[i].Service != results[j].Service {
+			return results[i].Service < results[j].Service
+		}
+		return results[i].Meta.Name < results[j].Meta.Name
+	})
+}
+
+// Scopes returns the Cloud Asset scopes to search: the current project, or
+// with all set the configured organization/folder scope, or else the current
+// project plus the configured projects
+func Scopes(current string, all bool, projects []string, scope string) []string {
+	if !all {
+		return []string{"projects/" + current}
+	}
+	if scope != "" {
+		return []string{scope}
+	}
+	scopes := []string{"projects/" + current}
+	seen := map[string]bool{current: true}
+	for _, p := range projects {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			scopes = append(scopes, "projects/"+p)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,164 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/yogirk/tgcp/internal/services"
+	cloudasset "google.golang.org/api/cloudasset/v1"
+)
+
+type fakeResource services.ResourceMeta
+
+func (f fakeResource) Meta() services.ResourceMeta { return services.ResourceMeta(f) }
+
+type fakeProvider []services.Resource
+
+func (p fakeProvider) Resources() []services.Resource { return p }
+
+func TestAssetQuery(t *testing.T) {
+	tests := map[string]string{
+		"web":                "web",
+		"env=prod 10.4.2.17": "labels.env:prod 10.4.2.17",
+		"team=":              "labels.team:*",
+		"  a   b ":           "a b",
+	}
+	for in, want := range tests {
+		if got := AssetQuery(in); got != want {
+			t.Errorf("AssetQuery(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	providers := map[string]services.ResourceProvider{
+		"gce": fakeProvider{
+			fakeResource{Kind: "compute.googleapis.com/Instance", Name: "web-01", Addresses: []string{"10.4.2.17", ""}, Labels: map[string]string{"env": "prod"}},
+			fakeResource{Kind: "compute.googleapis.com/Instance", Name: "batch-01", Addresses: []string{"10.4.9.1"}, Labels: map[string]string{"env": "dev"}},
+		},
+		"sql": fakeProvider{
+			fakeResource{Kind: "sqladmin.googleapis.com/Instance", Name: "orders-db", Addresses: []string{"10.8.0.3"}, Labels: map[string]string{"env": "prod"}},
+		},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+		why   string
+	}{
+		{"10.4.2.17", []string{"web-01"}, "ip 10.4.2.17"},
+		{"10.4.", []string{"batch-01", "web-01"}, ""},
+		{"env=prod", []string{"web-01", "orders-db"}, ""},
+		{"env=prod db", []string{"orders-db"}, "label env=prod"},
+		{"WEB", []string{"web-01"}, "name"},
+		{"prod", []string{"web-01", "orders-db"}, "label env=prod"},
+		{"nothing", nil, ""},
+		{"", nil, ""},
+	}
+	for _, tt := range tests {
+		results := Local(tt.query, providers)
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Meta.Name)
+			if r.Source != SourceCache {
+				t.Errorf("Local(%q): source %q", tt.query, r.Source)
+			}
+			if tt.why != "" && r.Match != tt.why {
+				t.Errorf("Local(%q): %s matched %q, want %q", tt.query, r.Meta.Name, r.Match, tt.why)
+			}
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("Local(%q) = %v, want %v", tt.query, names, tt.want)
+		}
+	}
+}
+
+func TestScopes(t *testing.T) {
+	if got := Scopes("p1", false, []string{"p2"}, "organizations/1"); !reflect.DeepEqual(got, []string{"projects/p1"}) {
+		t.Errorf("current project scopes = %v", got)
+	}
+	if got := Scopes("p1", true, []string{"p2", "p1", ""}, ""); !reflect.DeepEqual(got, []string{"projects/p1", "projects/p2"}) {
+		t.Errorf("project list scopes = %v", got)
+	}
+	if got := Scopes("p1", true, []string{"p2"}, "organizations/1"); !reflect.DeepEqual(got, []string{"organizations/1"}) {
+		t.Errorf("organization scopes = %v", got)
+	}
+}
+
+func TestFromAsset(t *testing.T) {
+	r := fromAsset(&cloudasset.ResourceSearchResult{
+		AssetType:            "compute.googleapis.com/Instance",
+		Name:                 "//compute.googleapis.com/projects/acme/zones/us-east1-b/instances/web-01",
+		DisplayName:          "web-01",
+		Location:             "us-east1-b",
+		Project:              "projects/1234",
+		AdditionalAttributes: []byte(`{"internalIPs":["10.4.2.17"],"externalIPs":[],"description":"ripe"}`),
+	}, "organizations/1", "10.4.2.17")
+
+	if r.Service != "gce" || r.Meta.Project != "acme" || r.Meta.Name != "web-01" || r.Match != "ip 10.4.2.17" {
+		t.Errorf("fromAsset = %+v", r)
+	}
+	if !reflect.DeepEqual(r.Meta.Addresses, []string{"10.4.2.17"}) {
+		t.Errorf("addresses = %v", r.Meta.Addresses)
+	}
+
+	// Buckets have no project in their name
+	b := fromAsset(&cloudasset.ResourceSearchResult{
+		AssetType: "storage.googleapis.com/Bucket",
+		Name:      "//storage.googleapis.com/acme-logs",
+		Project:   "projects/1234",
+	}, "projects/acme", "logs")
+	if b.Meta.Project != "acme" || b.Meta.Name != "acme-logs" || b.Service != "gcs" {
+		t.Errorf("fromAsset bucket = %+v", b)
+	}
+
+	// First-generation functions are modelled as Functions
+	f := fromAsset(&cloudasset.ResourceSearchResult{
+		AssetType: "cloudfunctions.googleapis.com/CloudFunction",
+		Name:      "//cloudfunctions.googleapis.com/projects/acme/locations/us-east1/functions/hook",
+	}, "projects/acme", "hook")
+	if f.Meta.Kind != "cloudfunctions.googleapis.com/Function" || f.Service != "run" {
+		t.Errorf("fromAsset function = %+v", f)
+	}
+}
+
+func TestResolveProjectNumbers(t *testing.T) {
+	results := []Result{
+		{Meta: services.ResourceMeta{Name: "acme-logs", Project: "1234"}},
+		{Meta: services.ResourceMeta{Name: "token", Project: "1234"}},
+		{Meta: services.ResourceMeta{Name: "web-01", Project: "acme"}},
+		{Meta: services.ResourceMeta{Name: "orphan", Project: "5678"}},
+	}
+	calls := 0
+	resolveProjectNumbers(results, func(number string) (string, error) {
+		calls++
+		if number == "1234" {
+			return "acme", nil
+		}
+		return "", errors.New("permission denied")
+	})
+
+	for i, want := range []string{"acme", "acme", "acme", "5678"} {
+		if results[i].Meta.Project != want {
+			t.Errorf("results[%d].Project = %q, want %q", i, results[i].Meta.Project, want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("lookup called %d times, want once per number", calls)
+	}
+	if IsProjectNumber("acme-123") || !IsProjectNumber("5678") || IsProjectNumber("") {
+		t.Error("IsProjectNumber misclassifies")
+	}
+}
+
+func TestAssetTypes(t *testing.T) {
+	types := AssetTypes()
+	if !sort.StringsAreSorted(types) || len(types) != len(kindServices) {
+		t.Errorf("AssetTypes() = %v", types)
+	}
+	if ServiceFor("compute.googleapis.com/ForwardingRule") != "" || ServiceFor("redis.googleapis.com/Instance") != "redis" {
+		t.Error("ServiceFor returned the wrong service")
+	}
+}
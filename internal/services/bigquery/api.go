@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"cloud.google.com/go/bigquery"
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/iterator"
 )
 
@@ -13,7 +14,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context, projectID string) (*Client, error) {
-	c, err := bigquery.NewClient(ctx, projectID)
+	opts, err := core.ClientOptions(ctx, "bq", bigquery.Scope)
+	if err != nil {
+		return nil, err
+	}
+	c, err := bigquery.NewClient(ctx, projectID, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bigquery client: %w", err)
 	}
@@ -49,6 +54,7 @@ func (c *Client) ListDatasets(projectID string) ([]Dataset, error) {
 			ID:        ds.DatasetID,
 			ProjectID: ds.ProjectID,
 			Location:  md.Location,
+			Labels:    md.Labels,
 		})
 	}
 	return datasets, nil
@@ -84,6 +90,7 @@ func (c *Client) ListTables(datasetID string) ([]Table, error) {
 				ID:        t.TableID,
 				DatasetID: datasetID,
 				Type:      "UNKNOWN",
+				ProjectID: t.ProjectID,
 			})
 			continue
 		}
@@ -95,6 +102,8 @@ func (c *Client) ListTables(datasetID string) ([]Table, error) {
 			NumRows:    md.NumRows,
 			TotalBytes: md.NumBytes,
 			LastMod:    md.LastModifiedTime,
+			ProjectID:  t.ProjectID,
+			Labels:     md.Labels,
 		})
 	}
 	return tables, nil
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -19,8 +22,6 @@ const CacheTTL = 5 * time.Minute
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -61,6 +62,9 @@ type Service struct {
 	selectedDataset *Dataset
 	selectedTable   *Table
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	cache *core.Cache
 }
 
@@ -70,7 +74,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "ID", Width: 30},
 		{Title: "Location", Width: 15},
 	}
-	dsTable := components.NewStandardTable(dsCols)
+	dsTable := components.NewStandardTable(dsCols, components.WithName("bq-datasets"))
 
 	// Table Table
 	tCols := []table.Column{
@@ -79,7 +83,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Rows", Width: 10},
 		{Title: "Size", Width: 10},
 	}
-	tTable := components.NewStandardTable(tCols)
+	tTable := components.NewStandardTable(tCols, components.WithName("bq-tables"))
 
 	// Schema Table
 	sCols := []table.Column{
@@ -88,7 +92,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Mode", Width: 10},
 		{Title: "Description", Width: 30},
 	}
-	sTable := components.NewStandardTable(sCols)
+	sTable := components.NewStandardTable(sCols, components.WithName("bq-schema"))
 
 	return &Service{
 		datasetTable: dsTable,
@@ -105,13 +109,13 @@ func (s *Service) ShortName() string { return "bq" }
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewDatasets {
-		return "Ent:Select  r:Refresh"
+		return keys.HelpLine(keys.Select, keys.Refresh)
 	}
 	if s.viewState == ViewTables {
-		return "Ent:Schema  Esc/q:Back"
+		return keys.HelpLine(keys.As(keys.Select, "Schema"), keys.Back)
 	}
 	if s.viewState == ViewSchema {
-		return "Esc/q:Back"
+		return keys.HelpLine(keys.Back)
 	}
 	return ""
 }
@@ -137,7 +141,7 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg { return tickMsg(t) })
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
@@ -157,6 +161,7 @@ func (s *Service) Refresh() tea.Cmd {
 }
 
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewDatasets
 	s.selectedDataset = nil
 	s.selectedTable = nil
@@ -168,6 +173,53 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewDatasets
 }
 
+// ExportTable snapshots the table on screen (datasets, tables or a
+// schema) for export
+func (s *Service) ExportTable() components.TableExport {
+	switch s.viewState {
+	case ViewTables:
+		return components.NewTableExport("bq-tables", s.tableTable, s.tables)
+	case ViewSchema:
+		return components.NewTableExport("bq-schema", s.schemaTable, s.schema)
+	}
+	return components.NewTableExport("bq-datasets", s.datasetTable, s.datasets)
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.datasets)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	return s.openPending()
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() tea.Cmd {
+	if i := services.FindPending(&s.pendingOpen, s.datasets); i >= 0 {
+		s.selectedDataset = &s.datasets[i]
+		s.viewState = ViewTables
+		return tea.Batch(s.fetchTablesCmd(), s.spinner.Start(""))
+	}
+	return nil
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	var loc services.Location
+	if !s.IsRootView() && s.selectedDataset != nil {
+		loc.Resource = services.RefOf(s.selectedDataset.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.datasetTable.Focus()
 	s.tableTable.Focus()
@@ -191,18 +243,19 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		// Background refresh? Maybe only if at root.
+	case core.RefreshTickMsg:
+		// Background refresh only at root
 		if s.viewState == ViewDatasets {
-			return s, tea.Batch(s.fetchDatasetsCmd(), s.Init())
+			return s, s.fetchDatasetsCmd()
 		}
-		return s, s.Init()
+		return s, nil
 
 	case datasetsMsg:
 		s.spinner.Stop()
 		s.datasets = msg
+		s.selectedDataset = services.Reselect(s.selectedDataset, s.datasets)
 		s.updateDatasetTable()
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		return s, tea.Batch(s.openPending(), core.OpenNotFound(&s.pendingOpen, nil), func() tea.Msg { return core.LastUpdatedMsg(time.Now()) })
 
 	case tablesMsg:
 		s.spinner.Stop()
@@ -226,7 +279,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.tableTable.HandleWindowSizeDefault(msg)
 		s.schemaTable.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to active table for click selection
 		switch s.viewState {
 		case ViewDatasets:
@@ -242,13 +295,14 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
+		s.pendingOpen.Clear()
+		switch {
+		case key.Matches(msg, keys.Refresh):
 			return s, s.Refresh()
 		}
 
 		if s.viewState == ViewDatasets {
-			if msg.String() == "enter" {
+			if key.Matches(msg, keys.Select) {
 				if s.datasetTable.Cursor() >= 0 && s.datasetTable.Cursor() < len(s.datasets) {
 					s.selectedDataset = &s.datasets[s.datasetTable.Cursor()]
 					s.viewState = ViewTables
@@ -262,12 +316,12 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewTables {
-			if msg.String() == "esc" || msg.String() == "q" {
+			if key.Matches(msg, keys.Back) {
 				s.viewState = ViewDatasets
 				s.selectedDataset = nil
 				return s, nil
 			}
-			if msg.String() == "enter" {
+			if key.Matches(msg, keys.Select) {
 				if s.tableTable.Cursor() >= 0 && s.tableTable.Cursor() < len(s.tables) {
 					s.selectedTable = &s.tables[s.tableTable.Cursor()]
 					s.viewState = ViewSchema
@@ -281,7 +335,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewSchema {
-			if msg.String() == "esc" || msg.String() == "q" {
+			if key.Matches(msg, keys.Back) {
 				s.viewState = ViewTables
 				s.selectedTable = nil
 				return s, nil
@@ -1,11 +1,31 @@
 package bigquery
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
 
 type Dataset struct {
 	ID        string
 	ProjectID string
 	Location  string
+	Labels    map[string]string
+}
+
+// Meta implements services.Resource
+func (d Dataset) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "bigquery.googleapis.com/Dataset",
+		ID:         d.ProjectID + ":" + d.ID,
+		Name:       d.ID,
+		Project:    d.ProjectID,
+		Location:   d.Location,
+		Labels:     d.Labels,
+		SelfLink:   fmt.Sprintf("https://bigquery.googleapis.com/bigquery/v2/projects/%s/datasets/%s", d.ProjectID, d.ID),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("bigquery?p=%s&d=%s&page=dataset", d.ProjectID, d.ID), d.ProjectID),
+	}
 }
 
 type Table struct {
@@ -15,6 +35,21 @@ type Table struct {
 	NumRows    uint64
 	TotalBytes int64
 	LastMod    time.Time
+	ProjectID  string
+	Labels     map[string]string
+}
+
+// Meta implements services.Resource
+func (t Table) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "bigquery.googleapis.com/Table",
+		ID:         fmt.Sprintf("%s:%s.%s", t.ProjectID, t.DatasetID, t.ID),
+		Name:       t.ID,
+		Project:    t.ProjectID,
+		Labels:     t.Labels,
+		SelfLink:   fmt.Sprintf("https://bigquery.googleapis.com/bigquery/v2/projects/%s/datasets/%s/tables/%s", t.ProjectID, t.DatasetID, t.ID),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("bigquery?p=%s&d=%s&t=%s&page=table", t.ProjectID, t.DatasetID, t.ID), t.ProjectID),
+	}
 }
 
 type SchemaField struct {
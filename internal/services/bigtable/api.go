@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/bigtableadmin/v2"
 )
 
@@ -13,7 +14,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := bigtableadmin.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "bigtable", bigtableadmin.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := bigtableadmin.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("bigtable client: %w", err)
 	}
@@ -42,6 +47,7 @@ func (c *Client) ListInstances(projectID string) ([]Instance, error) {
 				ProjectID:   projectID,
 				State:       i.State,
 				Type:        i.Type,
+				Labels:      i.Labels,
 			})
 		}
 		return nil
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -17,8 +20,6 @@ const CacheTTL = 60 * time.Second
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -50,6 +51,9 @@ type Service struct {
 	viewState        ViewState
 	selectedInstance *Instance
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	cache *core.Cache
 }
 
@@ -61,7 +65,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "State", Width: 10},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("bigtable-instances"))
 
 	svc := &Service{
 		table:     t,
@@ -70,7 +74,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredInstances, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, instanceFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -84,9 +88,9 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.As(keys.Select, "Detail"))
 	}
-	return "Esc/q:Back"
+	return keys.HelpLine(keys.Back)
 }
 
 // -----------------------------------------------------------------------------
@@ -110,23 +114,23 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchInstancesCmd(true),
+		core.PreloadPersisted(s.cache, s.instancesCacheKey(), func(v []Instance) tea.Msg { return instancesMsg(v) }, s.fetchInstancesCmd(true)),
 	)
 }
 
+// instancesCacheKey is the cache key for the instances list
+func (s *Service) instancesCacheKey() string {
+	return fmt.Sprintf("bigtable:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedInstance = nil
 	s.clusters = nil
@@ -139,6 +143,48 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("bigtable-instances", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.instances)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	return s.openPending()
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() tea.Cmd {
+	if i := services.FindPending(&s.pendingOpen, s.instances); i >= 0 {
+		s.selectedInstance = &s.instances[i]
+		s.viewState = ViewDetail
+		s.clusters = nil
+		return s.fetchClustersCmd(s.selectedInstance.Name)
+	}
+	return nil
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedInstance != nil {
+		loc.Resource = services.RefOf(s.selectedInstance.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -159,14 +205,15 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchInstancesCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchInstancesCmd(true)
 
 	case instancesMsg:
 		s.spinner.Stop()
 		s.instances = msg
+		s.selectedInstance = services.Reselect(s.selectedInstance, s.instances)
 		s.filterSession.Apply(s.instances)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		return s, tea.Batch(s.openPending(), core.OpenNotFound(&s.pendingOpen, s.cache, s.instancesCacheKey()), core.LastUpdatedCmd(s.cache, s.instancesCacheKey()))
 
 	case clustersMsg:
 		s.clusters = msg
@@ -180,7 +227,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -190,6 +237,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -206,10 +254,10 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
@@ -225,8 +273,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedInstance = nil
 				s.clusters = nil
@@ -243,24 +291,15 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (s *Service) fetchInstancesCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("bigtable:%s", s.projectID)
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Instance); ok {
-					return instancesMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.instancesCacheKey(), CacheTTL, force, func() ([]Instance, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListInstances(s.projectID)
+			return s.client.ListInstances(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return instancesMsg(items)
 	}
 }
@@ -292,12 +331,17 @@ func (s *Service) updateTable(items []Instance) {
 	s.table.SetRows(rows)
 }
 
+// instanceFilterFields are the fields the "/" filter understands
+var instanceFilterFields = []components.FilterField[Instance]{
+	components.TextField("name", func(i Instance) string { return i.Name }),
+	components.TextField("display", func(i Instance) string { return i.DisplayName }),
+	components.TextField("type", func(i Instance) string { return i.Type }),
+	components.TextField("state", func(i Instance) string { return i.State }),
+	components.LabelsField("label", func(i Instance) map[string]string { return i.Labels }),
+}
+
 // getFilteredInstances returns filtered instances based on the query string
 func (s *Service) getFilteredInstances(instances []Instance, query string) []Instance {
-	if query == "" {
-		return instances
-	}
-	return components.FilterSlice(instances, query, func(inst Instance, q string) bool {
-		return components.ContainsMatch(inst.Name, inst.DisplayName, inst.Type, inst.State)(q)
-	})
+	filtered, _ := components.FilterByQuery(instances, query, instanceFilterFields)
+	return filtered
 }
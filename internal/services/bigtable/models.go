@@ -1,11 +1,33 @@
 package bigtable
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Instance struct {
 	Name        string // Short ID
 	DisplayName string
 	ProjectID   string
 	State       string // READY
 	Type        string // PRODUCTION / DEVELOPMENT
+	Labels      map[string]string
+}
+
+// Meta implements services.Resource
+func (i Instance) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "bigtableadmin.googleapis.com/Instance",
+		ID:         i.Name,
+		Name:       i.Name,
+		Project:    i.ProjectID,
+		Location:   "global",
+		State:      i.State,
+		Labels:     i.Labels,
+		SelfLink:   fmt.Sprintf("projects/%s/instances/%s", i.ProjectID, i.Name),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("bigtable/instances/%s/overview", i.Name), i.ProjectID),
+	}
 }
 
 type Cluster struct {
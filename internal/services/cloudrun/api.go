@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/cloudfunctions/v2"
-	"google.golang.org/api/option"
 	run "google.golang.org/api/run/v1"
 )
 
@@ -15,7 +15,10 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	opts := []option.ClientOption{option.WithScopes(run.CloudPlatformScope)}
+	opts, err := core.ClientOptions(ctx, "run", run.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
 
 	svc, err := run.NewService(ctx, opts...)
 	if err != nil {
@@ -72,10 +75,14 @@ func (c *Client) ListServices(projectID string) ([]RunService, error) {
 		}
 
 		services = append(services, RunService{
-			Name:   name,
-			Region: region,
-			URL:    url,
-			Status: status,
+			Name:      name,
+			Region:    region,
+			URL:       url,
+			Status:    status,
+			UID:       item.Metadata.Uid,
+			ProjectID: projectID,
+			Labels:    item.Metadata.Labels,
+			SelfLink:  item.Metadata.SelfLink,
 		})
 	}
 	return services, nil
@@ -2,7 +2,10 @@ package cloudrun
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/yogirk/tgcp/internal/services"
 )
 
 // Wrapper for Cloud Functions API
@@ -15,6 +18,24 @@ type Function struct {
 	URL         string
 	LastUpdated time.Time
 	Environment string // GEN_1 or GEN_2
+	ProjectID   string
+	Labels      map[string]string
+}
+
+// Meta implements services.Resource
+func (f Function) Meta() services.ResourceMeta {
+	name := services.LastSegment(f.Name)
+	return services.ResourceMeta{
+		Kind:       "cloudfunctions.googleapis.com/Function",
+		ID:         f.Name,
+		Name:       name,
+		Project:    f.ProjectID,
+		Location:   f.Region,
+		State:      f.State,
+		Labels:     f.Labels,
+		SelfLink:   f.Name,
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("functions/details/%s/%s", f.Region, name), f.ProjectID),
+	}
 }
 
 // ListFunctions fetches cloud functions from the project
@@ -46,6 +67,8 @@ func (c *Client) ListFunctions(projectID string) ([]Function, error) {
 			URL:         url,
 			LastUpdated: updated,
 			Environment: f.Environment,
+			ProjectID:   projectID,
+			Labels:      f.Labels,
 		})
 	}
 	return results, nil
@@ -53,9 +76,11 @@ func (c *Client) ListFunctions(projectID string) ([]Function, error) {
 
 func extractRegion(fullName string) string {
 	// Format: projects/{project}/locations/{location}/functions/{function}
-	// We can cheat and just take the segment before "functions"
-	// But simpler might be to just store it if available.
-	// Let's iterate segments.
-	// This is MVP quality.
-	return "us-central1" // Placeholder or implement properly if needed
+	parts := strings.Split(fullName, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "locations" {
+			return parts[i+1]
+		}
+	}
+	return ""
 }
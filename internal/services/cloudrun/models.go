@@ -1,6 +1,11 @@
 package cloudrun
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
 
 type ServiceStatus string
 
@@ -16,4 +21,23 @@ type RunService struct {
 	URL          string
 	Status       ServiceStatus
 	LastModified time.Time
+	UID          string
+	ProjectID    string
+	Labels       map[string]string
+	SelfLink     string
+}
+
+// Meta implements services.Resource
+func (s RunService) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "run.googleapis.com/Service",
+		ID:         s.UID,
+		Name:       s.Name,
+		Project:    s.ProjectID,
+		Location:   s.Region,
+		State:      string(s.Status),
+		Labels:     s.Labels,
+		SelfLink:   s.SelfLink,
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("run/detail/%s/%s/metrics", s.Region, s.Name), s.ProjectID),
+	}
 }
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/styles"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
@@ -19,9 +22,6 @@ const CacheTTL = 30 * time.Second
 // Models
 // -----------------------------------------------------------------------------
 
-// Tick message for background refresh
-type tickMsg time.Time
-
 // ViewState defines the current UI state of the service
 type ViewState int
 
@@ -76,6 +76,9 @@ type Service struct {
 	selectedService *RunService
 	selectedFunc    *Function
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	// Cache
 	cache *core.Cache
 }
@@ -90,7 +93,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "URL", Width: 50},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("run-services"))
 
 	// 1b. Functions Table Setup
 	funcColumns := []table.Column{
@@ -99,7 +102,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "State", Width: 10},
 		{Title: "Updated", Width: 15},
 	}
-	ft := components.NewStandardTable(funcColumns)
+	ft := components.NewStandardTable(funcColumns, components.WithName("run-functions"))
 
 	svc := &Service{
 		table:     t,
@@ -110,8 +113,8 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.serviceFilterSession = components.NewFilterSession(&svc.filter, svc.getFilteredServices, svc.updateTable)
-	svc.functionFilterSession = components.NewFilterSession(&svc.filter, svc.getFilteredFunctions, svc.updateFuncTable)
+	svc.serviceFilterSession = components.NewFieldFilterSession(&svc.filter, serviceFilterFields, svc.updateTable)
+	svc.functionFilterSession = components.NewFieldFilterSession(&svc.filter, functionFilterFields, svc.updateFuncTable)
 	return svc
 }
 
@@ -128,10 +131,10 @@ func (s *Service) ShortName() string {
 // HelpText returns context-aware keybindings
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "[]:Tabs  r:Refresh  /:Filter  l:Logs  Ent:Detail"
+		return keys.HelpLine(keys.SwitchTab, keys.Refresh, keys.Filter, keys.Logs, keys.As(keys.Select, "Detail"))
 	}
 	if s.viewState == ViewDetail {
-		return "Esc/q:Back"
+		return keys.HelpLine(keys.Back)
 	}
 	return ""
 }
@@ -159,23 +162,16 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 
 // Init startup commands
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-// tick creates a background ticker for cache invalidation
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 // Refresh triggers a forced data reload
 func (s *Service) Refresh() tea.Cmd {
 	var fetchCmd tea.Cmd
 	if s.activeTab == TabServices {
-		fetchCmd = s.fetchDataCmd(true)
+		fetchCmd = core.PreloadPersisted(s.cache, s.servicesCacheKey(), func(v []RunService) tea.Msg { return servicesMsg(v) }, s.fetchDataCmd(true))
 	} else {
-		fetchCmd = s.fetchFunctionsCmd(true)
+		fetchCmd = core.PreloadPersisted(s.cache, s.functionsCacheKey(), func(v []Function) tea.Msg { return functionsMsg(v) }, s.fetchFunctionsCmd(true))
 	}
 	return tea.Batch(
 		s.spinner.Start(""),
@@ -183,8 +179,19 @@ func (s *Service) Refresh() tea.Cmd {
 	)
 }
 
+// functionsCacheKey is the cache key for the functions list
+func (s *Service) functionsCacheKey() string {
+	return "cloudrun_functions"
+}
+
+// servicesCacheKey is the cache key for the services list
+func (s *Service) servicesCacheKey() string {
+	return "cloudrun_services"
+}
+
 // Reset clears the service state when navigating away or switching projects
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedService = nil
 	s.err = nil          // CRITICAL: Always clear errors on reset
@@ -199,6 +206,94 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered services or functions, depending on
+// the active tab, for export
+func (s *Service) ExportTable() components.TableExport {
+	if s.activeTab == TabFunctions {
+		return components.NewTableExport("run-functions", s.funcTable, s.functionFilterSession.Filtered())
+	}
+	return components.NewTableExport("run-services", s.table, s.serviceFilterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return append(services.AsResources(s.services), services.AsResources(s.functions)...)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	if ref.Kind == "cloudfunctions.googleapis.com/Function" {
+		s.activeTab = TabFunctions // Refresh then loads functions
+	}
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.services); i >= 0 {
+		s.selectedService = &s.services[i]
+		s.viewState = ViewDetail
+	} else if i := services.FindPending(&s.pendingOpen, s.functions); i >= 0 {
+		s.selectedFunc = &s.functions[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// openNotFound reports a requested resource that the list of the active tab,
+// the one OpenResource chose for its kind, doesn't have
+func (s *Service) openNotFound(tab Tab, key string) tea.Cmd {
+	if s.activeTab != tab {
+		return nil
+	}
+	return core.OpenNotFound(&s.pendingOpen, s.cache, key)
+}
+
+// ResourceCommands implements services.CommandProvider
+func (s *Service) ResourceCommands() []services.ResourceCommand {
+	return services.NewResourceCommands("Logs", "logs", "View the service's logs", s.services)
+}
+
+// RunCommand implements services.CommandProvider
+func (s *Service) RunCommand(action string, ref services.ResourceRef) tea.Cmd {
+	i := services.Find(ref, s.services)
+	if i < 0 {
+		return s.OpenResource(ref)
+	}
+	s.selectedService = &s.services[i]
+	s.viewState = ViewDetail
+	if action == "logs" {
+		return logsCmd(*s.selectedService)
+	}
+	return nil
+}
+
+// logsCmd switches to the logs of a service
+func logsCmd(svc RunService) tea.Cmd {
+	// Strict quoting for filter
+	filter := fmt.Sprintf(`resource.type="cloud_run_revision" AND resource.labels.service_name="%s"`, svc.Name)
+	heading := fmt.Sprintf("Service: %s", svc.Name)
+	return func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "run", Heading: heading} }
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if s.viewState == ViewDetail && s.selectedService != nil {
+		loc.Resource = services.RefOf(s.selectedService.Meta())
+	} else if s.viewState == ViewDetail && s.selectedFunc != nil {
+		loc.Resource = services.RefOf(s.selectedFunc.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 // Focus handles input focus (Visual Highlight)
 func (s *Service) Focus() {
 	s.table.Focus()
@@ -225,28 +320,28 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return s, cmd
 
 	// 1. Background Tick
-	case tickMsg:
-		var batch []tea.Cmd
+	case core.RefreshTickMsg:
 		if s.activeTab == TabServices {
-			batch = append(batch, s.fetchDataCmd(false))
-		} else {
-			batch = append(batch, s.fetchFunctionsCmd(false))
+			return s, s.fetchDataCmd(true)
 		}
-		batch = append(batch, s.tick())
-		return s, tea.Batch(batch...)
+		return s, s.fetchFunctionsCmd(true)
 
 	// 2. Data Loaded
 	case servicesMsg:
 		s.spinner.Stop()
 		s.services = msg
+		s.selectedService = services.Reselect(s.selectedService, s.services)
 		s.serviceFilterSession.Apply(s.services)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(s.openNotFound(TabServices, s.servicesCacheKey()), core.LastUpdatedCmd(s.cache, s.servicesCacheKey()))
 
 	case functionsMsg:
 		s.spinner.Stop()
 		s.functions = msg
+		s.selectedFunc = services.Reselect(s.selectedFunc, s.functions)
 		s.functionFilterSession.Apply(s.functions)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(s.openNotFound(TabFunctions, s.functionsCacheKey()), core.LastUpdatedCmd(s.cache, s.functionsCacheKey()))
 
 	// 3. Error Handling
 	case errMsg:
@@ -260,7 +355,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.funcTable.HandleWindowSizeDefault(msg)
 
 	// 4.5 Mouse Input
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to active table for click selection
 		if s.viewState == ViewList {
 			if s.activeTab == TabServices {
@@ -277,6 +372,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// 5. User Input
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			var result components.FilterUpdateResult
@@ -298,8 +394,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "[", "]":
+			switch {
+			case key.Matches(msg, keys.SwitchTab):
 				// Switch Tab (Cycle)
 				if s.activeTab == TabServices {
 					s.activeTab = TabFunctions
@@ -309,13 +405,13 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					s.serviceFilterSession.Apply(s.services)
 					return s, nil
 				}
-			case "r":
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "/":
+			case key.Matches(msg, keys.Filter):
 				// Enter filter mode
 				cmd := s.filter.EnterFilterMode()
 				return s, cmd
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				// Handle detail view selection
 				if s.activeTab == TabServices {
 					if s.selectedService == nil {
@@ -332,15 +428,11 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						s.viewState = ViewDetail
 					}
 				}
-			case "l": // Logs
+			case key.Matches(msg, keys.Logs):
 				if s.activeTab == TabServices {
 					svcs := s.getFilteredServices(s.services, s.filter.Value())
 					if idx := s.table.Cursor(); idx >= 0 && idx < len(svcs) {
-						svc := svcs[idx]
-						// Strict quoting for filter
-						filter := fmt.Sprintf(`resource.type="cloud_run_revision" AND resource.labels.service_name="%s"`, svc.Name)
-						heading := fmt.Sprintf("Service: %s", svc.Name)
-						return s, func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "run", Heading: heading} }
+						return s, logsCmd(svcs[idx])
 					}
 				}
 			}
@@ -354,8 +446,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return s, cmd
 		} else if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "q", "esc":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedService = nil
 				s.selectedFunc = nil
@@ -499,31 +591,16 @@ func (s *Service) renderFuncDetailView() string {
 
 func (s *Service) fetchDataCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := "cloudrun_services"
-
-		// 1. Check Cache
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if svcs, ok := val.([]RunService); ok {
-					return servicesMsg(svcs)
-				}
+		svcs, err := core.GetOrFetch(s.cache, s.servicesCacheKey(), CacheTTL, force, func() ([]RunService, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not initialized")
 			}
-		}
-
-		// 2. API Call
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not initialized"))
-		}
-		svcs, err := s.client.ListServices(s.projectID)
+			return s.client.ListServices(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		// 3. Update Cache
-		if s.cache != nil {
-			s.cache.Set(key, svcs, CacheTTL)
-		}
-
 		return servicesMsg(svcs)
 	}
 }
@@ -549,24 +626,15 @@ func (s *Service) updateTable(items []RunService) {
 
 func (s *Service) fetchFunctionsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := "cloudrun_functions"
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Function); ok {
-					return functionsMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.functionsCacheKey(), CacheTTL, force, func() ([]Function, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListFunctions(s.projectID)
+			return s.client.ListFunctions(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return functionsMsg(items)
 	}
 }
@@ -586,22 +654,33 @@ func (s *Service) updateFuncTable(items []Function) {
 	s.funcTable.SetRows(rows)
 }
 
+// serviceFilterFields are the fields the "/" filter understands
+var serviceFilterFields = []components.FilterField[RunService]{
+	components.TextField("name", func(r RunService) string { return r.Name }),
+	components.TextField("region", func(r RunService) string { return r.Region }),
+	components.TextField("state", func(r RunService) string { return string(r.Status) }),
+	components.TextField("url", func(r RunService) string { return r.URL }),
+	components.LabelsField("label", func(r RunService) map[string]string { return r.Labels }),
+}
+
 // getFilteredServices returns filtered services based on the query string
 func (s *Service) getFilteredServices(services []RunService, query string) []RunService {
-	if query == "" {
-		return services
-	}
-	return components.FilterSlice(services, query, func(svc RunService, q string) bool {
-		return components.ContainsMatch(svc.Name, string(svc.Status), svc.Region, svc.URL)(q)
-	})
+	filtered, _ := components.FilterByQuery(services, query, serviceFilterFields)
+	return filtered
+}
+
+// functionFilterFields are the fields the "/" filter understands
+var functionFilterFields = []components.FilterField[Function]{
+	components.TextField("name", func(f Function) string { return f.Name }),
+	components.TextField("region", func(f Function) string { return f.Region }),
+	components.TextField("state", func(f Function) string { return f.State }),
+	components.TextField("url", func(f Function) string { return f.URL }),
+	components.TextField("env", func(f Function) string { return f.Environment }),
+	components.LabelsField("label", func(f Function) map[string]string { return f.Labels }),
 }
 
 // getFilteredFunctions returns filtered functions based on the query string
 func (s *Service) getFilteredFunctions(functions []Function, query string) []Function {
-	if query == "" {
-		return functions
-	}
-	return components.FilterSlice(functions, query, func(fn Function, q string) bool {
-		return components.ContainsMatch(fn.Name, fn.Region, fn.State, fn.URL)(q)
-	})
+	filtered, _ := components.FilterByQuery(functions, query, functionFilterFields)
+	return filtered
 }
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/yogirk/tgcp/internal/core"
-	"google.golang.org/api/option"
 	sqladmin "google.golang.org/api/sqladmin/v1beta4"
 )
 
@@ -16,12 +15,12 @@ type Client struct {
 
 // NewClient creates a new Cloud SQL client
 func NewClient(ctx context.Context) (*Client, error) {
-	httpClient, err := core.NewHTTPClient(ctx, sqladmin.SqlserviceAdminScope)
+	opts, err := core.ClientOptions(ctx, "sql", sqladmin.SqlserviceAdminScope)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create http client: %w", err)
+		return nil, err
 	}
 
-	svc, err := sqladmin.NewService(ctx, option.WithHTTPClient(httpClient))
+	svc, err := sqladmin.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sql client: %w", err)
 	}
@@ -55,12 +54,14 @@ func (c *Client) ListInstances(projectID string) ([]Instance, error) {
 			State:           InstanceState(item.State),
 			PrimaryIP:       primaryIP,
 			ConnectionName:  item.ConnectionName,
+			SelfLink:        item.SelfLink,
 		}
 
 		// Detailed mapping
 		if item.Settings != nil {
 			inst.Tier = item.Settings.Tier
 			inst.Activation = item.Settings.ActivationPolicy
+			inst.Labels = item.Settings.UserLabels
 			if item.Settings.DataDiskSizeGb > 0 {
 				inst.StorageGB = item.Settings.DataDiskSizeGb
 			}
@@ -75,24 +76,46 @@ func (c *Client) ListInstances(projectID string) ([]Instance, error) {
 	return instances, nil
 }
 
-// StopInstance stops a Cloud SQL instance by setting activation policy to NEVER
-func (c *Client) StopInstance(projectID, name string) error {
+// StopInstance stops a Cloud SQL instance by setting activation policy to NEVER.
+// It returns the sqladmin operation name.
+func (c *Client) StopInstance(projectID, name string) (string, error) {
 	rb := &sqladmin.DatabaseInstance{
 		Settings: &sqladmin.Settings{
 			ActivationPolicy: "NEVER",
 		},
 	}
-	_, err := c.service.Instances.Patch(projectID, name, rb).Do()
-	return err
+	return c.patchInstance(projectID, name, rb)
 }
 
-// StartInstance starts a Cloud SQL instance by setting activation policy to ALWAYS
-func (c *Client) StartInstance(projectID, name string) error {
+// StartInstance starts a Cloud SQL instance by setting activation policy to ALWAYS.
+// It returns the sqladmin operation name.
+func (c *Client) StartInstance(projectID, name string) (string, error) {
 	rb := &sqladmin.DatabaseInstance{
 		Settings: &sqladmin.Settings{
 			ActivationPolicy: "ALWAYS",
 		},
 	}
-	_, err := c.service.Instances.Patch(projectID, name, rb).Do()
-	return err
+	return c.patchInstance(projectID, name, rb)
+}
+
+// OperationPoller follows a sqladmin operation until its status is DONE
+func (c *Client) OperationPoller(projectID, name string) core.OperationPoller {
+	return core.StatusPoller(func(ctx context.Context) (string, string, error) {
+		op, err := c.service.Operations.Get(projectID, name).Context(ctx).Do()
+		if err != nil {
+			return "", "", err
+		}
+		if op.Error != nil && len(op.Error.Errors) > 0 {
+			return op.Status, op.Error.Errors[0].Message, nil
+		}
+		return op.Status, "", nil
+	})
+}
+
+func (c *Client) patchInstance(projectID, name string, rb *sqladmin.DatabaseInstance) (string, error) {
+	op, err := c.service.Instances.Patch(projectID, name, rb).Do()
+	if err != nil {
+		return "", err
+	}
+	return op.Name, nil
 }
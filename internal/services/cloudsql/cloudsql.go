@@ -6,17 +6,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
 const CacheTTL = 60 * time.Second
 
-// Tick message for background refresh
-type tickMsg time.Time
-
 // ViewState defines whether we are listing or viewing details
 type ViewState int
 
@@ -45,9 +45,13 @@ type Service struct {
 	viewState        ViewState
 	selectedInstance *Instance
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	// Confirmation State
 	pendingAction string    // "start" or "stop"
 	actionSource  ViewState // Where to return after confirmation
+	confirm       components.ConfirmationModel
 
 	// Cache
 	cache *core.Cache
@@ -64,7 +68,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Tier", Width: 20},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("sql-instances"))
 
 	svc := &Service{
 		table:     t,
@@ -73,7 +77,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredInstances, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, instanceFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -87,13 +91,13 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  s:Start  x:Stop  l:Logs  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.Start, keys.Stop, keys.Logs, keys.As(keys.Select, "Detail"))
 	}
 	if s.viewState == ViewDetail {
-		return "Esc/q:Back  s:Start  x:Stop"
+		return keys.HelpLine(keys.Back, keys.Start, keys.Stop)
 	}
 	if s.viewState == ViewConfirmation {
-		return "y:Confirm  n:Cancel"
+		return s.confirm.HelpText()
 	}
 	return ""
 }
@@ -123,13 +127,7 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 // Msg types
@@ -138,6 +136,10 @@ type errMsg error
 type actionResultMsg struct {
 	err error
 	msg string
+
+	// Long-running operation to follow, if the action created one
+	op   core.Operation
+	poll core.OperationPoller
 }
 
 func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -148,14 +150,23 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchInstancesCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchInstancesCmd(true)
 
 	case instancesMsg:
 		s.spinner.Stop()
 		s.instances = msg
+		s.selectedInstance = services.Reselect(s.selectedInstance, s.instances)
 		s.filterSession.Apply(s.instances)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.instancesCacheKey()), core.LastUpdatedCmd(s.cache, s.instancesCacheKey()))
+
+	case core.OperationDoneMsg:
+		// Reload the instance whose state the operation changed
+		if msg.Op.Service == s.ShortName() && msg.Op.Project == s.projectID {
+			return s, s.fetchInstancesCmd(true)
+		}
+		return s, nil
 
 	case errMsg:
 		s.spinner.Stop()
@@ -174,6 +185,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return core.ToastMsg{Message: msg.msg, Type: core.ToastSuccess}
 				},
 				s.Refresh(),
+				core.TrackOperation(msg.op, msg.poll),
 			)
 		}
 		return s, s.Refresh()
@@ -181,7 +193,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -191,6 +203,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -208,66 +221,56 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch s.viewState {
 		case ViewList:
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.fetchInstancesCmd(true)
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
 					s.viewState = ViewDetail
 				}
-			case "s": // Start
+			case key.Matches(msg, keys.Start): // Start
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
-					s.pendingAction = "start"
-					s.actionSource = ViewList
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("start", ViewList)
 				}
-			case "x": // Stop
+			case key.Matches(msg, keys.Stop): // Stop
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
-					s.pendingAction = "stop"
-					s.actionSource = ViewList
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("stop", ViewList)
 				}
-			case "l": // Logs
+			case key.Matches(msg, keys.Logs):
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(s.instances) {
-					inst := s.instances[idx]
-					// Cloud SQL filter uses database_id usually project:instance
-					filter := fmt.Sprintf(`resource.type="cloudsql_database" AND resource.labels.database_id="%s:%s"`, s.projectID, inst.Name)
-					heading := fmt.Sprintf("Database: %s", inst.Name)
-					return s, func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "sql", Heading: heading} }
+					return s, s.logsCmd(s.instances[idx])
 				}
 			}
 			s.table, cmd = s.table.Update(msg)
 			return s, cmd
 
 		case ViewDetail:
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedInstance = nil
 				return s, nil
-			case "s":
+			case key.Matches(msg, keys.Start):
 				if s.selectedInstance != nil {
-					s.pendingAction = "start"
-					s.actionSource = ViewDetail
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("start", ViewDetail)
 				}
-			case "x":
+			case key.Matches(msg, keys.Stop):
 				if s.selectedInstance != nil {
-					s.pendingAction = "stop"
-					s.actionSource = ViewDetail
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("stop", ViewDetail)
 				}
 			}
 
 		case ViewConfirmation:
-			switch msg.String() {
-			case "y", "enter":
+			var result components.ConfirmResult
+			s.confirm, result, cmd = s.confirm.HandleKey(msg)
+			switch result {
+			case components.ConfirmAccepted:
 				var actionCmd tea.Cmd
 				if s.pendingAction == "start" {
 					actionCmd = s.startInstanceCmd(*s.selectedInstance)
@@ -278,11 +281,12 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				s.pendingAction = ""
 				return s, actionCmd
 
-			case "n", "esc", "q":
+			case components.ConfirmCancelled:
 				s.viewState = s.actionSource
 				s.pendingAction = ""
 				return s, nil
 			}
+			return s, cmd
 		}
 	}
 
@@ -323,31 +327,16 @@ func (s *Service) View() string {
 // Cmd to fetch instances
 func (s *Service) fetchInstancesCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := "sql_instances"
-
-		// 1. Check Cache
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if insts, ok := val.([]Instance); ok {
-					return instancesMsg(insts)
-				}
+		insts, err := core.GetOrFetch(s.cache, s.instancesCacheKey(), CacheTTL, force, func() ([]Instance, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not initialized")
 			}
-		}
-
-		// 2. API Call
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not initialized"))
-		}
-		insts, err := s.client.ListInstances(s.projectID)
+			return s.client.ListInstances(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		// 3. Update Cache
-		if s.cache != nil {
-			s.cache.Set(key, insts, CacheTTL)
-		}
-
 		return instancesMsg(insts)
 	}
 }
@@ -355,11 +344,17 @@ func (s *Service) fetchInstancesCmd(force bool) tea.Cmd {
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchInstancesCmd(false),
+		core.PreloadPersisted(s.cache, s.instancesCacheKey(), func(v []Instance) tea.Msg { return instancesMsg(v) }, s.fetchInstancesCmd(false)),
 	)
 }
 
+// instancesCacheKey is the cache key for the instances list
+func (s *Service) instancesCacheKey() string {
+	return "sql_instances"
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedInstance = nil
 	s.err = nil // Fix: Clear previous errors on reset
@@ -367,10 +362,105 @@ func (s *Service) Reset() {
 	s.filter.ExitFilterMode()
 }
 
+// confirmAction opens the confirmation for an action on the selected
+// instance, unless read-only mode applies to this project
+func (s *Service) confirmAction(action string, source ViewState) tea.Cmd {
+	if reason, ok := core.ReadOnlyReason(s.projectID); ok {
+		return core.ReadOnlyToast(reason)
+	}
+	s.pendingAction = action
+	s.actionSource = source
+	s.confirm = components.NewConfirmationFor(action, s.selectedInstance.Name, "instance", core.IsProtected(s.projectID))
+	s.viewState = ViewConfirmation
+	return nil
+}
+
 func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("sql-instances", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.instances)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.instances); i >= 0 {
+		s.selectedInstance = &s.instances[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// ResourceCommands implements services.CommandProvider
+func (s *Service) ResourceCommands() []services.ResourceCommand {
+	var running, stopped []Instance
+	for _, inst := range s.instances {
+		if inst.Activation == "NEVER" {
+			stopped = append(stopped, inst)
+		} else {
+			running = append(running, inst)
+		}
+	}
+	var cmds []services.ResourceCommand
+	cmds = append(cmds, services.NewResourceCommands("Stop", "stop", "Stop the database instance", running)...)
+	cmds = append(cmds, services.NewResourceCommands("Start", "start", "Start the database instance", stopped)...)
+	cmds = append(cmds, services.NewResourceCommands("Logs", "logs", "View the database's logs", s.instances)...)
+	return cmds
+}
+
+// RunCommand implements services.CommandProvider
+func (s *Service) RunCommand(action string, ref services.ResourceRef) tea.Cmd {
+	i := services.Find(ref, s.instances)
+	if i < 0 {
+		return s.OpenResource(ref)
+	}
+	s.selectedInstance = &s.instances[i]
+	s.viewState = ViewDetail
+	switch action {
+	case "start", "stop":
+		return s.confirmAction(action, ViewDetail)
+	case "logs":
+		return s.logsCmd(*s.selectedInstance)
+	}
+	return nil
+}
+
+// logsCmd switches to the logs of an instance
+func (s *Service) logsCmd(inst Instance) tea.Cmd {
+	// Cloud SQL filter uses database_id usually project:instance
+	filter := fmt.Sprintf(`resource.type="cloudsql_database" AND resource.labels.database_id="%s:%s"`, s.projectID, inst.Name)
+	heading := fmt.Sprintf("Database: %s", inst.Name)
+	return func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "sql", Heading: heading} }
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedInstance != nil {
+		loc.Resource = services.RefOf(s.selectedInstance.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 // Internal Helpers
 
 func (s *Service) updateTable(instances []Instance) {
@@ -401,32 +491,62 @@ func (s *Service) updateTable(instances []Instance) {
 	s.table.SetRows(rows)
 }
 
+// instanceFilterFields are the fields the "/" filter understands
+var instanceFilterFields = []components.FilterField[Instance]{
+	components.TextField("name", func(i Instance) string { return i.Name }),
+	components.TextField("state", func(i Instance) string { return string(i.State) }),
+	components.TextField("version", func(i Instance) string { return i.DatabaseVersion }),
+	components.TextField("region", func(i Instance) string { return i.Region }),
+	components.TextField("ip", func(i Instance) string { return i.PrimaryIP }),
+	components.TextField("tier", func(i Instance) string { return i.Tier }),
+	components.NumberField("storage", func(i Instance) float64 { return float64(i.StorageGB) }),
+	components.LabelsField("label", func(i Instance) map[string]string { return i.Labels }),
+}
+
 // getFilteredInstances returns filtered instances based on the query string
 func (s *Service) getFilteredInstances(instances []Instance, query string) []Instance {
-	if query == "" {
-		return instances
-	}
-	return components.FilterSlice(instances, query, func(inst Instance, q string) bool {
-		return components.ContainsMatch(inst.Name, string(inst.State), inst.DatabaseVersion, inst.Region, inst.PrimaryIP, inst.Tier)(q)
-	})
+	filtered, _ := components.FilterByQuery(instances, query, instanceFilterFields)
+	return filtered
 }
 
 func (s *Service) startInstanceCmd(i Instance) tea.Cmd {
-	return func() tea.Msg {
-		err := s.client.StartInstance(s.projectID, i.Name)
-		if err != nil {
-			return actionResultMsg{err: err}
-		}
-		return actionResultMsg{msg: fmt.Sprintf("Starting instance %s...", i.Name)}
-	}
+	return s.instanceActionCmd(i, "start", "Starting", func(c *Client) (string, error) {
+		return c.StartInstance(s.projectID, i.Name)
+	})
 }
 
 func (s *Service) stopInstanceCmd(i Instance) tea.Cmd {
+	return s.instanceActionCmd(i, "stop", "Stopping", func(c *Client) (string, error) {
+		return c.StopInstance(s.projectID, i.Name)
+	})
+}
+
+// instanceActionCmd runs an audited action and hands the resulting sqladmin
+// operation to the operation tracker
+func (s *Service) instanceActionCmd(i Instance, action, verb string, call func(*Client) (string, error)) tea.Cmd {
 	return func() tea.Msg {
-		err := s.client.StopInstance(s.projectID, i.Name)
+		client := s.client
+		opID, err := core.RunAudited(core.AuditAction{
+			Service:  "sql",
+			Project:  s.projectID,
+			Resource: i.Name,
+			Action:   action,
+		}, func() (string, error) {
+			return call(client)
+		})
 		if err != nil {
 			return actionResultMsg{err: err}
 		}
-		return actionResultMsg{msg: fmt.Sprintf("Stopping instance %s...", i.Name)}
+		return actionResultMsg{
+			msg: fmt.Sprintf("%s instance %s...", verb, i.Name),
+			op: core.Operation{
+				ID:          opID,
+				Service:     "sql",
+				Project:     s.projectID,
+				Resource:    i.Name,
+				Description: fmt.Sprintf("%s instance %s", verb, i.Name),
+			},
+			poll: client.OperationPoller(s.projectID, opID),
+		}
 	}
 }
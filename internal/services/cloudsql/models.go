@@ -1,5 +1,11 @@
 package cloudsql
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 // InstanceState represents the status of a Cloud SQL instance
 type InstanceState string
 
@@ -27,4 +33,22 @@ type Instance struct {
 	StorageGB  int64
 	AutoBackup bool
 	Activation string // ALWAYS or NEVER
+	Labels     map[string]string
+	SelfLink   string
+}
+
+// Meta implements services.Resource
+func (i Instance) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "sqladmin.googleapis.com/Instance",
+		ID:         i.ConnectionName,
+		Name:       i.Name,
+		Project:    i.ProjectID,
+		Location:   i.Region,
+		State:      string(i.State),
+		Labels:     i.Labels,
+		Addresses:  []string{i.PrimaryIP},
+		SelfLink:   i.SelfLink,
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("sql/instances/%s/overview", i.Name), i.ProjectID),
+	}
 }
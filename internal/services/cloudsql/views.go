@@ -51,7 +51,7 @@ func (s *Service) renderConfirmation() string {
 		return "Error: No instance selected"
 	}
 
-	return components.RenderConfirmation(s.pendingAction, s.selectedInstance.Name, "instance")
+	return s.confirm.View()
 }
 
 func renderState(state InstanceState) string {
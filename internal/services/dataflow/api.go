@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/yogirk/tgcp/internal/core"
 	dataflow "google.golang.org/api/dataflow/v1b3"
 )
 
@@ -12,7 +13,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := dataflow.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "dataflow", dataflow.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := dataflow.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("dataflow client: %w", err)
 	}
@@ -37,6 +42,8 @@ func (c *Client) ListJobs(projectID string) ([]Job, error) {
 				State:      j.CurrentState,
 				CreateTime: j.CreateTime,
 				Location:   j.Location,
+				ProjectID:  projectID,
+				Labels:     j.Labels,
 			})
 		}
 		return nil
@@ -6,9 +6,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -18,8 +21,6 @@ const CacheTTL = 30 * time.Second
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -49,6 +50,9 @@ type Service struct {
 	viewState   ViewState
 	selectedJob *Job
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	cache *core.Cache
 }
 
@@ -58,9 +62,10 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Type", Width: 15},
 		{Title: "State", Width: 15},
 		{Title: "Location", Width: 10},
+		{Title: "Created", Width: 16},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("dataflow-jobs"))
 
 	svc := &Service{
 		table:     t,
@@ -69,7 +74,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredJobs, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, jobFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -83,9 +88,9 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.As(keys.Select, "Detail"))
 	}
-	return "Esc/q:Back"
+	return keys.HelpLine(keys.Back)
 }
 
 // -----------------------------------------------------------------------------
@@ -109,23 +114,23 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchJobsCmd(true),
+		core.PreloadPersisted(s.cache, s.jobsCacheKey(), func(v []Job) tea.Msg { return jobsMsg(v) }, s.fetchJobsCmd(true)),
 	)
 }
 
+// jobsCacheKey is the cache key for the jobs list
+func (s *Service) jobsCacheKey() string {
+	return fmt.Sprintf("dataflow:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedJob = nil
 	s.err = nil
@@ -137,6 +142,46 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("dataflow-jobs", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.jobs)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.jobs); i >= 0 {
+		s.selectedJob = &s.jobs[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedJob != nil {
+		loc.Resource = services.RefOf(s.selectedJob.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -157,14 +202,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchJobsCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchJobsCmd(true)
 
 	case jobsMsg:
 		s.spinner.Stop()
 		s.jobs = msg
+		s.selectedJob = services.Reselect(s.selectedJob, s.jobs)
 		s.filterSession.Apply(s.jobs)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.jobsCacheKey()), core.LastUpdatedCmd(s.cache, s.jobsCacheKey()))
 
 	case errMsg:
 		s.spinner.Stop()
@@ -174,7 +221,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -184,6 +231,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -200,10 +248,10 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				jobs := s.getFilteredJobs(s.jobs, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(jobs) {
 					s.selectedJob = &jobs[idx]
@@ -217,8 +265,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedJob = nil
 				return s, nil
@@ -234,24 +282,15 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (s *Service) fetchJobsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("dataflow:%s", s.projectID)
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Job); ok {
-					return jobsMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.jobsCacheKey(), CacheTTL, force, func() ([]Job, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListJobs(s.projectID)
+			return s.client.ListJobs(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return jobsMsg(items)
 	}
 }
@@ -267,17 +306,34 @@ func (s *Service) updateTable(items []Job) {
 			cleanType,
 			cleanState,
 			item.Location,
+			formatCreateTime(item.CreateTime),
 		}
 	}
 	s.table.SetRows(rows)
 }
 
+// formatCreateTime shortens the API's RFC 3339 timestamps for the table
+func formatCreateTime(ts string) string {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// jobFilterFields are the fields the "/" filter understands
+var jobFilterFields = []components.FilterField[Job]{
+	components.TextField("name", func(j Job) string { return j.Name }),
+	components.TextField("type", func(j Job) string { return j.Type }),
+	components.TextField("state", func(j Job) string { return j.State }),
+	components.TextField("location", func(j Job) string { return j.Location }),
+	components.TextField("id", func(j Job) string { return j.ID }),
+	components.NumberField("workers", func(j Job) float64 { return float64(j.CurrentWorkers) }),
+	components.LabelsField("label", func(j Job) map[string]string { return j.Labels }),
+}
+
 // getFilteredJobs returns filtered jobs based on the query string
 func (s *Service) getFilteredJobs(jobs []Job, query string) []Job {
-	if query == "" {
-		return jobs
-	}
-	return components.FilterSlice(jobs, query, func(job Job, q string) bool {
-		return components.ContainsMatch(job.Name, job.Type, job.State, job.Location)(q)
-	})
+	filtered, _ := components.FilterByQuery(jobs, query, jobFilterFields)
+	return filtered
 }
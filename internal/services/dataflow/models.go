@@ -1,5 +1,11 @@
 package dataflow
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Job struct {
 	ID             string
 	Name           string
@@ -8,4 +14,21 @@ type Job struct {
 	CreateTime     string
 	Location       string
 	CurrentWorkers int64 // Derived if available, or just from metric
+	ProjectID      string
+	Labels         map[string]string
+}
+
+// Meta implements services.Resource
+func (j Job) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "dataflow.googleapis.com/Job",
+		ID:         j.ID,
+		Name:       j.Name,
+		Project:    j.ProjectID,
+		Location:   j.Location,
+		State:      j.State,
+		Labels:     j.Labels,
+		SelfLink:   fmt.Sprintf("projects/%s/locations/%s/jobs/%s", j.ProjectID, j.Location, j.ID),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("dataflow/jobs/%s/%s", j.Location, j.ID), j.ProjectID),
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/dataproc/v1"
 )
 
@@ -13,7 +14,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := dataproc.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "dataproc", dataproc.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := dataproc.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("dataproc client: %w", err)
 	}
@@ -56,6 +61,9 @@ func (c *Client) ListClusters(projectID string, region string) ([]Cluster, error
 				WorkerCount:   workerCount,
 				WorkerMachine: workerType,
 				Zone:          zone,
+				Region:        region,
+				UUID:          cl.ClusterUuid,
+				Labels:        cl.Labels,
 			})
 		}
 		return nil
@@ -3,11 +3,15 @@ package dataproc
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -18,8 +22,6 @@ const DefaultRegion = "us-central1" // Simplification for MVP
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -37,6 +39,7 @@ type errMsg error
 type Service struct {
 	client    *Client
 	projectID string
+	region    string
 	table     *components.StandardTable
 
 	filter        components.FilterModel
@@ -49,6 +52,9 @@ type Service struct {
 	viewState       ViewState
 	selectedCluster *Cluster
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	cache *core.Cache
 }
 
@@ -60,7 +66,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Zone", Width: 15},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("dataproc-clusters"))
 
 	svc := &Service{
 		table:     t,
@@ -68,8 +74,9 @@ func NewService(cache *core.Cache) *Service {
 		spinner:   components.NewSpinner(),
 		viewState: ViewList,
 		cache:     cache,
+		region:    DefaultRegion,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredClusters, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, clusterFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -83,9 +90,9 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.As(keys.Select, "Detail"))
 	}
-	return "Esc/q:Back"
+	return keys.HelpLine(keys.Back)
 }
 
 // -----------------------------------------------------------------------------
@@ -102,6 +109,23 @@ func (s *Service) InitService(ctx context.Context, projectID string) error {
 	return nil
 }
 
+// SetLocation implements services.Regional
+func (s *Service) SetLocation(region, zone string) {
+	s.region = RegionFor(region, zone)
+}
+
+// RegionFor returns the region whose clusters are listed: the given one,
+// else the zone's region, else DefaultRegion
+func RegionFor(region, zone string) string {
+	switch {
+	case region != "":
+		return region
+	case strings.Contains(zone, "-"):
+		return zone[:strings.LastIndex(zone, "-")]
+	}
+	return DefaultRegion
+}
+
 // Reinit reinitializes the service with a new project ID
 func (s *Service) Reinit(ctx context.Context, projectID string) error {
 	s.Reset()
@@ -109,23 +133,23 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchClustersCmd(true),
+		core.PreloadPersisted(s.cache, s.clustersCacheKey(), func(v []Cluster) tea.Msg { return clustersMsg(v) }, s.fetchClustersCmd(true)),
 	)
 }
 
+// clustersCacheKey is the cache key for the clusters list
+func (s *Service) clustersCacheKey() string {
+	return fmt.Sprintf("dataproc:%s:%s", s.projectID, s.region)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedCluster = nil
 	s.err = nil
@@ -137,6 +161,46 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("dataproc-clusters", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.clusters)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.clusters); i >= 0 {
+		s.selectedCluster = &s.clusters[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedCluster != nil {
+		loc.Resource = services.RefOf(s.selectedCluster.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -157,14 +221,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchClustersCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchClustersCmd(true)
 
 	case clustersMsg:
 		s.spinner.Stop()
 		s.clusters = msg
+		s.selectedCluster = services.Reselect(s.selectedCluster, s.clusters)
 		s.filterSession.Apply(s.clusters)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.clustersCacheKey()), core.LastUpdatedCmd(s.cache, s.clustersCacheKey()))
 
 	case errMsg:
 		s.spinner.Stop()
@@ -174,7 +240,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -184,6 +250,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -200,10 +267,10 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				clusters := s.getFilteredClusters(s.clusters, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(clusters) {
 					s.selectedCluster = &clusters[idx]
@@ -217,8 +284,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedCluster = nil
 				return s, nil
@@ -234,24 +301,15 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (s *Service) fetchClustersCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("dataproc:%s:%s", s.projectID, DefaultRegion)
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Cluster); ok {
-					return clustersMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.clustersCacheKey(), CacheTTL, force, func() ([]Cluster, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListClusters(s.projectID, DefaultRegion)
+			return s.client.ListClusters(s.projectID, s.region)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return clustersMsg(items)
 	}
 }
@@ -269,12 +327,19 @@ func (s *Service) updateTable(items []Cluster) {
 	s.table.SetRows(rows)
 }
 
+// clusterFilterFields are the fields the "/" filter understands
+var clusterFilterFields = []components.FilterField[Cluster]{
+	components.TextField("name", func(c Cluster) string { return c.Name }),
+	components.TextField("state", func(c Cluster) string { return c.Status }),
+	components.TextField("zone", func(c Cluster) string { return c.Zone }),
+	components.TextField("region", func(c Cluster) string { return c.Region }),
+	components.TextField("machine", func(c Cluster) string { return c.MasterMachine }),
+	components.NumberField("workers", func(c Cluster) float64 { return float64(c.WorkerCount) }),
+	components.LabelsField("label", func(c Cluster) map[string]string { return c.Labels }),
+}
+
 // getFilteredClusters returns filtered clusters based on the query string
 func (s *Service) getFilteredClusters(clusters []Cluster, query string) []Cluster {
-	if query == "" {
-		return clusters
-	}
-	return components.FilterSlice(clusters, query, func(cluster Cluster, q string) bool {
-		return components.ContainsMatch(cluster.Name, cluster.Status, cluster.Zone)(q)
-	})
+	filtered, _ := components.FilterByQuery(clusters, query, clusterFilterFields)
+	return filtered
 }
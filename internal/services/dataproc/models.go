@@ -1,5 +1,11 @@
 package dataproc
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Cluster struct {
 	Name          string
 	ProjectID     string
@@ -8,4 +14,22 @@ type Cluster struct {
 	WorkerCount   int
 	WorkerMachine string
 	Zone          string
+	Region        string
+	UUID          string
+	Labels        map[string]string
+}
+
+// Meta implements services.Resource
+func (c Cluster) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "dataproc.googleapis.com/Cluster",
+		ID:         c.UUID,
+		Name:       c.Name,
+		Project:    c.ProjectID,
+		Location:   c.Region,
+		State:      c.Status,
+		Labels:     c.Labels,
+		SelfLink:   fmt.Sprintf("projects/%s/regions/%s/clusters/%s", c.ProjectID, c.Region, c.Name),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("dataproc/clusters/%s/monitoring?region=%s", c.Name, c.Region), c.ProjectID),
+	}
 }
@@ -56,7 +56,7 @@ func (s *Service) renderDetailView() string {
 		Rows: []components.KeyValue{
 			{Key: "Name", Value: c.Name},
 			{Key: "Status", Value: components.RenderStatus(c.Status)},
-			{Key: "Region", Value: DefaultRegion},
+			{Key: "Region", Value: c.Region},
 			{Key: "Zone", Value: c.Zone},
 			{Key: "Master", Value: c.MasterMachine},
 			{Key: "Workers", Value: workers},
@@ -0,0 +1,58 @@
+package disks
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/core"
+)
+
+// SnapshotDisksCmd snapshots the given disks in parallel
+func (s *Service) SnapshotDisksCmd(disks []Disk) tea.Cmd {
+	now := time.Now()
+	items := make([]core.BulkItem, len(disks))
+	for i, d := range disks {
+		d := d
+		items[i] = core.BulkItem{
+			Resource: d.Name,
+			Run: func() (core.Operation, core.OperationPoller, error) {
+				return s.snapshotDisk(d, snapshotName(d.Name, now))
+			},
+		}
+	}
+
+	description := fmt.Sprintf("Snapshotting %d disks", len(disks))
+	if len(disks) == 1 {
+		description = "Snapshotting disk " + disks[0].Name
+	}
+	return core.RunBulk("disks", s.projectID, description, items)
+}
+
+// snapshotDisk runs an audited snapshot of one disk and returns the zone
+// operation it started
+func (s *Service) snapshotDisk(d Disk, name string) (core.Operation, core.OperationPoller, error) {
+	if s.client == nil {
+		return core.Operation{}, nil, fmt.Errorf("client not initialized")
+	}
+	client := s.client
+	opID, err := core.RunAudited(core.AuditAction{
+		Service:  "disks",
+		Project:  s.projectID,
+		Resource: d.Zone + "/" + d.Name,
+		Action:   "snapshot",
+	}, func() (string, error) {
+		return client.CreateSnapshot(s.projectID, d.Zone, d.Name, name)
+	})
+	if err != nil {
+		return core.Operation{Resource: d.Name}, nil, err
+	}
+	op := core.Operation{
+		ID:          opID,
+		Service:     "disks",
+		Project:     s.projectID,
+		Resource:    d.Name,
+		Description: fmt.Sprintf("Snapshotting disk %s as %s", d.Name, name),
+	}
+	return op, core.ZoneOperationPoller(client.service, s.projectID, d.Zone, opID), nil
+}
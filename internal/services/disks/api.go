@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/compute/v1"
 )
 
@@ -13,7 +15,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := compute.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "disks", compute.ComputeScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("compute client: %w", err)
 	}
@@ -44,6 +50,10 @@ func (c *Client) ListDisks(projectID string) ([]Disk, error) {
 					LastAttachTimestamp: d.LastAttachTimestamp,
 					Users:               d.Users,
 					SourceImage:         d.SourceImage,
+					ID:                  d.Id,
+					ProjectID:           projectID,
+					Labels:              d.Labels,
+					SelfLink:            d.SelfLink,
 				})
 			}
 		}
@@ -54,3 +64,23 @@ func (c *Client) ListDisks(projectID string) ([]Disk, error) {
 
 	return disks, nil
 }
+
+// CreateSnapshot snapshots a zonal disk and returns the zone operation name
+func (c *Client) CreateSnapshot(projectID, zone, diskName, snapshotName string) (string, error) {
+	op, err := c.service.Disks.CreateSnapshot(projectID, zone, diskName, &compute.Snapshot{Name: snapshotName}).Do()
+	if err != nil {
+		return "", err
+	}
+	return op.Name, nil
+}
+
+// snapshotName names a snapshot of a disk taken at t. Snapshot names
+// follow the same rules as disk names: lowercase, at most 63 characters.
+func snapshotName(disk string, t time.Time) string {
+	suffix := t.Format("-20060102-150405")
+	base := strings.ToLower(disk)
+	if limit := 63 - len(suffix); len(base) > limit {
+		base = strings.TrimRight(base[:limit], "-")
+	}
+	return base + suffix
+}
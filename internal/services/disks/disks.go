@@ -6,9 +6,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -18,8 +21,6 @@ const CacheTTL = 30 * time.Second
 // Models & Msgs
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -51,8 +52,13 @@ type Service struct {
 	viewState    ViewState
 	selectedDisk *Disk
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	pendingAction string
 	actionSource  ViewState
+	confirm       components.ConfirmationModel
+	targets       []Disk // Disks the pending action applies to
 
 	cache *core.Cache
 }
@@ -67,7 +73,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Attached To", Width: 20},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("disks"), components.WithRowKey(0, 1))
 
 	svc := &Service{
 		table:     t,
@@ -76,7 +82,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredDisks, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, diskFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -90,13 +96,13 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.Mark, keys.Snapshot, keys.As(keys.Select, "Detail"))
 	}
 	if s.viewState == ViewDetail {
-		return "Esc/q:Back  s:Snapshot"
+		return keys.HelpLine(keys.Back, keys.Snapshot)
 	}
 	if s.viewState == ViewConfirmation {
-		return "y:Confirm  n:Cancel"
+		return s.confirm.HelpText()
 	}
 	return ""
 }
@@ -122,34 +128,129 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchDisksCmd(true),
+		core.PreloadPersisted(s.cache, s.disksCacheKey(), func(v []Disk) tea.Msg { return disksMsg(v) }, s.fetchDisksCmd(true)),
 	)
 }
 
+// disksCacheKey is the cache key for the disks list
+func (s *Service) disksCacheKey() string {
+	return fmt.Sprintf("disks:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedDisk = nil
 	s.err = nil
 	s.table.SetCursor(0)
+	s.table.ClearMarks()
+	s.targets = nil
 	s.filter.ExitFilterMode()
 }
 
+// confirmSnapshot opens the confirmation for snapshotting one or more
+// disks, unless read-only mode applies to this project
+func (s *Service) confirmSnapshot(disks []Disk, source ViewState) tea.Cmd {
+	if reason, ok := core.ReadOnlyReason(s.projectID); ok {
+		return core.ReadOnlyToast(reason)
+	}
+	s.pendingAction = "snapshot"
+	s.actionSource = source
+	s.targets = disks
+	if len(disks) == 1 {
+		s.confirm = components.NewConfirmationFor("snapshot", disks[0].Name, "disk", core.IsProtected(s.projectID))
+	} else {
+		names := make([]string, len(disks))
+		for i, d := range disks {
+			names[i] = d.Name + " (" + d.Zone + ")"
+		}
+		s.confirm = components.NewBulkConfirmation("snapshot", "disk", names, core.IsProtected(s.projectID))
+	}
+	s.viewState = ViewConfirmation
+	return nil
+}
+
+// markedDisks returns the disks marked in the filtered list
+func (s *Service) markedDisks() []Disk {
+	disks := s.getFilteredDisks(s.disks, s.filter.Value())
+	var marked []Disk
+	for _, idx := range s.table.Marked() {
+		if idx < len(disks) {
+			marked = append(marked, disks[idx])
+		}
+	}
+	return marked
+}
+
 func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("disks", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.disks)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.disks); i >= 0 {
+		s.selectedDisk = &s.disks[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// ResourceCommands implements services.CommandProvider
+func (s *Service) ResourceCommands() []services.ResourceCommand {
+	return services.NewResourceCommands("Snapshot", "snapshot", "Snapshot the disk", s.disks)
+}
+
+// RunCommand implements services.CommandProvider
+func (s *Service) RunCommand(action string, ref services.ResourceRef) tea.Cmd {
+	i := services.Find(ref, s.disks)
+	if i < 0 {
+		return s.OpenResource(ref)
+	}
+	s.selectedDisk = &s.disks[i]
+	s.viewState = ViewDetail
+	if action == "snapshot" {
+		return s.confirmSnapshot([]Disk{*s.selectedDisk}, ViewDetail)
+	}
+	return nil
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedDisk != nil {
+		loc.Resource = services.RefOf(s.selectedDisk.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -170,14 +271,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchDisksCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchDisksCmd(true)
 
 	case disksMsg:
 		s.spinner.Stop()
 		s.disks = msg
+		s.selectedDisk = services.Reselect(s.selectedDisk, s.disks)
 		s.filterSession.Apply(s.disks)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.disksCacheKey()), core.LastUpdatedCmd(s.cache, s.disksCacheKey()))
 
 	case errMsg:
 		s.spinner.Stop()
@@ -190,10 +293,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return s, nil
 
+	case core.BulkDoneMsg:
+		if msg.Service == s.ShortName() && msg.Project == s.projectID {
+			return s, s.fetchDisksCmd(true)
+		}
+		return s, nil
+
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -203,6 +312,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -219,15 +329,27 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				disks := s.getFilteredDisks(s.disks, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(disks) {
 					s.selectedDisk = &disks[idx]
 					s.viewState = ViewDetail
 				}
+			case key.Matches(msg, keys.Snapshot):
+				disks := s.markedDisks()
+				if len(disks) == 0 {
+					filtered := s.getFilteredDisks(s.disks, s.filter.Value())
+					if idx := s.table.Cursor(); idx >= 0 && idx < len(filtered) {
+						disks = filtered[idx : idx+1]
+					}
+				}
+				if len(disks) > 0 {
+					return s, s.confirmSnapshot(disks, ViewList)
+				}
+				return s, nil
 			}
 			var updatedTable *components.StandardTable
 			updatedTable, cmd = s.table.Update(msg)
@@ -236,33 +358,36 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedDisk = nil
 				return s, nil
-			case "s":
-				// Placeholder for Snapshot
-				s.pendingAction = "snapshot"
-				s.actionSource = ViewDetail
-				s.viewState = ViewConfirmation
-				return s, nil
+			case key.Matches(msg, keys.Snapshot):
+				if s.selectedDisk != nil {
+					return s, s.confirmSnapshot([]Disk{*s.selectedDisk}, ViewDetail)
+				}
 			}
 		}
 
 		if s.viewState == ViewConfirmation {
-			switch msg.String() {
-			case "y", "enter":
-				// No-op for MVP (Read-onlyish)
-				// or implement snapshot call
+			var result components.ConfirmResult
+			s.confirm, result, cmd = s.confirm.HandleKey(msg)
+			switch result {
+			case components.ConfirmAccepted:
+				actionCmd := s.SnapshotDisksCmd(s.targets)
+				s.table.ClearMarks()
 				s.viewState = s.actionSource
 				s.pendingAction = ""
-				return s, nil
-			case "n", "esc", "q":
+				s.targets = nil
+				return s, actionCmd
+			case components.ConfirmCancelled:
 				s.viewState = s.actionSource
 				s.pendingAction = ""
+				s.targets = nil
 				return s, nil
 			}
+			return s, cmd
 		}
 	}
 
@@ -287,10 +412,10 @@ func (s *Service) View() string {
 		return s.renderDetailView()
 	}
 	if s.viewState == ViewConfirmation {
-		if s.selectedDisk == nil {
+		if len(s.targets) == 0 {
 			return "Error: No disk selected"
 		}
-		return components.RenderConfirmation("snapshot", s.selectedDisk.Name, "disk")
+		return s.confirm.View()
 	}
 
 	return s.renderListView()
@@ -317,29 +442,16 @@ func (s *Service) renderListView() string {
 
 func (s *Service) fetchDisksCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("disks:%s", s.projectID)
-
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Disk); ok {
-					return disksMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.disksCacheKey(), CacheTTL, force, func() ([]Disk, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-
-		items, err := s.client.ListDisks(s.projectID)
+			return s.client.ListDisks(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
-
 		return disksMsg(items)
 	}
 }
@@ -371,12 +483,19 @@ func (s *Service) updateTable(items []Disk) {
 	s.table.SetRows(rows)
 }
 
+// diskFilterFields are the fields the "/" filter understands
+var diskFilterFields = []components.FilterField[Disk]{
+	components.TextField("name", func(d Disk) string { return d.Name }),
+	components.TextField("zone", func(d Disk) string { return d.Zone }),
+	components.TextField("type", func(d Disk) string { return d.ShortType() }),
+	components.TextField("state", func(d Disk) string { return d.Status }),
+	components.NumberField("size", func(d Disk) float64 { return float64(d.SizeGb) }),
+	components.TextField("image", func(d Disk) string { return d.SourceImage }),
+	components.LabelsField("label", func(d Disk) map[string]string { return d.Labels }),
+}
+
 // getFilteredDisks returns filtered disks based on the query string
 func (s *Service) getFilteredDisks(disks []Disk, query string) []Disk {
-	if query == "" {
-		return disks
-	}
-	return components.FilterSlice(disks, query, func(disk Disk, q string) bool {
-		return components.ContainsMatch(disk.Name, disk.Zone, disk.Type, disk.Status)(q)
-	})
+	filtered, _ := components.FilterByQuery(disks, query, diskFilterFields)
+	return filtered
 }
@@ -1,6 +1,12 @@
 package disks
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
 
 type Disk struct {
 	Name                string
@@ -11,6 +17,25 @@ type Disk struct {
 	LastAttachTimestamp string
 	Users               []string // Links to instances attached to this disk
 	SourceImage         string   // Source image if boot disk
+	ID                  uint64
+	ProjectID           string
+	Labels              map[string]string
+	SelfLink            string
+}
+
+// Meta implements services.Resource
+func (d Disk) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "compute.googleapis.com/Disk",
+		ID:         strconv.FormatUint(d.ID, 10),
+		Name:       d.Name,
+		Project:    d.ProjectID,
+		Location:   d.Zone,
+		State:      d.Status,
+		Labels:     d.Labels,
+		SelfLink:   d.SelfLink,
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("compute/disksDetail/zones/%s/disks/%s", d.Zone, d.Name), d.ProjectID),
+	}
 }
 
 // IsOrphan returns true if the disk is not attached to any instance
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/datastore/v1"
 	"google.golang.org/api/firestore/v1"
 )
@@ -15,11 +16,19 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	fsSvc, err := firestore.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "firestore", firestore.DatastoreScope)
+	if err != nil {
+		return nil, err
+	}
+	fsSvc, err := firestore.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("firestore client: %w", err)
 	}
-	dsSvc, err := datastore.NewService(ctx)
+	dsOpts, err := core.ClientOptions(ctx, "datastore", datastore.DatastoreScope)
+	if err != nil {
+		return nil, err
+	}
+	dsSvc, err := datastore.NewService(ctx, dsOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("datastore client: %w", err)
 	}
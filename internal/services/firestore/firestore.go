@@ -6,9 +6,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -18,8 +21,6 @@ const CacheTTL = 120 * time.Second // Info changes rarely
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -53,6 +54,9 @@ type Service struct {
 	viewState  ViewState
 	selectedDB *Database
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	// Datastore mode navigation
 	namespaces        []Namespace
 	selectedNamespace *Namespace
@@ -71,19 +75,19 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Created", Width: 20},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("firestore-databases"))
 
 	// Namespace table for Datastore mode
 	nsColumns := []table.Column{
 		{Title: "Namespace", Width: 50},
 	}
-	nsTable := components.NewStandardTable(nsColumns)
+	nsTable := components.NewStandardTable(nsColumns, components.WithName("firestore-namespaces"))
 
 	// Kind table for Datastore mode
 	kindColumns := []table.Column{
 		{Title: "Kind", Width: 50},
 	}
-	kindTable := components.NewStandardTable(kindColumns)
+	kindTable := components.NewStandardTable(kindColumns, components.WithName("firestore-kinds"))
 
 	svc := &Service{
 		table:     t,
@@ -94,7 +98,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredDBs, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, databaseFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -109,13 +113,13 @@ func (s *Service) ShortName() string {
 func (s *Service) HelpText() string {
 	switch s.viewState {
 	case ViewList:
-		return "r:Refresh  /:Filter  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.As(keys.Select, "Detail"))
 	case ViewNamespaces:
-		return "r:Refresh  Ent:Kinds  Esc/q:Back"
+		return keys.HelpLine(keys.Refresh, keys.As(keys.Select, "Kinds"), keys.Back)
 	case ViewKinds:
-		return "Esc/q:Back"
+		return keys.HelpLine(keys.Back)
 	default:
-		return "Esc/q:Back"
+		return keys.HelpLine(keys.Back)
 	}
 }
 
@@ -140,23 +144,23 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchDBsCmd(true),
+		core.PreloadPersisted(s.cache, s.dbsCacheKey(), func(v []Database) tea.Msg { return dbsMsg(v) }, s.fetchDBsCmd(true)),
 	)
 }
 
+// dbsCacheKey is the cache key for the dbs list
+func (s *Service) dbsCacheKey() string {
+	return fmt.Sprintf("firestore:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedDB = nil
 	s.selectedNamespace = nil
@@ -173,6 +177,50 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("firestore-databases", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.dbs)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	return s.openPending()
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() tea.Cmd {
+	if i := services.FindPending(&s.pendingOpen, s.dbs); i >= 0 {
+		s.selectedDB = &s.dbs[i]
+		if s.selectedDB.IsDatastoreMode() {
+			s.viewState = ViewNamespaces
+			return tea.Batch(s.spinner.Start("Loading namespaces..."), s.fetchNamespacesCmd())
+		}
+		s.viewState = ViewDetail
+	}
+	return nil
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedDB != nil {
+		loc.Resource = services.RefOf(s.selectedDB.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -193,14 +241,15 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchDBsCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchDBsCmd(true)
 
 	case dbsMsg:
 		s.spinner.Stop()
 		s.dbs = msg
+		s.selectedDB = services.Reselect(s.selectedDB, s.dbs)
 		s.filterSession.Apply(s.dbs)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		return s, tea.Batch(s.openPending(), core.OpenNotFound(&s.pendingOpen, s.cache, s.dbsCacheKey()), core.LastUpdatedCmd(s.cache, s.dbsCacheKey()))
 
 	case namespacesMsg:
 		s.spinner.Stop()
@@ -224,7 +273,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.nsTable.HandleWindowSizeDefault(msg)
 		s.kindTable.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to active table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -234,6 +283,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -250,10 +300,10 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				dbs := s.getFilteredDBs(s.dbs, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(dbs) {
 					s.selectedDB = &dbs[idx]
@@ -275,8 +325,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedDB = nil
 				return s, nil
@@ -284,18 +334,18 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewNamespaces {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedDB = nil
 				s.namespaces = nil
 				return s, nil
-			case "r":
+			case key.Matches(msg, keys.Refresh):
 				return s, tea.Batch(
 					s.spinner.Start("Loading namespaces..."),
 					s.fetchNamespacesCmd(),
 				)
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				if idx := s.nsTable.Cursor(); idx >= 0 && idx < len(s.namespaces) {
 					s.selectedNamespace = &s.namespaces[idx]
 					s.viewState = ViewKinds
@@ -312,8 +362,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewKinds {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewNamespaces
 				s.selectedNamespace = nil
 				s.kinds = nil
@@ -334,24 +384,15 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (s *Service) fetchDBsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("firestore:%s", s.projectID)
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Database); ok {
-					return dbsMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.dbsCacheKey(), CacheTTL, force, func() ([]Database, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListDatabases(s.projectID)
+			return s.client.ListDatabases(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return dbsMsg(items)
 	}
 }
@@ -370,14 +411,18 @@ func (s *Service) updateTable(items []Database) {
 	s.table.SetRows(rows)
 }
 
+// databaseFilterFields are the fields the "/" filter understands
+var databaseFilterFields = []components.FilterField[Database]{
+	components.TextField("name", func(d Database) string { return d.Name }),
+	components.TextField("type", func(d Database) string { return d.Type }),
+	components.TextField("location", func(d Database) string { return d.Location }),
+	components.TextField("state", func(d Database) string { return d.State }),
+}
+
 // getFilteredDBs returns filtered databases based on the query string
 func (s *Service) getFilteredDBs(dbs []Database, query string) []Database {
-	if query == "" {
-		return dbs
-	}
-	return components.FilterSlice(dbs, query, func(db Database, q string) bool {
-		return components.ContainsMatch(db.Name, db.Type, db.Location)(q)
-	})
+	filtered, _ := components.FilterByQuery(dbs, query, databaseFilterFields)
+	return filtered
 }
 
 // fetchNamespacesCmd fetches namespaces for Datastore mode database
@@ -1,5 +1,11 @@
 package firestore
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Database struct {
 	Name       string // Short ID: (default)
 	ProjectID  string
@@ -10,6 +16,20 @@ type Database struct {
 	Uid        string
 }
 
+// Meta implements services.Resource
+func (d Database) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "firestore.googleapis.com/Database",
+		ID:         d.Uid,
+		Name:       d.Name,
+		Project:    d.ProjectID,
+		Location:   d.Location,
+		State:      d.State,
+		SelfLink:   fmt.Sprintf("projects/%s/databases/%s", d.ProjectID, d.Name),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("firestore/databases/%s/data", d.Name), d.ProjectID),
+	}
+}
+
 // IsDatastoreMode returns true if this database is in Datastore mode
 func (d Database) IsDatastoreMode() bool {
 	return d.Type == "DATASTORE_MODE"
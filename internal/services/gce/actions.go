@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/core"
 	"github.com/yogirk/tgcp/internal/utils"
 )
 
@@ -13,34 +14,92 @@ import (
 type actionResultMsg struct {
 	err error
 	msg string
+
+	// Long-running operation to follow, if the action created one
+	op   core.Operation
+	poll core.OperationPoller
 }
 
 // StartInstanceCmd triggers the start operation
 func (s *Service) StartInstanceCmd(instance Instance) tea.Cmd {
-	return func() tea.Msg {
-		if s.client == nil {
-			return actionResultMsg{err: fmt.Errorf("client not initialized")}
-		}
-		err := s.client.StartInstance(s.projectID, instance.Zone, instance.Name)
-		if err != nil {
-			return actionResultMsg{err: err}
-		}
-		return actionResultMsg{msg: fmt.Sprintf("Starting instance %s...", instance.Name)}
-	}
+	return s.instanceActionCmd(instance, "start", "Starting", func(c *Client) (string, error) {
+		return c.StartInstance(s.projectID, instance.Zone, instance.Name)
+	})
 }
 
 // StopInstanceCmd triggers the stop operation
 func (s *Service) StopInstanceCmd(instance Instance) tea.Cmd {
+	return s.instanceActionCmd(instance, "stop", "Stopping", func(c *Client) (string, error) {
+		return c.StopInstance(s.projectID, instance.Zone, instance.Name)
+	})
+}
+
+// instanceActionCmd runs an audited action and hands the resulting zone
+// operation to the operation tracker
+func (s *Service) instanceActionCmd(instance Instance, action, verb string, call func(*Client) (string, error)) tea.Cmd {
 	return func() tea.Msg {
-		if s.client == nil {
-			return actionResultMsg{err: fmt.Errorf("client not initialized")}
-		}
-		err := s.client.StopInstance(s.projectID, instance.Zone, instance.Name)
+		op, poll, err := s.instanceAction(instance, action, verb, call)
 		if err != nil {
 			return actionResultMsg{err: err}
 		}
-		return actionResultMsg{msg: fmt.Sprintf("Stopping instance %s...", instance.Name)}
+		return actionResultMsg{
+			msg:  fmt.Sprintf("%s instance %s...", verb, instance.Name),
+			op:   op,
+			poll: poll,
+		}
+	}
+}
+
+// instanceAction runs an audited action on one instance and returns the
+// zone operation it started
+func (s *Service) instanceAction(instance Instance, action, verb string, call func(*Client) (string, error)) (core.Operation, core.OperationPoller, error) {
+	if s.client == nil {
+		return core.Operation{}, nil, fmt.Errorf("client not initialized")
+	}
+	client := s.client
+	opID, err := core.RunAudited(core.AuditAction{
+		Service:  "gce",
+		Project:  s.projectID,
+		Resource: instance.Zone + "/" + instance.Name,
+		Action:   action,
+	}, func() (string, error) {
+		return call(client)
+	})
+	if err != nil {
+		return core.Operation{Resource: instance.Name}, nil, err
+	}
+	op := core.Operation{
+		ID:          opID,
+		Service:     "gce",
+		Project:     s.projectID,
+		Resource:    instance.Name,
+		Description: fmt.Sprintf("%s instance %s", verb, instance.Name),
+	}
+	return op, core.ZoneOperationPoller(client.service, s.projectID, instance.Zone, opID), nil
+}
+
+// BulkInstanceCmd starts or stops several instances in parallel
+func (s *Service) BulkInstanceCmd(instances []Instance, action string) tea.Cmd {
+	verb := "Starting"
+	if action == "stop" {
+		verb = "Stopping"
+	}
+	items := make([]core.BulkItem, len(instances))
+	for i, inst := range instances {
+		inst := inst
+		items[i] = core.BulkItem{
+			Resource: inst.Name,
+			Run: func() (core.Operation, core.OperationPoller, error) {
+				return s.instanceAction(inst, action, verb, func(c *Client) (string, error) {
+					if action == "stop" {
+						return c.StopInstance(s.projectID, inst.Zone, inst.Name)
+					}
+					return c.StartInstance(s.projectID, inst.Zone, inst.Name)
+				})
+			},
+		}
 	}
+	return core.RunBulk("gce", s.projectID, fmt.Sprintf("%s %d instances", verb, len(instances)), items)
 }
 
 // SSHCmd constructs the gcloud ssh command
@@ -8,7 +8,6 @@ import (
 
 	"github.com/yogirk/tgcp/internal/core"
 	compute "google.golang.org/api/compute/v1"
-	"google.golang.org/api/option"
 )
 
 // Client wraps the GCE API service
@@ -18,12 +17,12 @@ type Client struct {
 
 // NewClient initializes a new GCE API client
 func NewClient(ctx context.Context) (*Client, error) {
-	httpClient, err := core.NewHTTPClient(ctx, compute.ComputeScope)
+	opts, err := core.ClientOptions(ctx, "gce", compute.ComputeScope)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create http client: %w", err)
+		return nil, err
 	}
 
-	svc, err := compute.NewService(ctx, option.WithHTTPClient(httpClient))
+	svc, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute service: %w", err)
 	}
@@ -112,6 +111,9 @@ func (c *Client) ListInstances(projectID string) ([]Instance, error) {
 					Tags:         inst.Tags.Items,
 					Disks:        disks,
 					OSImage:      osImage,
+					ProjectID:    projectID,
+					Labels:       inst.Labels,
+					SelfLink:     inst.SelfLink,
 				})
 			}
 		}
@@ -123,14 +125,20 @@ func (c *Client) ListInstances(projectID string) ([]Instance, error) {
 	return instances, nil
 }
 
-// StartInstance starts a stopped instance
-func (c *Client) StartInstance(projectID, zone, instanceName string) error {
-	_, err := c.service.Instances.Start(projectID, zone, instanceName).Do()
-	return err
+// StartInstance starts a stopped instance and returns the zone operation name
+func (c *Client) StartInstance(projectID, zone, instanceName string) (string, error) {
+	op, err := c.service.Instances.Start(projectID, zone, instanceName).Do()
+	if err != nil {
+		return "", err
+	}
+	return op.Name, nil
 }
 
-// StopInstance stops a running instance
-func (c *Client) StopInstance(projectID, zone, instanceName string) error {
-	_, err := c.service.Instances.Stop(projectID, zone, instanceName).Do()
-	return err
+// StopInstance stops a running instance and returns the zone operation name
+func (c *Client) StopInstance(projectID, zone, instanceName string) (string, error) {
+	op, err := c.service.Instances.Stop(projectID, zone, instanceName).Do()
+	if err != nil {
+		return "", err
+	}
+	return op.Name, nil
 }
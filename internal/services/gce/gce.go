@@ -5,16 +5,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
 const CacheTTL = 30 * time.Second
 
-// Tick message for background refresh
-type tickMsg time.Time
-
 // ViewState defines whether we are listing or viewing details
 type ViewState int
 
@@ -43,9 +43,14 @@ type Service struct {
 	viewState        ViewState
 	selectedInstance *Instance
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	// Confirmation State
 	pendingAction string    // "start" or "stop"
 	actionSource  ViewState // Where to return after confirmation
+	confirm       components.ConfirmationModel
+	bulkTargets   []Instance // Marked instances the pending action applies to
 
 	// Cache
 	cache *core.Cache
@@ -55,7 +60,7 @@ func NewService(cache *core.Cache) *Service {
 	// Table Setup
 	columns := GetGCEColumns()
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("gce-instances"), components.WithRowKey(0, 2))
 
 	svc := &Service{
 		table:     t,
@@ -64,7 +69,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredInstances, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, instanceFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -78,13 +83,13 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  s:Start  x:Stop  h:SSH  l:Logs  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.Mark, keys.Start, keys.Stop, keys.SSH, keys.Logs, keys.As(keys.Select, "Detail"))
 	}
 	if s.viewState == ViewDetail {
-		return "Esc/q:Back  s:Start  x:Stop  h:SSH"
+		return keys.HelpLine(keys.Back, keys.Start, keys.Stop, keys.SSH)
 	}
 	if s.viewState == ViewConfirmation {
-		return "y:Confirm  n:Cancel"
+		return s.confirm.HelpText()
 	}
 	return ""
 }
@@ -122,13 +127,7 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 
 // Init satisfies tea.Model interface
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 // Update handles messages specific to GCE
@@ -141,16 +140,31 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
+	case core.RefreshTickMsg:
 		// Background refresh
-		return s, tea.Batch(s.fetchInstancesCmd(false), s.tick())
+		return s, s.fetchInstancesCmd(true)
 
 	// Handle Data Fetching
 	case instancesMsg:
 		s.spinner.Stop()
 		s.instances = msg
+		s.selectedInstance = services.Reselect(s.selectedInstance, s.instances)
 		s.filterSession.Apply(s.instances)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.instancesCacheKey()), core.LastUpdatedCmd(s.cache, s.instancesCacheKey()))
+
+	case core.OperationDoneMsg:
+		// Reload the instance whose state the operation changed
+		if msg.Op.Service == s.ShortName() && msg.Op.Project == s.projectID {
+			return s, s.fetchInstancesCmd(true)
+		}
+		return s, nil
+
+	case core.BulkDoneMsg:
+		if msg.Service == s.ShortName() && msg.Project == s.projectID {
+			return s, s.fetchInstancesCmd(true)
+		}
+		return s, nil
 
 	case errMsg:
 		s.spinner.Stop()
@@ -165,12 +179,13 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return core.ToastMsg{Message: msg.err.Error(), Type: core.ToastError}
 			}
 		} else if msg.msg != "" {
-			// Show success toast and refresh
+			// Show success toast, refresh and follow the operation to completion
 			return s, tea.Batch(
 				func() tea.Msg {
 					return core.ToastMsg{Message: msg.msg, Type: core.ToastSuccess}
 				},
 				s.Refresh(),
+				core.TrackOperation(msg.op, msg.poll),
 			)
 		} else {
 			return s, s.Refresh()
@@ -182,7 +197,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Optional: We could also resize columns here based on width
 		// but let's stick to height for now to fix the "truncation" visual
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -192,6 +207,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -209,44 +225,42 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// LIST VIEW KEYBINDINGS
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.fetchInstancesCmd(true)
-			case "enter": // View Details
+			case key.Matches(msg, keys.Select): // View Details
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
 					s.viewState = ViewDetail
 				}
-			case "s": // Start (Confirm)
+			case key.Matches(msg, keys.Start): // Start (Confirm)
+				if marked := s.markedInstances(); len(marked) > 0 {
+					return s, s.confirmBulkAction("start", marked)
+				}
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
-					s.pendingAction = "start"
-					s.actionSource = ViewList
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("start", ViewList)
+				}
+			case key.Matches(msg, keys.Stop): // Stop (Confirm)
+				if marked := s.markedInstances(); len(marked) > 0 {
+					return s, s.confirmBulkAction("stop", marked)
 				}
-			case "x": // Stop (Confirm)
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
-					s.pendingAction = "stop"
-					s.actionSource = ViewList
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("stop", ViewList)
 				}
-			case "h": // SSH (Changed from Enter)
+			case key.Matches(msg, keys.SSH): // SSH (Changed from Enter)
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					return s, s.SSHCmd(instances[idx])
 				}
-			case "l": // Logs
+			case key.Matches(msg, keys.Logs):
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
-					inst := instances[idx]
-					// Filter for GCE instance logs with Strict Quoting
-					filter := fmt.Sprintf(`resource.type="gce_instance" AND resource.labels.instance_id="%s"`, inst.ID)
-					heading := fmt.Sprintf("VM Instance: %s (ID: %s)", inst.Name, inst.ID)
-					return s, func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "gce", Heading: heading} }
+					return s, logsCmd(instances[idx])
 				}
 			}
 			// Forward to table
@@ -258,25 +272,21 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// DETAIL VIEW KEYBINDINGS
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				// Return to List View
 				s.viewState = ViewList
 				s.selectedInstance = nil
 				return s, nil
-			case "s": // Start (Confirm)
+			case key.Matches(msg, keys.Start): // Start (Confirm)
 				if s.selectedInstance != nil {
-					s.pendingAction = "start"
-					s.actionSource = ViewDetail
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("start", ViewDetail)
 				}
-			case "x": // Stop (Confirm)
+			case key.Matches(msg, keys.Stop): // Stop (Confirm)
 				if s.selectedInstance != nil {
-					s.pendingAction = "stop"
-					s.actionSource = ViewDetail
-					s.viewState = ViewConfirmation
+					return s, s.confirmAction("stop", ViewDetail)
 				}
-			case "h": // SSH
+			case key.Matches(msg, keys.SSH): // SSH
 				if s.selectedInstance != nil {
 					return s, s.SSHCmd(*s.selectedInstance)
 				}
@@ -286,10 +296,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// CONFIRMATION VIEW KEYBINDINGS
 		if s.viewState == ViewConfirmation {
-			switch msg.String() {
-			case "y", "enter": // Confirm
+			var result components.ConfirmResult
+			s.confirm, result, cmd = s.confirm.HandleKey(msg)
+			switch result {
+			case components.ConfirmAccepted:
 				var actionCmd tea.Cmd
-				if s.pendingAction == "start" {
+				if len(s.bulkTargets) > 0 {
+					actionCmd = s.BulkInstanceCmd(s.bulkTargets, s.pendingAction)
+					s.bulkTargets = nil
+					s.table.ClearMarks()
+				} else if s.pendingAction == "start" {
 					actionCmd = s.StartInstanceCmd(*s.selectedInstance)
 				} else if s.pendingAction == "stop" {
 					actionCmd = s.StopInstanceCmd(*s.selectedInstance)
@@ -304,11 +320,13 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				s.pendingAction = ""
 				return s, actionCmd
 
-			case "n", "esc", "q": // Cancel
+			case components.ConfirmCancelled:
 				s.viewState = s.actionSource
 				s.pendingAction = ""
+				s.bulkTargets = nil
 				return s, nil
 			}
+			return s, cmd
 		}
 
 	// Handle Table Events (only in List View)
@@ -347,32 +365,16 @@ func (s *Service) View() string {
 // Cmd to fetch instances
 func (s *Service) fetchInstancesCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := "gce_instances"
-
-		// 1. Check Cache
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if insts, ok := val.([]Instance); ok {
-					return instancesMsg(insts)
-				}
+		insts, err := core.GetOrFetch(s.cache, s.instancesCacheKey(), CacheTTL, force, func() ([]Instance, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not initialized")
 			}
-		}
-
-		// 2. API Call
-		if s.client == nil {
-			// This can happen if Refresh is called before InitService
-			return errMsg(fmt.Errorf("client not initialized"))
-		}
-		insts, err := s.client.ListInstances(s.projectID)
+			return s.client.ListInstances(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		// 3. Update Cache
-		if s.cache != nil {
-			s.cache.Set(key, insts, CacheTTL)
-		}
-
 		return instancesMsg(insts)
 	}
 }
@@ -381,21 +383,158 @@ func (s *Service) fetchInstancesCmd(force bool) tea.Cmd {
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""), // Start animated spinner (empty = use playful messages)
-		s.fetchInstancesCmd(false), // Smart refresh
+		core.PreloadPersisted(s.cache, s.instancesCacheKey(), func(v []Instance) tea.Msg { return instancesMsg(v) }, s.fetchInstancesCmd(false)), // Smart refresh
 	)
 }
 
+// instancesCacheKey is the cache key for the instances list
+func (s *Service) instancesCacheKey() string {
+	return "gce_instances"
+}
+
 // Reset resets the service state
 // Reset resets the service state
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedInstance = nil
 	s.err = nil          // Fix: Clear previous errors on reset
 	s.table.SetCursor(0) // Optional: reset cursor to top
+	s.table.ClearMarks()
+	s.bulkTargets = nil
 	s.filter.ExitFilterMode()
 }
 
+// confirmAction opens the confirmation for an action on the selected
+// instance, unless read-only mode applies to this project
+func (s *Service) confirmAction(action string, source ViewState) tea.Cmd {
+	if reason, ok := core.ReadOnlyReason(s.projectID); ok {
+		return core.ReadOnlyToast(reason)
+	}
+	s.pendingAction = action
+	s.actionSource = source
+	s.confirm = components.NewConfirmationFor(action, s.selectedInstance.Name, "instance", core.IsProtected(s.projectID))
+	s.viewState = ViewConfirmation
+	return nil
+}
+
+// confirmBulkAction opens one confirmation for an action on all the
+// marked instances
+func (s *Service) confirmBulkAction(action string, instances []Instance) tea.Cmd {
+	if reason, ok := core.ReadOnlyReason(s.projectID); ok {
+		return core.ReadOnlyToast(reason)
+	}
+	names := make([]string, len(instances))
+	for i, inst := range instances {
+		names[i] = inst.Name + " (" + inst.Zone + ")"
+	}
+	s.pendingAction = action
+	s.actionSource = ViewList
+	s.bulkTargets = instances
+	s.confirm = components.NewBulkConfirmation(action, "instance", names, core.IsProtected(s.projectID))
+	s.viewState = ViewConfirmation
+	return nil
+}
+
+// markedInstances returns the instances marked in the filtered list
+func (s *Service) markedInstances() []Instance {
+	instances := s.getFilteredInstances(s.instances, s.filter.Value())
+	var marked []Instance
+	for _, idx := range s.table.Marked() {
+		if idx < len(instances) {
+			marked = append(marked, instances[idx])
+		}
+	}
+	return marked
+}
+
 // IsRootView checks if we are in the main list view
 func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
+
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("gce-instances", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.instances)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.instances); i >= 0 {
+		s.selectedInstance = &s.instances[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// ResourceCommands implements services.CommandProvider
+func (s *Service) ResourceCommands() []services.ResourceCommand {
+	var running, stopped []Instance
+	for _, inst := range s.instances {
+		switch inst.State {
+		case StateRunning:
+			running = append(running, inst)
+		case StateStopped, StateTerminated:
+			stopped = append(stopped, inst)
+		}
+	}
+	var cmds []services.ResourceCommand
+	cmds = append(cmds, services.NewResourceCommands("SSH", "ssh", "SSH into the VM", running)...)
+	cmds = append(cmds, services.NewResourceCommands("Stop", "stop", "Stop the VM", running)...)
+	cmds = append(cmds, services.NewResourceCommands("Start", "start", "Start the VM", stopped)...)
+	cmds = append(cmds, services.NewResourceCommands("Logs", "logs", "View the VM's logs", s.instances)...)
+	return cmds
+}
+
+// RunCommand implements services.CommandProvider
+func (s *Service) RunCommand(action string, ref services.ResourceRef) tea.Cmd {
+	i := services.Find(ref, s.instances)
+	if i < 0 {
+		return s.OpenResource(ref)
+	}
+	s.selectedInstance = &s.instances[i]
+	s.viewState = ViewDetail
+	switch action {
+	case "ssh":
+		return s.SSHCmd(*s.selectedInstance)
+	case "start", "stop":
+		return s.confirmAction(action, ViewDetail)
+	case "logs":
+		return logsCmd(*s.selectedInstance)
+	}
+	return nil
+}
+
+// logsCmd switches to the logs of an instance
+func logsCmd(inst Instance) tea.Cmd {
+	// Filter for GCE instance logs with Strict Quoting
+	filter := fmt.Sprintf(`resource.type="gce_instance" AND resource.labels.instance_id="%s"`, inst.ID)
+	heading := fmt.Sprintf("VM Instance: %s (ID: %s)", inst.Name, inst.ID)
+	return func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "gce", Heading: heading} }
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedInstance != nil {
+		loc.Resource = services.RefOf(s.selectedInstance.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
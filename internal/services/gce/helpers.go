@@ -1,6 +1,8 @@
 package gce
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
@@ -30,12 +32,21 @@ func (s *Service) updateTable(instances []Instance) {
 	// s.table.SetCursor(0) // Don't reset cursor on every update, preserves selection on refresh
 }
 
+// instanceFilterFields are the fields the "/" filter understands
+var instanceFilterFields = []components.FilterField[Instance]{
+	components.TextField("name", func(i Instance) string { return i.Name }),
+	components.TextField("zone", func(i Instance) string { return i.Zone }),
+	components.TextField("state", func(i Instance) string { return string(i.State) }),
+	components.TextField("type", func(i Instance) string { return i.MachineType }),
+	components.TextField("internal_ip", func(i Instance) string { return i.InternalIP }),
+	components.TextField("external_ip", func(i Instance) string { return i.ExternalIP }),
+	components.TextField("image", func(i Instance) string { return i.OSImage }),
+	components.TextField("tag", func(i Instance) string { return strings.Join(i.Tags, " ") }),
+	components.LabelsField("label", func(i Instance) map[string]string { return i.Labels }),
+}
+
 // getFilteredInstances returns filtered instances based on the query string
 func (s *Service) getFilteredInstances(instances []Instance, query string) []Instance {
-	if query == "" {
-		return instances
-	}
-	return components.FilterSlice(instances, query, func(inst Instance, q string) bool {
-		return components.ContainsMatch(inst.Name, inst.Zone, inst.InternalIP, inst.ExternalIP)(q)
-	})
+	filtered, _ := components.FilterByQuery(instances, query, instanceFilterFields)
+	return filtered
 }
@@ -1,6 +1,11 @@
 package gce
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
 
 // InstanceState represents the status of a VM
 type InstanceState string
@@ -37,4 +42,23 @@ type Instance struct {
 	Tags         []string
 	Disks        []Disk
 	OSImage      string
+	ProjectID    string
+	Labels       map[string]string
+	SelfLink     string
+}
+
+// Meta implements services.Resource
+func (i Instance) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "compute.googleapis.com/Instance",
+		ID:         i.ID,
+		Name:       i.Name,
+		Project:    i.ProjectID,
+		Location:   i.Zone,
+		State:      string(i.State),
+		Labels:     i.Labels,
+		Addresses:  []string{i.InternalIP, i.ExternalIP},
+		SelfLink:   i.SelfLink,
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("compute/instancesDetail/zones/%s/instances/%s", i.Zone, i.Name), i.ProjectID),
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"cloud.google.com/go/storage"
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/iterator"
 )
 
@@ -12,7 +13,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	c, err := storage.NewClient(ctx)
+	opts, err := core.ClientOptions(ctx, "gcs", storage.ScopeFullControl)
+	if err != nil {
+		return nil, err
+	}
+	c, err := storage.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +40,8 @@ func (c *Client) ListBuckets(projectID string) ([]Bucket, error) {
 			Location:     battrs.Location,
 			StorageClass: battrs.StorageClass,
 			Created:      battrs.Created,
+			ProjectID:    projectID,
+			Labels:       battrs.Labels,
 		})
 	}
 	return buckets, nil
@@ -6,10 +6,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -19,9 +22,6 @@ const CacheTTL = 5 * time.Minute // Buckets don't change often
 // Models
 // -----------------------------------------------------------------------------
 
-// Tick message for background refresh
-type tickMsg time.Time
-
 // ViewState defines the current UI state of the service
 type ViewState int
 
@@ -68,6 +68,9 @@ type Service struct {
 	selectedObject *Object
 	currentPrefix  string
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	// Cache
 	cache *core.Cache
 }
@@ -82,7 +85,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Created", Width: 20},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("gcs-buckets"))
 
 	// 1b. Object Table Setup
 	objColumns := []table.Column{
@@ -91,7 +94,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Size", Width: 10},
 		{Title: "Updated", Width: 15},
 	}
-	ot := components.NewStandardTable(objColumns)
+	ot := components.NewStandardTable(objColumns, components.WithName("gcs-objects"))
 
 	svc := &Service{
 		table:       t,
@@ -101,8 +104,8 @@ func NewService(cache *core.Cache) *Service {
 		viewState:   ViewList,
 		cache:       cache,
 	}
-	svc.bucketFilterSession = components.NewFilterSession(&svc.filter, svc.getFilteredBuckets, svc.updateTable)
-	svc.objectFilterSession = components.NewFilterSession(&svc.filter, svc.getFilteredObjects, svc.updateObjectTable)
+	svc.bucketFilterSession = components.NewFieldFilterSession(&svc.filter, bucketFilterFields, svc.updateTable)
+	svc.objectFilterSession = components.NewFieldFilterSession(&svc.filter, objectFilterFields, svc.updateObjectTable)
 	return svc
 }
 
@@ -119,13 +122,13 @@ func (s *Service) ShortName() string {
 // HelpText returns context-aware keybindings
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.As(keys.Select, "Detail"))
 	}
 	if s.viewState == ViewDetail {
-		return "Enter:Browse Objects  Esc/q:Back"
+		return keys.HelpLine(keys.As(keys.Select, "Browse Objects"), keys.Back)
 	}
 	if s.viewState == ViewObjects {
-		return "Enter:Open  Esc/q:Back/Up"
+		return keys.HelpLine(keys.As(keys.Select, "Open"), keys.As(keys.Back, "Back/Up"))
 	}
 	return ""
 }
@@ -153,26 +156,25 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 
 // Init startup commands
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-// tick creates a background ticker for cache invalidation
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 // Refresh triggers a forced data reload
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""), // Start animated spinner
-		s.fetchBucketsCmd(true),
+		core.PreloadPersisted(s.cache, s.bucketsCacheKey(), func(v []Bucket) tea.Msg { return bucketsMsg(v) }, s.fetchBucketsCmd(true)),
 	)
 }
 
+// bucketsCacheKey is the cache key for the buckets list
+func (s *Service) bucketsCacheKey() string {
+	return "gcs_buckets"
+}
+
 // Reset clears the service state when navigating away or switching projects
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedBucket = nil
 	s.err = nil          // CRITICAL: Always clear errors on reset
@@ -187,6 +189,50 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered buckets, or the objects of the open
+// bucket, for export
+func (s *Service) ExportTable() components.TableExport {
+	if s.viewState == ViewObjects {
+		return components.NewTableExport("gcs-objects", s.objectTable, s.objectFilterSession.Filtered())
+	}
+	return components.NewTableExport("gcs-buckets", s.table, s.bucketFilterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.buckets)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.buckets); i >= 0 {
+		s.selectedBucket = &s.buckets[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedBucket != nil {
+		loc.Resource = services.RefOf(s.selectedBucket.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 // Focus handles input focus (Visual Highlight)
 func (s *Service) Focus() {
 	s.table.Focus()
@@ -213,15 +259,17 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return s, cmd
 
 	// 1. Background Tick
-	case tickMsg:
-		return s, tea.Batch(s.fetchBucketsCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchBucketsCmd(true)
 
 	// 2. Data Loaded
 	case bucketsMsg:
 		s.spinner.Stop()
 		s.buckets = msg
+		s.selectedBucket = services.Reselect(s.selectedBucket, s.buckets)
 		s.bucketFilterSession.Apply(s.buckets)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.bucketsCacheKey()), core.LastUpdatedCmd(s.cache, s.bucketsCacheKey()))
 
 	case objectsMsg:
 		s.spinner.Stop()
@@ -242,7 +290,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.objectTable.HandleWindowSizeDefault(msg)
 
 	// 4.5 Mouse Input
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to active table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -258,6 +306,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// 5. User Input
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (list or object view)
 		if s.viewState == ViewList || s.viewState == ViewObjects {
 			var result components.FilterUpdateResult
@@ -279,10 +328,10 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				// Handle bucket selection -> Go to Details
 				if s.selectedBucket == nil {
 					buckets := s.getFilteredBuckets(s.buckets, s.filter.Value())
@@ -298,13 +347,13 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return s, cmd
 
 		} else if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "q", "esc":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedBucket = nil
 				s.bucketFilterSession.Apply(s.buckets)
 				return s, nil
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				// Go to Object Browser
 				s.viewState = ViewObjects
 				s.currentPrefix = ""
@@ -312,8 +361,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		} else if s.viewState == ViewObjects {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				if s.currentPrefix == "" {
 					s.viewState = ViewDetail // Back to Details
 				} else {
@@ -321,7 +370,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return s, tea.Batch(s.fetchObjectsCmd(), s.spinner.Start(""))
 				}
 				return s, nil
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				// Drill down or select
 				objs := s.objects
 				if idx := s.objectTable.Cursor(); idx >= 0 && idx < len(objs) {
@@ -442,31 +491,16 @@ func (s *Service) renderDetailView() string {
 
 func (s *Service) fetchBucketsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := "gcs_buckets"
-
-		// 1. Check Cache
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if buckets, ok := val.([]Bucket); ok {
-					return bucketsMsg(buckets)
-				}
+		buckets, err := core.GetOrFetch(s.cache, s.bucketsCacheKey(), CacheTTL, force, func() ([]Bucket, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not initialized")
 			}
-		}
-
-		// 2. API Call
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not initialized"))
-		}
-		buckets, err := s.client.ListBuckets(s.projectID)
+			return s.client.ListBuckets(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		// 3. Update Cache
-		if s.cache != nil {
-			s.cache.Set(key, buckets, CacheTTL)
-		}
-
 		return bucketsMsg(buckets)
 	}
 }
@@ -484,23 +518,31 @@ func (s *Service) updateTable(items []Bucket) {
 	s.table.SetRows(rows)
 }
 
+// bucketFilterFields are the fields the "/" filter understands
+var bucketFilterFields = []components.FilterField[Bucket]{
+	components.TextField("name", func(b Bucket) string { return b.Name }),
+	components.TextField("location", func(b Bucket) string { return b.Location }),
+	components.TextField("class", func(b Bucket) string { return b.StorageClass }),
+	components.LabelsField("label", func(b Bucket) map[string]string { return b.Labels }),
+}
+
 // getFilteredBuckets returns filtered buckets based on the query string
 func (s *Service) getFilteredBuckets(buckets []Bucket, query string) []Bucket {
-	if query == "" {
-		return buckets
-	}
-	return components.FilterSlice(buckets, query, func(bucket Bucket, q string) bool {
-		return components.ContainsMatch(bucket.Name, bucket.Location, bucket.StorageClass)(q)
-	})
+	filtered, _ := components.FilterByQuery(buckets, query, bucketFilterFields)
+	return filtered
+}
+
+// objectFilterFields are the fields the "/" filter understands
+var objectFilterFields = []components.FilterField[Object]{
+	components.TextField("name", func(o Object) string { return o.Name }),
+	components.TextField("type", func(o Object) string { return o.Type }),
+	components.NumberField("size", func(o Object) float64 { return float64(o.Size) }),
 }
 
+// getFilteredObjects returns the objects matching the filter query
 func (s *Service) getFilteredObjects(objects []Object, query string) []Object {
-	if query == "" {
-		return objects
-	}
-	return components.FilterSlice(objects, query, func(obj Object, q string) bool {
-		return components.ContainsMatch(obj.Name, obj.Type)(q)
-	})
+	filtered, _ := components.FilterByQuery(objects, query, objectFilterFields)
+	return filtered
 }
 
 func (s *Service) fetchObjectsCmd() tea.Cmd {
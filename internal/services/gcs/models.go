@@ -1,12 +1,32 @@
 package gcs
 
-import "time"
+import (
+	"time"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
 
 type Bucket struct {
 	Name         string
 	Location     string
 	StorageClass string
 	Created      time.Time
+	ProjectID    string
+	Labels       map[string]string
+}
+
+// Meta implements services.Resource
+func (b Bucket) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "storage.googleapis.com/Bucket",
+		ID:         b.Name,
+		Name:       b.Name,
+		Project:    b.ProjectID,
+		Location:   b.Location,
+		Labels:     b.Labels,
+		SelfLink:   "https://www.googleapis.com/storage/v1/b/" + b.Name,
+		ConsoleURL: services.ConsoleURL("storage/browser/"+b.Name, b.ProjectID),
+	}
 }
 
 type Object struct {
@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/container/v1"
-	"google.golang.org/api/option"
 )
 
 type Client struct {
@@ -13,7 +13,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := container.NewService(ctx, option.WithScopes(container.CloudPlatformScope))
+	opts, err := core.ClientOptions(ctx, "gke", container.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := container.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("gke client: %w", err)
 	}
@@ -46,6 +50,9 @@ func (c *Client) ListClusters(projectID string) ([]Cluster, error) {
 			NodeCount:     int(cl.CurrentNodeCount),
 			Mode:          getMode(cl),
 			SelfLink:      cl.SelfLink,
+			ID:            cl.Id,
+			ProjectID:     projectID,
+			Labels:        cl.ResourceLabels,
 			NodePools:     convertNodePools(cl.NodePools),
 		})
 	}
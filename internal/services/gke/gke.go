@@ -7,9 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -19,16 +22,12 @@ const CacheTTL = 60 * time.Second
 // Models & Msgs
 // -----------------------------------------------------------------------------
 
-// Tick message for background refresh
-type tickMsg time.Time
-
 // ViewState defines the current UI state of the service
 type ViewState int
 
 const (
 	ViewList ViewState = iota
 	ViewDetail
-	ViewConfirmation
 )
 
 // Msg types
@@ -61,9 +60,8 @@ type Service struct {
 	viewState       ViewState
 	selectedCluster *Cluster
 
-	// Confirmation State
-	pendingAction string    // e.g. "connect"
-	actionSource  ViewState // Where to return after confirmation
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
 
 	// Cache
 	cache *core.Cache
@@ -80,7 +78,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Nodes", Width: 8},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("gke-clusters"))
 
 	svc := &Service{
 		table:     t,
@@ -89,7 +87,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredClusters, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, clusterFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -103,13 +101,10 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  K:k9s  l:Logs  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.K9s, keys.Logs, keys.As(keys.Select, "Detail"))
 	}
 	if s.viewState == ViewDetail {
-		return "Esc/q:Back  K:k9s"
-	}
-	if s.viewState == ViewConfirmation {
-		return "y:Confirm  n:Cancel"
+		return keys.HelpLine(keys.Back, keys.K9s)
 	}
 	return ""
 }
@@ -135,23 +130,23 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchClustersCmd(true),
+		core.PreloadPersisted(s.cache, s.clustersCacheKey(), func(v []Cluster) tea.Msg { return clustersMsg(v) }, s.fetchClustersCmd(true)),
 	)
 }
 
+// clustersCacheKey is the cache key for the clusters list
+func (s *Service) clustersCacheKey() string {
+	return fmt.Sprintf("gke_clusters:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedCluster = nil
 	s.err = nil
@@ -163,6 +158,79 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("gke-clusters", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.clusters)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.clusters); i >= 0 {
+		s.selectedCluster = &s.clusters[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// ResourceCommands implements services.CommandProvider
+func (s *Service) ResourceCommands() []services.ResourceCommand {
+	var cmds []services.ResourceCommand
+	cmds = append(cmds, services.NewResourceCommands("k9s", "k9s", "Launch k9s on the cluster", s.clusters)...)
+	cmds = append(cmds, services.NewResourceCommands("Logs", "logs", "View the cluster's logs", s.clusters)...)
+	return cmds
+}
+
+// RunCommand implements services.CommandProvider
+func (s *Service) RunCommand(action string, ref services.ResourceRef) tea.Cmd {
+	i := services.Find(ref, s.clusters)
+	if i < 0 {
+		return s.OpenResource(ref)
+	}
+	s.selectedCluster = &s.clusters[i]
+	s.viewState = ViewDetail
+	switch action {
+	case "k9s":
+		return s.launchK9s(*s.selectedCluster)
+	case "logs":
+		return logsCmd(*s.selectedCluster)
+	}
+	return nil
+}
+
+// logsCmd switches to the logs of a cluster
+func logsCmd(c Cluster) tea.Cmd {
+	// Filter for GKE Cluster logs
+	filter := fmt.Sprintf(`resource.type="k8s_cluster" AND resource.labels.cluster_name="%s" AND resource.labels.location="%s"`, c.Name, c.Location)
+	heading := fmt.Sprintf("Cluster: %s", c.Name)
+	return func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "gke", Heading: heading} }
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedCluster != nil {
+		loc.Resource = services.RefOf(s.selectedCluster.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -183,14 +251,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchClustersCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchClustersCmd(true)
 
 	case clustersMsg:
 		s.spinner.Stop()
 		s.clusters = msg
+		s.selectedCluster = services.Reselect(s.selectedCluster, s.clusters)
 		s.filterSession.Apply(s.clusters)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.clustersCacheKey()), core.LastUpdatedCmd(s.cache, s.clustersCacheKey()))
 
 	case errMsg:
 		s.spinner.Stop()
@@ -213,7 +283,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -223,6 +293,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -240,29 +311,25 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// LIST VIEW
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				clusters := s.getFilteredClusters(s.clusters, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(clusters) {
 					s.selectedCluster = &clusters[idx]
 					s.viewState = ViewDetail
 				}
-			case "K": // Launch k9s
+			case key.Matches(msg, keys.K9s): // Launch k9s
 				clusters := s.getFilteredClusters(s.clusters, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(clusters) {
 					c := clusters[idx]
 					return s, s.launchK9s(c)
 				}
-			case "l": // Logs
+			case key.Matches(msg, keys.Logs):
 				clusters := s.getFilteredClusters(s.clusters, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(clusters) {
-					c := clusters[idx]
-					// Filter for GKE Cluster logs
-					filter := fmt.Sprintf(`resource.type="k8s_cluster" AND resource.labels.cluster_name="%s" AND resource.labels.location="%s"`, c.Name, c.Location)
-					heading := fmt.Sprintf("Cluster: %s", c.Name)
-					return s, func() tea.Msg { return core.SwitchToLogsMsg{Filter: filter, Source: "gke", Heading: heading} }
+					return s, logsCmd(clusters[idx])
 				}
 			}
 			var updatedTable *components.StandardTable
@@ -273,33 +340,17 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// DETAIL VIEW
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedCluster = nil
 				return s, nil
-			case "K": // Launch k9s
+			case key.Matches(msg, keys.K9s): // Launch k9s
 				if s.selectedCluster != nil {
 					return s, s.launchK9s(*s.selectedCluster)
 				}
 			}
 		}
-
-		// CONFIRMATION VIEW
-		// (Not currently used but ready)
-		if s.viewState == ViewConfirmation {
-			switch msg.String() {
-			case "y", "enter":
-				// s.pendingAction logic
-				s.viewState = s.actionSource
-				s.pendingAction = ""
-				return s, nil
-			case "n", "esc", "q":
-				s.viewState = s.actionSource
-				s.pendingAction = ""
-				return s, nil
-			}
-		}
 	}
 
 	return s, nil
@@ -347,29 +398,16 @@ func (s *Service) renderListView() string {
 
 func (s *Service) fetchClustersCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("gke_clusters:%s", s.projectID)
-
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Cluster); ok {
-					return clustersMsg(items)
-				}
+		clusters, err := core.GetOrFetch(s.cache, s.clustersCacheKey(), CacheTTL, force, func() ([]Cluster, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not initialized")
 			}
-		}
-
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not initialized"))
-		}
-
-		clusters, err := s.client.ListClusters(s.projectID)
+			return s.client.ListClusters(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		if s.cache != nil {
-			s.cache.Set(key, clusters, CacheTTL)
-		}
-
 		return clustersMsg(clusters)
 	}
 }
@@ -394,14 +432,22 @@ func (s *Service) updateTable(items []Cluster) {
 	s.table.SetRows(rows)
 }
 
+// clusterFilterFields are the fields the "/" filter understands
+var clusterFilterFields = []components.FilterField[Cluster]{
+	components.TextField("name", func(c Cluster) string { return c.Name }),
+	components.TextField("location", func(c Cluster) string { return c.Location }),
+	components.TextField("state", func(c Cluster) string { return c.Status }),
+	components.TextField("version", func(c Cluster) string { return c.MasterVersion }),
+	components.TextField("mode", func(c Cluster) string { return c.Mode }),
+	components.TextField("network", func(c Cluster) string { return c.Network }),
+	components.NumberField("nodes", func(c Cluster) float64 { return float64(c.NodeCount) }),
+	components.LabelsField("label", func(c Cluster) map[string]string { return c.Labels }),
+}
+
 // getFilteredClusters returns filtered clusters based on the query string
 func (s *Service) getFilteredClusters(clusters []Cluster, query string) []Cluster {
-	if query == "" {
-		return clusters
-	}
-	return components.FilterSlice(clusters, query, func(cluster Cluster, q string) bool {
-		return components.ContainsMatch(cluster.Name, cluster.Location, cluster.Status, cluster.MasterVersion)(q)
-	})
+	filtered, _ := components.FilterByQuery(clusters, query, clusterFilterFields)
+	return filtered
 }
 
 func (s *Service) launchK9s(c Cluster) tea.Cmd {
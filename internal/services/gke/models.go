@@ -1,5 +1,11 @@
 package gke
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 // -----------------------------------------------------------------------------
 // Models
 // -----------------------------------------------------------------------------
@@ -19,10 +25,30 @@ type Cluster struct {
 	// format: projects/{project}/locations/{location}/clusters/{name}
 	SelfLink string
 
+	ID        string
+	ProjectID string
+	Labels    map[string]string
+
 	// Detailed info (loaded on demand or with list if cheap)
 	NodePools []NodePool
 }
 
+// Meta implements services.Resource
+func (c Cluster) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "container.googleapis.com/Cluster",
+		ID:         c.ID,
+		Name:       c.Name,
+		Project:    c.ProjectID,
+		Location:   c.Location,
+		State:      c.Status,
+		Labels:     c.Labels,
+		Addresses:  []string{c.Endpoint},
+		SelfLink:   c.SelfLink,
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("kubernetes/clusters/details/%s/%s/details", c.Location, c.Name), c.ProjectID),
+	}
+}
+
 type NodePool struct {
 	Name             string
 	Status           string // PROVISIONING, RUNNING
@@ -6,7 +6,6 @@ import (
 
 	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/iam/v1"
-	"google.golang.org/api/option"
 )
 
 // Client handles IAM API interactions
@@ -16,12 +15,12 @@ type Client struct {
 
 // NewClient creates a new IAM API client
 func NewClient(ctx context.Context) (*Client, error) {
-	httpClient, err := core.NewHTTPClient(ctx, iam.CloudPlatformScope)
+	opts, err := core.ClientOptions(ctx, "iam", iam.CloudPlatformScope)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create http client: %w", err)
+		return nil, err
 	}
 
-	service, err := iam.NewService(ctx, option.WithHTTPClient(httpClient))
+	service, err := iam.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create iam service: %w", err)
 	}
@@ -46,6 +45,7 @@ func (c *Client) ListServiceAccounts(projectID string) ([]ServiceAccount, error)
 			Description: acc.Description,
 			Disabled:    acc.Disabled,
 			UniqueID:    acc.UniqueId,
+			ProjectID:   acc.ProjectId,
 		})
 	}
 	return accounts, nil
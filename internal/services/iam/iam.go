@@ -6,17 +6,17 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
 const CacheTTL = 5 * time.Minute
 
-// Tick message for background refresh
-type tickMsg time.Time
-
 // Service implements the generic Service interface for IAM
 type Service struct {
 	client    *Client
@@ -32,6 +32,9 @@ type Service struct {
 	viewDetail      bool
 	selectedAccount *ServiceAccount
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	// Cache
 	cache *core.Cache
 }
@@ -45,7 +48,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "ID", Width: 25},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("iam-serviceaccounts"))
 
 	return &Service{
 		table:   t,
@@ -64,9 +67,9 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewDetail {
-		return "Esc/q:Back"
+		return keys.HelpLine(keys.Back)
 	}
-	return "r:Refresh  Ent:Detail"
+	return keys.HelpLine(keys.Refresh, keys.As(keys.Select, "Detail"))
 }
 
 func (s *Service) Focus() {
@@ -94,13 +97,7 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 // Msg types
@@ -115,14 +112,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchAccountsCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchAccountsCmd(true)
 
 	case accountsMsg:
 		s.spinner.Stop()
 		s.accounts = msg
+		s.selectedAccount = services.Reselect(s.selectedAccount, s.accounts)
 		s.updateTable(msg)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.accountsCacheKey()), core.LastUpdatedCmd(s.cache, s.accountsCacheKey()))
 
 	case errMsg:
 		s.spinner.Stop()
@@ -132,7 +131,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if !s.viewDetail {
 			var updatedTable *components.StandardTable
@@ -142,20 +141,21 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		if s.viewDetail {
 			// Detail View Keybindings
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewDetail = false
 				s.selectedAccount = nil
 				return s, nil
 			}
 		} else {
 			// List View Keybindings
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.fetchAccountsCmd(true)
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(s.accounts) {
 					s.selectedAccount = &s.accounts[idx]
 					s.viewDetail = true
@@ -191,31 +191,16 @@ func (s *Service) View() string {
 // Cmd to fetch accounts
 func (s *Service) fetchAccountsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := "iam_accounts"
-
-		// 1. Check Cache
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if accs, ok := val.([]ServiceAccount); ok {
-					return accountsMsg(accs)
-				}
+		accs, err := core.GetOrFetch(s.cache, s.accountsCacheKey(), CacheTTL, force, func() ([]ServiceAccount, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not initialized")
 			}
-		}
-
-		// 2. API Call
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not initialized"))
-		}
-		accs, err := s.client.ListServiceAccounts(s.projectID)
+			return s.client.ListServiceAccounts(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		// 3. Update Cache
-		if s.cache != nil {
-			s.cache.Set(key, accs, CacheTTL)
-		}
-
 		return accountsMsg(accs)
 	}
 }
@@ -223,11 +208,17 @@ func (s *Service) fetchAccountsCmd(force bool) tea.Cmd {
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchAccountsCmd(false),
+		core.PreloadPersisted(s.cache, s.accountsCacheKey(), func(v []ServiceAccount) tea.Msg { return accountsMsg(v) }, s.fetchAccountsCmd(false)),
 	)
 }
 
+// accountsCacheKey is the cache key for the accounts list
+func (s *Service) accountsCacheKey() string {
+	return "iam_accounts"
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewDetail = false
 	s.selectedAccount = nil
 	s.err = nil // Fix: Clear previous errors on reset
@@ -238,6 +229,45 @@ func (s *Service) IsRootView() bool {
 	return !s.viewDetail
 }
 
+// ExportTable snapshots the service accounts for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("iam-serviceaccounts", s.table, s.accounts)
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.accounts)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.accounts); i >= 0 {
+		s.selectedAccount = &s.accounts[i]
+		s.viewDetail = true
+	}
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	var loc services.Location
+	if !s.IsRootView() && s.selectedAccount != nil {
+		loc.Resource = services.RefOf(s.selectedAccount.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	return s.OpenResource(loc.Resource)
+}
+
 // Internal Helpers
 
 func (s *Service) updateTable(accounts []ServiceAccount) {
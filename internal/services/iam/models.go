@@ -1,5 +1,7 @@
 package iam
 
+import "github.com/yogirk/tgcp/internal/services"
+
 // ServiceAccount represents a Google Cloud Service Account
 type ServiceAccount struct {
 	Name        string `json:"name"`
@@ -8,6 +10,25 @@ type ServiceAccount struct {
 	Description string `json:"description"`
 	Disabled    bool   `json:"disabled"`
 	UniqueID    string `json:"uniqueId"`
+	ProjectID   string `json:"projectId"`
+}
+
+// Meta implements services.Resource
+func (sa ServiceAccount) Meta() services.ResourceMeta {
+	state := "ENABLED"
+	if sa.Disabled {
+		state = "DISABLED"
+	}
+	return services.ResourceMeta{
+		Kind:       "iam.googleapis.com/ServiceAccount",
+		ID:         sa.UniqueID,
+		Name:       sa.Email,
+		Project:    sa.ProjectID,
+		Location:   "global",
+		State:      state,
+		SelfLink:   sa.Name,
+		ConsoleURL: services.ConsoleURL("iam-admin/serviceaccounts/details/"+sa.UniqueID, sa.ProjectID),
+	}
 }
 
 // PolicyMember represents a member in an IAM policy
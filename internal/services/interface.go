@@ -46,3 +46,10 @@ type Service interface {
 	// Used to determine if 'q' should exit the service or go back
 	IsRootView() bool
 }
+
+// Regional is implemented by services whose listings depend on a default
+// region or zone, e.g. the active profile's. The registry sets them before
+// InitService and Reinit.
+type Regional interface {
+	SetLocation(region, zone string)
+}
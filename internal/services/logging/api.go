@@ -8,8 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/logging/v2"
-	"google.golang.org/api/option"
 )
 
 // Regex patterns for log cleaning
@@ -36,7 +36,11 @@ type Client struct {
 // NewClient initializes a new Logging client using the v2 REST API
 func NewClient(ctx context.Context, projectID string) (*Client, error) {
     // Use ADC with Logging Read scope
-    svc, err := logging.NewService(ctx, option.WithScopes(logging.LoggingReadScope))
+    opts, err := core.ClientOptions(ctx, "logs", logging.LoggingReadScope)
+    if err != nil {
+        return nil, err
+    }
+    svc, err := logging.NewService(ctx, opts...)
     if err != nil {
         return nil, fmt.Errorf("failed to create logging service: %w", err)
     }
@@ -73,7 +77,8 @@ func (c *Client) ListEntries(
         OrderBy:       "timestamp desc", // Equivalent to NewestFirst
     })
 
-	resp, err := req.Context(ctx).Do()
+	// entries.list is a read-only POST, so it is safe to retry on 429/5xx
+	resp, err := req.Context(core.WithRetrySafe(ctx)).Do()
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to list entries: %w", err)
 	}
@@ -6,17 +6,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
 const CacheTTL = 10 * time.Second // Logs change frequently
 
-// Tick message for background refresh
-type tickMsg time.Time
-
 // Service implements the services.Service interface for Cloud Logging
 type Service struct {
 	client    *Client
@@ -60,7 +60,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Message", Width: 80},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("logs"))
 
 	s := &Service{
 		table:   t,
@@ -80,21 +80,20 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewingDetail {
-		return "Esc/q:Back"
+		return keys.HelpLine(keys.Back)
 	}
-	base := "r:Refresh  Enter:Detail"
+	back := keys.Back
 	if s.returnTo != "" {
-		base = fmt.Sprintf("Esc:Back to %s  r:Refresh  Enter:Detail", s.returnTo)
-	} else {
-		base += "  Esc/q:Back"
+		back = keys.As(keys.Back, "Back to "+s.returnTo)
 	}
+	bindings := []key.Binding{back, keys.Refresh, keys.As(keys.Select, "Detail")}
 	if len(s.tokenStack) > 0 {
-		base += "  n:Newer"
+		bindings = append(bindings, keys.NewerLogs)
 	}
 	if s.nextPageToken != "" {
-		base += "  p:Older"
+		bindings = append(bindings, keys.OlderLogs)
 	}
-	return base
+	return keys.HelpLine(bindings...)
 }
 
 // Focus handles input focus
@@ -131,18 +130,9 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 	return s.InitService(ctx, projectID)
 }
 
-// Init satisfies tea.Model interface, starts background tick
+// Init satisfies tea.Model interface
 func (s *Service) Init() tea.Cmd {
-	return tea.Batch(
-		s.tick(),
-		s.Refresh(), // Trigger first fetch
-	)
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return s.Refresh() // Trigger first fetch
 }
 
 // Update handles messages specific to Logging
@@ -154,9 +144,9 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
+	case core.RefreshTickMsg:
 		// Background refresh always fetches page 1 (empty token) to see latest
-		return s, tea.Batch(s.fetchEntriesCmd(""), s.tick())
+		return s, s.fetchEntriesCmd("")
 
 	case entriesMsg:
 		s.spinner.Stop()
@@ -176,7 +166,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Adjust message column to fill available width
 		s.adjustTableColumns()
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if !s.viewingDetail {
 			var updatedTable *components.StandardTable
@@ -187,8 +177,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if s.viewingDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewingDetail = false
 				s.selectedEntry = nil
 				return s, nil
@@ -196,18 +186,18 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return s, nil
 		}
 
-		switch msg.String() {
-		case "r":
+		switch {
+		case key.Matches(msg, keys.Refresh):
 			return s, s.Refresh()
 
-		case "enter":
+		case key.Matches(msg, keys.Select):
 			if idx := s.table.Cursor(); idx >= 0 && idx < len(s.entries) {
 				s.selectedEntry = &s.entries[idx]
 				s.viewingDetail = true
 			}
 			return s, nil
 
-		case "p": // Previous / Older (Fetch next API page)
+		case key.Matches(msg, keys.OlderLogs): // Previous / Older (Fetch next API page)
 			if s.nextPageToken != "" {
 				s.spinner.Start("")
 				// Push current token to stack (which represents state of "newer" page)
@@ -216,7 +206,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				s.currentToken = s.nextPageToken
 				return s, s.fetchEntriesCmd(s.currentToken)
 			}
-		case "n": // Next / Newer (Pop stack)
+		case key.Matches(msg, keys.NewerLogs): // Next / Newer (Pop stack)
 			if len(s.tokenStack) > 0 {
 				s.spinner.Start("")
 				// Pop last token
@@ -228,11 +218,11 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				s.currentToken = prevToken
 				return s, s.fetchEntriesCmd(s.currentToken)
 			}
-		case "esc", "q":
+		case key.Matches(msg, keys.Back):
 			if s.returnTo != "" {
-				dest := s.returnTo
-				s.returnTo = "" // Reset
-				return s, func() tea.Msg { return core.SwitchToServiceMsg{Service: dest} }
+				// Back to the exact place the logs were opened from
+				s.returnTo = ""
+				return s, func() tea.Msg { return core.NavigateBackMsg{} }
 			}
 			return s, nil
 		}
@@ -257,6 +247,19 @@ func (s *Service) SetReturnTo(service string) {
 	s.returnTo = service
 }
 
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	return services.Location{Filter: s.filter, Heading: s.heading}
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter = loc.Filter
+	s.heading = loc.Heading
+	s.returnTo = ""
+	return nil
+}
+
 // SetHeading sets the custom heading
 func (s *Service) SetHeading(heading string) {
 	s.heading = heading
@@ -360,3 +363,8 @@ func (s *Service) Reset() {
 func (s *Service) IsRootView() bool {
 	return !s.viewingDetail
 }
+
+// ExportTable snapshots the current page of log entries for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("logs", s.table, s.entries)
+}
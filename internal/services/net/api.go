@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/compute/v1"
 )
 
@@ -13,7 +14,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	s, err := compute.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "net", compute.ComputeReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+	s, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute service: %w", err)
 	}
@@ -39,6 +44,7 @@ func (c *Client) ListNetworks(projectID string) ([]Network, error) {
 				IPv4Range:   n.IPv4Range,
 				Mode:        mode,
 				GatewayIPv4: n.GatewayIPv4,
+				ProjectID:   projectID,
 			})
 		}
 		return nil
@@ -64,6 +70,9 @@ func (c *Client) ListSubnets(projectID string, networkLink string) ([]Subnet, er
 					IPCidrRange: s.IpCidrRange,
 					Gateway:     s.GatewayAddress,
 					Network:     s.Network,
+					ID:          s.Id,
+					ProjectID:   projectID,
+					SelfLink:    s.SelfLink,
 				})
 			}
 		}
@@ -121,6 +130,9 @@ func (c *Client) ListFirewalls(projectID string, networkLink string) ([]Firewall
 				Action:    action,
 				Source:    source,
 				Target:    target,
+				ID:        f.Id,
+				ProjectID: projectID,
+				SelfLink:  f.SelfLink,
 			})
 		}
 		return nil
@@ -1,5 +1,12 @@
 package net
 
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Network struct {
 	Name        string
 	ID          uint64
@@ -7,6 +14,20 @@ type Network struct {
 	IPv4Range   string // for legacy/auto mode
 	Mode        string // "AUTO", "CUSTOM", "LEGACY"
 	GatewayIPv4 string
+	ProjectID   string
+}
+
+// Meta implements services.Resource
+func (n Network) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "compute.googleapis.com/Network",
+		ID:         strconv.FormatUint(n.ID, 10),
+		Name:       n.Name,
+		Project:    n.ProjectID,
+		Location:   "global",
+		SelfLink:   n.SelfLink,
+		ConsoleURL: services.ConsoleURL("networking/networks/details/"+n.Name, n.ProjectID),
+	}
 }
 
 type Subnet struct {
@@ -15,6 +36,22 @@ type Subnet struct {
 	IPCidrRange string
 	Gateway     string
 	Network     string // link to network
+	ID          uint64
+	ProjectID   string
+	SelfLink    string
+}
+
+// Meta implements services.Resource
+func (s Subnet) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "compute.googleapis.com/Subnetwork",
+		ID:         strconv.FormatUint(s.ID, 10),
+		Name:       s.Name,
+		Project:    s.ProjectID,
+		Location:   s.Region,
+		SelfLink:   s.SelfLink,
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("networking/subnetworks/details/%s/%s", s.Region, s.Name), s.ProjectID),
+	}
 }
 
 type Firewall struct {
@@ -25,4 +62,20 @@ type Firewall struct {
 	Action    string // ALLOW, DENY
 	Source    string // Ranges or Tags
 	Target    string // Ranges or Tags
+	ID        uint64
+	ProjectID string
+	SelfLink  string
+}
+
+// Meta implements services.Resource
+func (f Firewall) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "compute.googleapis.com/Firewall",
+		ID:         strconv.FormatUint(f.ID, 10),
+		Name:       f.Name,
+		Project:    f.ProjectID,
+		Location:   "global",
+		SelfLink:   f.SelfLink,
+		ConsoleURL: services.ConsoleURL("networking/firewalls/details/"+f.Name, f.ProjectID),
+	}
 }
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/styles"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
@@ -20,8 +23,6 @@ const CacheTTL = 5 * time.Minute
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -68,6 +69,9 @@ type Service struct {
 	viewState       ViewState
 	selectedNetwork *Network
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	cache *core.Cache
 }
 
@@ -79,7 +83,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "IPv4 Range", Width: 20},
 		{Title: "Gateway", Width: 15},
 	}
-	nTable := components.NewStandardTable(nCols)
+	nTable := components.NewStandardTable(nCols, components.WithName("net-networks"))
 
 	// Subnets Table
 	sCols := []table.Column{
@@ -88,7 +92,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Range", Width: 15},
 		{Title: "Gateway", Width: 15},
 	}
-	sTable := components.NewStandardTable(sCols)
+	sTable := components.NewStandardTable(sCols, components.WithName("net-subnets"))
 
 	// Firewalls Table
 	fCols := []table.Column{
@@ -99,7 +103,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Source", Width: 20},
 		{Title: "Target", Width: 20},
 	}
-	fTable := components.NewStandardTable(fCols)
+	fTable := components.NewStandardTable(fCols, components.WithName("net-firewalls"))
 
 	return &Service{
 		networksTable:  nTable,
@@ -117,10 +121,10 @@ func (s *Service) ShortName() string { return "net" }
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "Ent:Detail  r:Refresh"
+		return keys.HelpLine(keys.As(keys.Select, "Detail"), keys.Refresh)
 	}
 	if s.viewState == ViewDetail {
-		return "[]:Switch Tab  Esc/q:Back"
+		return keys.HelpLine(keys.As(keys.SwitchTab, "Switch Tab"), keys.Back)
 	}
 	return ""
 }
@@ -146,7 +150,7 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg { return tickMsg(t) })
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
@@ -166,6 +170,7 @@ func (s *Service) Refresh() tea.Cmd {
 }
 
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedNetwork = nil
 	s.activeTab = TabSubnets
@@ -177,6 +182,54 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the networks, or the active tab of the network
+// detail, for export
+func (s *Service) ExportTable() components.TableExport {
+	if s.viewState == ViewDetail {
+		if s.activeTab == TabSubnets {
+			return components.NewTableExport("net-subnets", s.subnetsTable, s.subnets)
+		}
+		return components.NewTableExport("net-firewalls", s.firewallsTable, s.firewalls)
+	}
+	return components.NewTableExport("net-networks", s.networksTable, s.networks)
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.networks)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	return s.openPending()
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() tea.Cmd {
+	if i := services.FindPending(&s.pendingOpen, s.networks); i >= 0 {
+		s.selectedNetwork = &s.networks[i]
+		s.viewState = ViewDetail
+		s.activeTab = TabSubnets
+		return tea.Batch(s.fetchSubnetsCmd(), s.fetchFirewallsCmd(), s.spinner.Start(""))
+	}
+	return nil
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	var loc services.Location
+	if !s.IsRootView() && s.selectedNetwork != nil {
+		loc.Resource = services.RefOf(s.selectedNetwork.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.networksTable.Focus()
 	s.subnetsTable.Focus()
@@ -200,17 +253,18 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
+	case core.RefreshTickMsg:
 		if s.viewState == ViewList {
-			return s, tea.Batch(s.fetchNetworksCmd(), s.Init())
+			return s, s.fetchNetworksCmd()
 		}
-		return s, s.Init()
+		return s, nil
 
 	case networksMsg:
 		s.spinner.Stop()
 		s.networks = msg
+		s.selectedNetwork = services.Reselect(s.selectedNetwork, s.networks)
 		s.updateNetworksTable()
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		return s, tea.Batch(s.openPending(), core.OpenNotFound(&s.pendingOpen, nil), func() tea.Msg { return core.LastUpdatedMsg(time.Now()) })
 
 	case subnetsMsg:
 		s.spinner.Stop()
@@ -235,7 +289,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.subnetsTable.HandleWindowSize(msg, 9)
 		s.firewallsTable.HandleWindowSize(msg, 9)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to active table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -245,13 +299,14 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
+		s.pendingOpen.Clear()
+		switch {
+		case key.Matches(msg, keys.Refresh):
 			return s, s.Refresh()
 		}
 
 		if s.viewState == ViewList {
-			if msg.String() == "enter" {
+			if key.Matches(msg, keys.Select) {
 				if s.networksTable.Cursor() >= 0 && s.networksTable.Cursor() < len(s.networks) {
 					s.selectedNetwork = &s.networks[s.networksTable.Cursor()]
 					s.viewState = ViewDetail
@@ -265,12 +320,12 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return s, cmd
 
 		} else if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedNetwork = nil
 				return s, nil
-			case "[", "]", "tab": // Allow tab-like switching
+			case key.Matches(msg, keys.SwitchTab): // Allow tab-like switching
 				if s.activeTab == TabSubnets {
 					s.activeTab = TabFirewalls
 				} else {
@@ -7,6 +7,7 @@ import (
 
 	"cloud.google.com/go/bigquery"
 	"cloud.google.com/go/storage"
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/billingbudgets/v1"
 	"google.golang.org/api/cloudbilling/v1"
 	"google.golang.org/api/compute/v1"
@@ -27,51 +28,84 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	opts := []option.ClientOption{option.WithScopes(
+	scopes := []string{
 		cloudbilling.CloudPlatformScope,
 		"https://www.googleapis.com/auth/sqlservice.admin",
 		"https://www.googleapis.com/auth/devstorage.read_only",
 		"https://www.googleapis.com/auth/bigquery.readonly",
-	)}
+	}
+	// Each API honors its own endpoint override, shared with the service
+	// that owns it where there is one
+	opts := func(service string) ([]option.ClientOption, error) {
+		return core.ClientOptions(ctx, service, scopes...)
+	}
 
 	// Cloud Billing
-	billingSvc, err := cloudbilling.NewService(ctx, opts...)
+	billingOpts, err := opts("billing")
+	if err != nil {
+		return nil, err
+	}
+	billingSvc, err := cloudbilling.NewService(ctx, billingOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("billing client: %w", err)
 	}
 
 	// Recommender
-	recSvc, err := recommender.NewService(ctx, opts...)
+	recOpts, err := opts("recommender")
+	if err != nil {
+		return nil, err
+	}
+	recSvc, err := recommender.NewService(ctx, recOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("recommender client: %w", err)
 	}
 
 	// Billing Budgets
-	budgetSvc, err := billingbudgets.NewService(ctx, opts...)
+	budgetOpts, err := opts("budgets")
+	if err != nil {
+		return nil, err
+	}
+	budgetSvc, err := billingbudgets.NewService(ctx, budgetOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("budget client: %w", err)
 	}
 
 	// Compute Engine
-	compSvc, err := compute.NewService(ctx, opts...)
+	compOpts, err := opts("gce")
+	if err != nil {
+		return nil, err
+	}
+	compSvc, err := compute.NewService(ctx, compOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("compute client: %w", err)
 	}
 
 	// Cloud SQL
-	sqlSvc, err := sqladmin.NewService(ctx, opts...)
+	sqlOpts, err := opts("sql")
+	if err != nil {
+		return nil, err
+	}
+	sqlSvc, err := sqladmin.NewService(ctx, sqlOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("sql client: %w", err)
 	}
 
-	// GCS
-	storageClient, err := storage.NewClient(ctx, opts...)
+	// GCS (honors the gcs endpoint override so emulators are picked up here too)
+	storageOpts, err := opts("gcs")
+	if err != nil {
+		return nil, err
+	}
+	storageClient, err := storage.NewClient(ctx, storageOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("storage client: %w", err)
 	}
 
 	// BigQuery
-	bqClient, err := bigquery.NewClient(ctx, "", opts...) // Project ID empty, will infer or be passed later?
+	bqOpts, err := opts("bq")
+	if err != nil {
+		return nil, err
+	}
+	bqClient, err := bigquery.NewClient(ctx, "", bqOpts...) // Project ID empty, will infer or be passed later?
 	// Actually NewClient requires ProjectID for standard operations usually, but for listing datasets it might need it.
 	// But api.go NewClient doesn't take ProjectID. We can pass "" and use explicit project ID in calls often, or we need to pass it.
 	// Let's pass "" and handle it or note if BQ fails.
@@ -301,27 +335,9 @@ func (c *Client) GetGlobalInventory(projectID string) (ResourceInventory, error)
 
 	// 6. BigQuery Datasets
 	go func() {
-		// BQ Client might strictly need ProjectID on init, but we passed "" in NewClient.
-		// If that fails, we might need a separate BQ client init here with project ID.
-		// However, typical pattern is client.DatasetInProject(projectID).
-		// We can't list datasets without a project ID context usually.
-		// The client.Datasets(ctx) lists for the client's project.
-		// If client was init with "", this might fail or default to auth project.
-		// Let's rely on InProject if available, otherwise we might need to recreate client.
-		// NOTE: cloud.google.com/go/bigquery does not have a simple "ListDatasets(project)" on the client if client has no project.
-		// It has `client.Datasets(ctx)` which iterates datasets in the client's project.
-		// Workaround: Create a new client just for this call if needed, or rely on NewClient having correct project if we update it.
-		// Actually, let's try creating a lightweight client here if we can't switch context,
-		// OR safer: rely on iterator and `DatasetsInProject`? No such method.
-		// We will assume for now we use a new client scoped to project.
-		bq, err := bigquery.NewClient(context.Background(), projectID)
-		if err != nil {
-			ch <- result{typ: "datasets", err: err}
-			return
-		}
-		defer bq.Close()
-
-		it := bq.Datasets(context.Background())
+		// The shared client has no project of its own; the iterator takes one
+		it := c.bigquery.Datasets(context.Background())
+		it.ProjectID = projectID
 		var count int
 		for {
 			_, err := it.Next()
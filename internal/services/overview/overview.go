@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
 )
 
 const CacheTTL = 15 * time.Minute // Longer TTL for billing/inventory data
@@ -43,7 +45,7 @@ func (s *Service) ShortName() string {
 }
 
 func (s *Service) HelpText() string {
-	return "r:Refresh"
+	return keys.HelpLine(keys.Refresh)
 }
 
 func (s *Service) Refresh() tea.Cmd {
@@ -133,8 +135,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.data.BudgetsLoading = false
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
+		switch {
+		case key.Matches(msg, keys.Refresh):
 			return s, s.Refresh()
 		}
 	}
@@ -170,22 +172,14 @@ func (s *Service) fetchRecsCmd() tea.Cmd {
 			return RecsMsg{}
 		}
 
-		// Cache Check
 		cacheKey := fmt.Sprintf("billing:recs:%s", s.projectID)
-		if val, found := s.cache.Get(cacheKey); found {
-			if recs, ok := val.([]Recommendation); ok {
-				return RecsMsg(recs)
-			}
-		}
-
-		recs, err := s.client.GetRecommendations(s.projectID, "")
+		recs, err := core.GetOrFetch(s.cache, cacheKey, CacheTTL, false, func() ([]Recommendation, error) {
+			return s.client.GetRecommendations(s.projectID, "")
+		})
 		if err != nil {
 			return RecsMsg{}
 		}
 
-		// Cache Set
-		s.cache.Set(cacheKey, recs, CacheTTL)
-
 		return RecsMsg(recs)
 	}
 }
@@ -196,22 +190,14 @@ func (s *Service) fetchInventoryCmd() tea.Cmd {
 			return InventoryMsg{}
 		}
 
-		// Cache Check
 		cacheKey := fmt.Sprintf("billing:inventory:global:%s", s.projectID)
-		if val, found := s.cache.Get(cacheKey); found {
-			if inv, ok := val.(ResourceInventory); ok {
-				return InventoryMsg(inv)
-			}
-		}
-
-		inv, err := s.client.GetGlobalInventory(s.projectID)
+		inv, err := core.GetOrFetch(s.cache, cacheKey, CacheTTL, false, func() (ResourceInventory, error) {
+			return s.client.GetGlobalInventory(s.projectID)
+		})
 		if err != nil {
 			return InventoryMsg{}
 		}
 
-		// Cache Set
-		s.cache.Set(cacheKey, inv, CacheTTL)
-
 		return InventoryMsg(inv)
 	}
 }
@@ -223,19 +209,13 @@ func (s *Service) fetchBudgetsCmd(billingAccountID string) tea.Cmd {
 		}
 
 		cacheKey := fmt.Sprintf("billing:budgets:%s", billingAccountID)
-		if val, found := s.cache.Get(cacheKey); found {
-			if b, ok := val.([]SpendLimit); ok {
-				return BudgetsMsg(b)
-			}
-		}
-
-		budgets, err := s.client.GetBudgets(billingAccountID)
+		budgets, err := core.GetOrFetch(s.cache, cacheKey, CacheTTL, false, func() ([]SpendLimit, error) {
+			return s.client.GetBudgets(billingAccountID)
+		})
 		if err != nil {
 			return BudgetsMsg{}
 		}
 
-		s.cache.Set(cacheKey, budgets, CacheTTL)
-
 		return BudgetsMsg(budgets)
 	}
 }
@@ -9,28 +9,19 @@ import (
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
-// Styles specific to billing dashboard
-var (
-	cardStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(styles.ColorTextMuted).
-			Padding(0, 1).
-			MarginBottom(1)
-
-	titleStyle = lipgloss.NewStyle().
-			Foreground(styles.ColorBrandAccent).
-			Bold(true).
-			MarginBottom(1)
-
-	labelStyle = lipgloss.NewStyle().
-			Foreground(styles.ColorTextMuted).
-			Width(15)
-
-	valueStyle = lipgloss.NewStyle().
-			Foreground(styles.ColorTextPrimary)
-)
-
 func (s *Service) View() string {
+	// Styles specific to billing dashboard, built here to follow the theme
+	cardStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorTextMuted).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(styles.ColorBrandAccent).
+		Bold(true).
+		MarginBottom(1)
+
 	if s.data.Error != nil {
 		return components.RenderError(s.data.Error, "Overview", "Project Overview")
 	}
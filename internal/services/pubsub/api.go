@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/pubsub/v1"
 )
 
@@ -13,7 +14,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := pubsub.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "pubsub", pubsub.PubsubScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := pubsub.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("pubsub client: %w", err)
 	}
@@ -63,6 +68,8 @@ func (c *Client) ListSubscriptions(projectID string) ([]Subscription, error) {
 				RetentionDuration: s.MessageRetentionDuration,
 				DeadLetterTopic:   dlTopic,
 				State:             s.State,
+				ProjectID:         projectID,
+				Labels:            s.Labels,
 			})
 		}
 		return nil
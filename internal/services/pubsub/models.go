@@ -1,5 +1,11 @@
 package pubsub
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Topic struct {
 	Name           string // Short name
 	ProjectID      string
@@ -8,6 +14,20 @@ type Topic struct {
 	MessageStorage string // Config info
 }
 
+// Meta implements services.Resource
+func (t Topic) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "pubsub.googleapis.com/Topic",
+		ID:         t.Name,
+		Name:       t.Name,
+		Project:    t.ProjectID,
+		Location:   "global",
+		Labels:     t.Labels,
+		SelfLink:   fmt.Sprintf("projects/%s/topics/%s", t.ProjectID, t.Name),
+		ConsoleURL: services.ConsoleURL("cloudpubsub/topic/detail/"+t.Name, t.ProjectID),
+	}
+}
+
 type Subscription struct {
 	Name              string
 	Topic             string
@@ -17,4 +37,21 @@ type Subscription struct {
 	RetentionDuration string
 	DeadLetterTopic   string // Alerting
 	State             string // Active/ResourceError/etc
+	ProjectID         string
+	Labels            map[string]string
+}
+
+// Meta implements services.Resource
+func (s Subscription) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "pubsub.googleapis.com/Subscription",
+		ID:         s.Name,
+		Name:       s.Name,
+		Project:    s.ProjectID,
+		Location:   "global",
+		State:      s.State,
+		Labels:     s.Labels,
+		SelfLink:   fmt.Sprintf("projects/%s/subscriptions/%s", s.ProjectID, s.Name),
+		ConsoleURL: services.ConsoleURL("cloudpubsub/subscription/detail/"+s.Name, s.ProjectID),
+	}
 }
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -17,8 +20,6 @@ const CacheTTL = 60 * time.Second
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -55,6 +56,9 @@ type Service struct {
 	selectedTopic *Topic
 	selectedSub   *Subscription
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	cache *core.Cache
 }
 
@@ -65,7 +69,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "KMS Key", Width: 30},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("pubsub-topics"))
 
 	svc := &Service{
 		table:     t,
@@ -74,8 +78,8 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewListTopics,
 		cache:     cache,
 	}
-	svc.topicFilterSession = components.NewFilterSession(&svc.filter, svc.getFilteredTopics, svc.updateTopicTable)
-	svc.subFilterSession = components.NewFilterSession(&svc.filter, svc.getFilteredSubs, svc.updateSubTable)
+	svc.topicFilterSession = components.NewFieldFilterSession(&svc.filter, topicFilterFields, svc.updateTopicTable)
+	svc.subFilterSession = components.NewFieldFilterSession(&svc.filter, subscriptionFilterFields, svc.updateSubTable)
 	return svc
 }
 
@@ -89,12 +93,12 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewListTopics {
-		return "r:Refresh  /:Filter  s:Switch to Subs  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.ShowSubscriptions, keys.As(keys.Select, "Detail"))
 	}
 	if s.viewState == ViewListSubs {
-		return "r:Refresh  /:Filter  t:Switch to Topics  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.ShowTopics, keys.As(keys.Select, "Detail"))
 	}
-	return "Esc/q:Back"
+	return keys.HelpLine(keys.Back)
 }
 
 // -----------------------------------------------------------------------------
@@ -118,24 +122,29 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchTopicsCmd(true),
-		s.fetchSubsCmd(true),
+		core.PreloadPersisted(s.cache, s.topicsCacheKey(), func(v []Topic) tea.Msg { return topicsMsg(v) }, s.fetchTopicsCmd(true)),
+		core.PreloadPersisted(s.cache, s.subsCacheKey(), func(v []Subscription) tea.Msg { return subsMsg(v) }, s.fetchSubsCmd(true)),
 	)
 }
 
+// subsCacheKey is the cache key for the subs list
+func (s *Service) subsCacheKey() string {
+	return fmt.Sprintf("pubsub_subs:%s", s.projectID)
+}
+
+// topicsCacheKey is the cache key for the topics list
+func (s *Service) topicsCacheKey() string {
+	return fmt.Sprintf("pubsub_topics:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewListTopics
 	s.selectedTopic = nil
 	s.selectedSub = nil
@@ -149,6 +158,60 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewListTopics || s.viewState == ViewListSubs
 }
 
+// ExportTable snapshots the filtered topics or subscriptions for export
+func (s *Service) ExportTable() components.TableExport {
+	if s.viewState == ViewListSubs || s.viewState == ViewDetailSub {
+		return components.NewTableExport("pubsub-subscriptions", s.table, s.subFilterSession.Filtered())
+	}
+	return components.NewTableExport("pubsub-topics", s.table, s.topicFilterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return append(services.AsResources(s.topics), services.AsResources(s.subs)...)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.topics); i >= 0 {
+		s.selectedTopic = &s.topics[i]
+		s.viewState = ViewDetailTopic
+	} else if i := services.FindPending(&s.pendingOpen, s.subs); i >= 0 {
+		s.selectedSub = &s.subs[i]
+		s.viewState = ViewDetailSub
+	}
+}
+
+// openNotFound reports a requested resource that neither list has, once
+// both have loaded
+func (s *Service) openNotFound() tea.Cmd {
+	return core.OpenNotFound(&s.pendingOpen, s.cache, s.topicsCacheKey(), s.subsCacheKey())
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if s.viewState == ViewDetailTopic && s.selectedTopic != nil {
+		loc.Resource = services.RefOf(s.selectedTopic.Meta())
+	} else if s.viewState == ViewDetailSub && s.selectedSub != nil {
+		loc.Resource = services.RefOf(s.selectedSub.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -169,24 +232,28 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchTopicsCmd(false), s.fetchSubsCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, tea.Batch(s.fetchTopicsCmd(true), s.fetchSubsCmd(true))
 
 	case topicsMsg:
 		s.topics = msg
+		s.selectedTopic = services.Reselect(s.selectedTopic, s.topics)
+		s.openPending()
 		if s.viewState == ViewListTopics {
 			s.spinner.Stop()
 			s.topicFilterSession.Apply(s.topics)
 		}
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		return s, tea.Batch(s.openNotFound(), core.LastUpdatedCmd(s.cache, s.topicsCacheKey()))
 
 	case subsMsg:
 		s.subs = msg
+		s.selectedSub = services.Reselect(s.selectedSub, s.subs)
+		s.openPending()
 		if s.viewState == ViewListSubs {
 			s.spinner.Stop()
 			s.subFilterSession.Apply(s.subs)
 		}
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		return s, tea.Batch(s.openNotFound(), core.LastUpdatedCmd(s.cache, s.subsCacheKey()))
 
 	case errMsg:
 		s.spinner.Stop()
@@ -196,7 +263,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewListTopics || s.viewState == ViewListSubs {
 			var updatedTable *components.StandardTable
@@ -206,6 +273,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list views)
 		if s.viewState == ViewListTopics || s.viewState == ViewListSubs {
 			var result components.FilterUpdateResult
@@ -228,10 +296,10 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Root Views (Topics or Subs)
 		if s.viewState == ViewListTopics || s.viewState == ViewListSubs {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "s": // Switch to Subs
+			case key.Matches(msg, keys.ShowSubscriptions): // Switch to Subs
 				if s.viewState == ViewListTopics {
 					s.viewState = ViewListSubs
 					s.subFilterSession.Apply(s.subs) // Render existing if available
@@ -239,7 +307,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return s, tea.Batch(s.fetchSubsCmd(true), s.spinner.Start(""))
 					}
 				}
-			case "t": // Switch to Topics
+			case key.Matches(msg, keys.ShowTopics): // Switch to Topics
 				if s.viewState == ViewListSubs {
 					s.viewState = ViewListTopics
 					s.topicFilterSession.Apply(s.topics)
@@ -247,7 +315,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return s, tea.Batch(s.fetchTopicsCmd(true), s.spinner.Start(""))
 					}
 				}
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				if s.viewState == ViewListTopics {
 					topics := s.getFilteredTopics(s.topics, s.filter.Value())
 					if idx := s.table.Cursor(); idx >= 0 && idx < len(topics) {
@@ -270,8 +338,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Detail Views
 		if s.viewState == ViewDetailTopic || s.viewState == ViewDetailSub {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				if s.viewState == ViewDetailTopic {
 					s.viewState = ViewListTopics
 					s.selectedTopic = nil
@@ -293,48 +361,30 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (s *Service) fetchTopicsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("pubsub_topics:%s", s.projectID)
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Topic); ok {
-					return topicsMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.topicsCacheKey(), CacheTTL, force, func() ([]Topic, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListTopics(s.projectID)
+			return s.client.ListTopics(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return topicsMsg(items)
 	}
 }
 
 func (s *Service) fetchSubsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("pubsub_subs:%s", s.projectID)
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Subscription); ok {
-					return subsMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.subsCacheKey(), CacheTTL, force, func() ([]Subscription, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListSubscriptions(s.projectID)
+			return s.client.ListSubscriptions(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return subsMsg(items)
 	}
 }
@@ -351,6 +401,7 @@ func (s *Service) updateTopicTable(items []Topic) {
 	}
 	// Clear rows first to prevent panic on column resize
 	s.table.SetRows([]table.Row{})
+	s.table.SetName("pubsub-topics")
 	s.table.SetColumns(columns)
 
 	rows := make([]table.Row, len(items))
@@ -370,6 +421,7 @@ func (s *Service) updateSubTable(items []Subscription) {
 	}
 	// Clear rows first to prevent panic on column resize
 	s.table.SetRows([]table.Row{})
+	s.table.SetName("pubsub-subscriptions")
 	s.table.SetColumns(columns)
 
 	rows := make([]table.Row, len(items))
@@ -392,22 +444,31 @@ func (s *Service) updateSubTable(items []Subscription) {
 	s.table.SetRows(rows)
 }
 
+// topicFilterFields are the fields the "/" filter understands
+var topicFilterFields = []components.FilterField[Topic]{
+	components.TextField("name", func(t Topic) string { return t.Name }),
+	components.TextField("kms", func(t Topic) string { return t.KmsKeyName }),
+	components.LabelsField("label", func(t Topic) map[string]string { return t.Labels }),
+}
+
 // getFilteredTopics returns filtered topics based on the query string
 func (s *Service) getFilteredTopics(topics []Topic, query string) []Topic {
-	if query == "" {
-		return topics
-	}
-	return components.FilterSlice(topics, query, func(topic Topic, q string) bool {
-		return components.ContainsMatch(topic.Name, topic.KmsKeyName)(q)
-	})
+	filtered, _ := components.FilterByQuery(topics, query, topicFilterFields)
+	return filtered
+}
+
+// subscriptionFilterFields are the fields the "/" filter understands
+var subscriptionFilterFields = []components.FilterField[Subscription]{
+	components.TextField("name", func(s Subscription) string { return s.Name }),
+	components.TextField("topic", func(s Subscription) string { return s.Topic }),
+	components.TextField("push", func(s Subscription) string { return s.PushEndpoint }),
+	components.TextField("state", func(s Subscription) string { return s.State }),
+	components.NumberField("ack", func(s Subscription) float64 { return float64(s.AckDeadline) }),
+	components.LabelsField("label", func(s Subscription) map[string]string { return s.Labels }),
 }
 
 // getFilteredSubs returns filtered subscriptions based on the query string
 func (s *Service) getFilteredSubs(subs []Subscription, query string) []Subscription {
-	if query == "" {
-		return subs
-	}
-	return components.FilterSlice(subs, query, func(sub Subscription, q string) bool {
-		return components.ContainsMatch(sub.Name, sub.Topic, sub.PushEndpoint)(q)
-	})
+	filtered, _ := components.FilterByQuery(subs, query, subscriptionFilterFields)
+	return filtered
 }
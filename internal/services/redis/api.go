@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/redis/v1"
 )
 
@@ -13,7 +14,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := redis.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "redis", redis.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := redis.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("redis client: %w", err)
 	}
@@ -54,6 +59,7 @@ func (c *Client) ListInstances(projectID string) ([]Instance, error) {
 				Port:              int(i.Port),
 				State:             i.State,
 				AuthorizedNetwork: network,
+				Labels:            i.Labels,
 			})
 		}
 		return nil
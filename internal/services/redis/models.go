@@ -1,5 +1,11 @@
 package redis
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Instance struct {
 	Name              string // Short ID
 	DisplayName       string
@@ -12,4 +18,21 @@ type Instance struct {
 	Port              int
 	State             string // READY, CREATING
 	AuthorizedNetwork string
+	Labels            map[string]string
+}
+
+// Meta implements services.Resource
+func (i Instance) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "redis.googleapis.com/Instance",
+		ID:         i.Name,
+		Name:       i.Name,
+		Project:    i.ProjectID,
+		Location:   i.Location,
+		State:      i.State,
+		Labels:     i.Labels,
+		Addresses:  []string{i.Host},
+		SelfLink:   fmt.Sprintf("projects/%s/locations/%s/instances/%s", i.ProjectID, i.Location, i.Name),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("memorystore/redis/locations/%s/instances/%s/details/overview", i.Location, i.Name), i.ProjectID),
+	}
 }
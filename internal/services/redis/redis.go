@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -17,8 +20,6 @@ const CacheTTL = 60 * time.Second
 // Models
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
-
 type ViewState int
 
 const (
@@ -48,6 +49,9 @@ type Service struct {
 	viewState        ViewState
 	selectedInstance *Instance
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	cache *core.Cache
 }
 
@@ -60,7 +64,7 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "State", Width: 10},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("redis-instances"))
 
 	svc := &Service{
 		table:     t,
@@ -69,7 +73,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState: ViewList,
 		cache:     cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredInstances, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, instanceFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -83,9 +87,9 @@ func (s *Service) ShortName() string {
 
 func (s *Service) HelpText() string {
 	if s.viewState == ViewList {
-		return "r:Refresh  /:Filter  Ent:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.As(keys.Select, "Detail"))
 	}
-	return "Esc/q:Back"
+	return keys.HelpLine(keys.Back)
 }
 
 // -----------------------------------------------------------------------------
@@ -109,23 +113,23 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchInstancesCmd(true),
+		core.PreloadPersisted(s.cache, s.instancesCacheKey(), func(v []Instance) tea.Msg { return instancesMsg(v) }, s.fetchInstancesCmd(true)),
 	)
 }
 
+// instancesCacheKey is the cache key for the instances list
+func (s *Service) instancesCacheKey() string {
+	return fmt.Sprintf("redis:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedInstance = nil
 	s.err = nil
@@ -137,6 +141,46 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered list for export
+func (s *Service) ExportTable() components.TableExport {
+	return components.NewTableExport("redis-instances", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.instances)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.instances); i >= 0 {
+		s.selectedInstance = &s.instances[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedInstance != nil {
+		loc.Resource = services.RefOf(s.selectedInstance.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -157,14 +201,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchInstancesCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchInstancesCmd(true)
 
 	case instancesMsg:
 		s.spinner.Stop()
 		s.instances = msg
+		s.selectedInstance = services.Reselect(s.selectedInstance, s.instances)
 		s.filterSession.Apply(s.instances)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.instancesCacheKey()), core.LastUpdatedCmd(s.cache, s.instancesCacheKey()))
 
 	case errMsg:
 		s.spinner.Stop()
@@ -174,7 +220,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.table.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -184,6 +230,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		// Handle filter mode (only in list view)
 		if s.viewState == ViewList {
 			result := s.filterSession.HandleKey(msg)
@@ -200,10 +247,10 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewList {
-			switch msg.String() {
-			case "r":
+			switch {
+			case key.Matches(msg, keys.Refresh):
 				return s, s.Refresh()
-			case "enter":
+			case key.Matches(msg, keys.Select):
 				instances := s.getFilteredInstances(s.instances, s.filter.Value())
 				if idx := s.table.Cursor(); idx >= 0 && idx < len(instances) {
 					s.selectedInstance = &instances[idx]
@@ -217,8 +264,8 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if s.viewState == ViewDetail {
-			switch msg.String() {
-			case "esc", "q":
+			switch {
+			case key.Matches(msg, keys.Back):
 				s.viewState = ViewList
 				s.selectedInstance = nil
 				return s, nil
@@ -234,24 +281,15 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (s *Service) fetchInstancesCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		key := fmt.Sprintf("redis:%s", s.projectID)
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(key); found {
-				if items, ok := val.([]Instance); ok {
-					return instancesMsg(items)
-				}
+		items, err := core.GetOrFetch(s.cache, s.instancesCacheKey(), CacheTTL, force, func() ([]Instance, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not init")
 			}
-		}
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not init"))
-		}
-		items, err := s.client.ListInstances(s.projectID)
+			return s.client.ListInstances(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
-		if s.cache != nil {
-			s.cache.Set(key, items, CacheTTL)
-		}
 		return instancesMsg(items)
 	}
 }
@@ -270,12 +308,20 @@ func (s *Service) updateTable(items []Instance) {
 	s.table.SetRows(rows)
 }
 
+// instanceFilterFields are the fields the "/" filter understands
+var instanceFilterFields = []components.FilterField[Instance]{
+	components.TextField("name", func(i Instance) string { return i.Name }),
+	components.TextField("location", func(i Instance) string { return i.Location }),
+	components.TextField("tier", func(i Instance) string { return i.Tier }),
+	components.TextField("state", func(i Instance) string { return i.State }),
+	components.TextField("version", func(i Instance) string { return i.RedisVersion }),
+	components.TextField("host", func(i Instance) string { return i.Host }),
+	components.NumberField("memory", func(i Instance) float64 { return float64(i.MemorySizeGb) }),
+	components.LabelsField("label", func(i Instance) map[string]string { return i.Labels }),
+}
+
 // getFilteredInstances returns filtered instances based on the query string
 func (s *Service) getFilteredInstances(instances []Instance, query string) []Instance {
-	if query == "" {
-		return instances
-	}
-	return components.FilterSlice(instances, query, func(inst Instance, q string) bool {
-		return components.ContainsMatch(inst.Name, inst.Location, inst.Tier, inst.State)(q)
-	})
+	filtered, _ := components.FilterByQuery(instances, query, instanceFilterFields)
+	return filtered
 }
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ResourceMeta is the service-independent description of a resource,
+// shared by every service model
+type ResourceMeta struct {
+	Kind       string // Cloud Asset Inventory type, e.g. "compute.googleapis.com/Instance"
+	ID         string // Stable identifier; the name when the API has no separate ID
+	Name       string
+	Project    string
+	Location   string // Zone, region, multi-region or "global"
+	State      string
+	Labels     map[string]string
+	Addresses  []string // IPs and hostnames the resource answers on; may hold empty strings
+	SelfLink   string   // API URL or full resource name
+	ConsoleURL string
+}
+
+// Resource is implemented by the models of every service so cross-cutting
+// features (search, export, cross-linking) can treat them uniformly
+type Resource interface {
+	// Meta describes the resource
+	Meta() ResourceMeta
+}
+
+// ResourceProvider is an optional extension of Service for services that
+// can hand out the resources they have loaded
+type ResourceProvider interface {
+	// Resources returns the currently loaded top-level items, unfiltered
+	Resources() []Resource
+}
+
+// ResourceOpener is an optional extension of Service for services that can
+// jump straight to the detail view of one of their resources
+type ResourceOpener interface {
+	// OpenResource shows the resource now, or once the list has loaded.
+	// Call it after Reset and before Refresh.
+	OpenResource(ref ResourceRef) tea.Cmd
+}
+
+// CommandProvider is an optional extension of Service for services with
+// actions on their resources, which the command palette then offers for each
+// loaded resource, e.g. "SSH: web-01"
+type CommandProvider interface {
+	// ResourceCommands returns the actions on the currently loaded
+	// resources that apply to them in their current state
+	ResourceCommands() []ResourceCommand
+
+	// RunCommand runs an action on a loaded resource, showing its detail
+	// view. Call it after Reset.
+	RunCommand(action string, ref ResourceRef) tea.Cmd
+}
+
+// ResourceCommand is an action on one resource
+type ResourceCommand struct {
+	Title       string // Verb shown before the resource name, e.g. "SSH"
+	Action      string // Passed back to RunCommand, e.g. "ssh"
+	Description string
+	Resource    ResourceMeta
+}
+
+// NewResourceCommands returns the same action for several resources
+func NewResourceCommands[T Resource](title, action, description string, items []T) []ResourceCommand {
+	out := make([]ResourceCommand, len(items))
+	for i, item := range items {
+		out[i] = ResourceCommand{Title: title, Action: action, Description: description, Resource: item.Meta()}
+	}
+	return out
+}
+
+// Navigable is an optional extension of Service for services that can
+// report what they show, so that navigating back through the history
+// returns there
+type Navigable interface {
+	// Location returns the resource open in a detail view, if any, and the
+	// list filter
+	Location() Location
+
+	// Restore returns to a recorded location. Call it after Reset and before
+	// Refresh.
+	Restore(loc Location) tea.Cmd
+}
+
+// Location is what a service shows, as recorded in the navigation history
+type Location struct {
+	Resource ResourceRef // Empty for the list
+	Filter   string      // Filter query of the list; the log filter for Cloud Logging
+	Heading  string      // Heading of a log query
+}
+
+// ResourceRef points at a resource found outside its service, e.g. by
+// search or a deep link
+type ResourceRef struct {
+	Kind       string
+	Collection string // Optional, e.g. "instances"; matched against the last part of Kind
+	ID         string // Optional; matched against ResourceMeta.ID
+	Name       string
+	Location   string // Optional; picks between resources with the same name
+}
+
+// Matches reports whether the resource has the referenced kind and name or ID
+func (r ResourceRef) Matches(m ResourceMeta) bool {
+	if r.Kind != "" && m.Kind != r.Kind {
+		return false
+	}
+	if r.Collection != "" && !inCollection(m.Kind, r.Collection) {
+		return false
+	}
+	return m.Name == r.Name || (r.ID != "" && m.ID == r.ID)
+}
+
+// RefOf returns a reference to a resource
+func RefOf(m ResourceMeta) ResourceRef {
+	return ResourceRef{Kind: m.Kind, ID: m.ID, Name: m.Name, Location: m.Location}
+}
+
+// inCollection reports whether a kind such as "iam.googleapis.com/ServiceAccount"
+// belongs to a collection written the way URLs do, e.g. "service-accounts"
+func inCollection(kind, collection string) bool {
+	normalize := func(s string) string {
+		s = strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(s))
+		return strings.TrimSuffix(s, "s")
+	}
+	return normalize(LastSegment(kind)) == normalize(collection)
+}
+
+// PendingOpen remembers a resource a service was asked to open before its
+// list had loaded
+type PendingOpen struct {
+	ref    ResourceRef
+	active bool
+}
+
+// Set records the resource to open. An empty ref clears the request.
+func (p *PendingOpen) Set(ref ResourceRef) {
+	p.ref = ref
+	p.active = ref != ResourceRef{}
+}
+
+// Clear drops the request, e.g. once the user starts navigating
+func (p *PendingOpen) Clear() {
+	p.active = false
+}
+
+// Missed returns the request, if any, and drops it. Services call it once
+// their list has loaded without the resource, so that a mistyped link is
+// reported rather than left armed.
+func (p *PendingOpen) Missed() (ResourceRef, bool) {
+	if !p.active {
+		return ResourceRef{}, false
+	}
+	p.active = false
+	return p.ref, true
+}
+
+// FindPending returns the index of the pending resource in items, preferring
+// one in the referenced location, and clears the request. It returns -1 when
+// nothing is pending or the resource is not in items (yet).
+func FindPending[T Resource](p *PendingOpen, items []T) int {
+	if !p.active {
+		return -1
+	}
+	found := Find(p.ref, items)
+	if found >= 0 {
+		p.active = false
+	}
+	return found
+}
+
+// Find returns the index of the referenced resource in items, preferring
+// one in the referenced location, or -1 when it is not there
+func Find[T Resource](ref ResourceRef, items []T) int {
+	found := -1
+	for i, item := range items {
+		m := item.Meta()
+		if !ref.Matches(m) {
+			continue
+		}
+		if found < 0 {
+			found = i
+		}
+		if ref.Location == "" || strings.EqualFold(m.Location, ref.Location) {
+			found = i
+			break
+		}
+	}
+	return found
+}
+
+// Reselect returns the item of a reloaded list that is the same resource
+// as the selected one, in the same location, so that a detail view shows
+// the refreshed state. It keeps the old selection when the resource is gone.
+func Reselect[T Resource](selected *T, items []T) *T {
+	if selected == nil {
+		return nil
+	}
+	ref := RefOf((*selected).Meta())
+	for i := range items {
+		m := items[i].Meta()
+		if ref.Matches(m) && strings.EqualFold(m.Location, ref.Location) {
+			return &items[i]
+		}
+	}
+	return selected
+}
+
+// AsResources converts a slice of models to a slice of Resources
+func AsResources[T Resource](items []T) []Resource {
+	out := make([]Resource, len(items))
+	for i, item := range items {
+		out[i] = item
+	}
+	return out
+}
+
+// ConsoleURL builds a Cloud Console link for a page of a project
+func ConsoleURL(path, projectID string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return "https://console.cloud.google.com/" + path + sep + "project=" + url.QueryEscape(projectID)
+}
+
+// LastSegment returns the part of a resource URL or name after the last "/"
+func LastSegment(s string) string {
+	return s[strings.LastIndex(s, "/")+1:]
+}
@@ -0,0 +1,123 @@
+package services
+
+import "testing"
+
+type fakeResource struct{ name string }
+
+func (f fakeResource) Meta() ResourceMeta { return ResourceMeta{Name: f.name} }
+
+func TestConsoleURL(t *testing.T) {
+	tests := []struct {
+		path, project, want string
+	}{
+		{"sql/instances/db/overview", "acme", "https://console.cloud.google.com/sql/instances/db/overview?project=acme"},
+		{"dataproc/clusters/c/monitoring?region=us-east1", "acme", "https://console.cloud.google.com/dataproc/clusters/c/monitoring?region=us-east1&project=acme"},
+	}
+	for _, tt := range tests {
+		if got := ConsoleURL(tt.path, tt.project); got != tt.want {
+			t.Errorf("ConsoleURL(%q, %q) = %q, want %q", tt.path, tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestAsResources(t *testing.T) {
+	got := AsResources([]fakeResource{{"a"}, {"b"}})
+	if len(got) != 2 || got[0].Meta().Name != "a" || got[1].Meta().Name != "b" {
+		t.Errorf("AsResources returned %v", got)
+	}
+	if len(AsResources[fakeResource](nil)) != 0 {
+		t.Error("AsResources(nil) should be empty")
+	}
+}
+
+func TestLastSegment(t *testing.T) {
+	if got := LastSegment("projects/p/locations/l/functions/fn"); got != "fn" {
+		t.Errorf("LastSegment = %q", got)
+	}
+	if got := LastSegment("plain"); got != "plain" {
+		t.Errorf("LastSegment = %q", got)
+	}
+}
+
+type fakeMeta ResourceMeta
+
+func (f fakeMeta) Meta() ResourceMeta { return ResourceMeta(f) }
+
+func TestFindPending(t *testing.T) {
+	items := []fakeMeta{
+		{Kind: "k", Name: "web", Location: "us-east1-b"},
+		{Kind: "k", Name: "web", Location: "europe-west1-b"},
+		{Kind: "k", Name: "sa@p.iam", ID: "1234"},
+	}
+
+	var p PendingOpen
+	if i := FindPending(&p, items); i != -1 {
+		t.Errorf("nothing pending: got %d", i)
+	}
+
+	p.Set(ResourceRef{Kind: "k", Name: "web", Location: "EUROPE-WEST1-B"})
+	if i := FindPending(&p, items); i != 1 {
+		t.Errorf("location match: got %d, want 1", i)
+	}
+	if i := FindPending(&p, items); i != -1 {
+		t.Errorf("request should be cleared once found, got %d", i)
+	}
+
+	p.Set(ResourceRef{Kind: "k", Name: "web", Location: "asia-east1-a"})
+	if i := FindPending(&p, items); i != 0 {
+		t.Errorf("no location match: got %d, want first", i)
+	}
+
+	p.Set(ResourceRef{Kind: "k", ID: "1234", Name: "My SA"})
+	if i := FindPending(&p, items); i != 2 {
+		t.Errorf("ID match: got %d, want 2", i)
+	}
+
+	p.Set(ResourceRef{Kind: "other", Name: "web"})
+	if i := FindPending(&p, items); i != -1 {
+		t.Errorf("kind mismatch: got %d", i)
+	}
+	p.Clear()
+	items = append(items, fakeMeta{Kind: "other", Name: "web"})
+	if i := FindPending(&p, items); i != -1 {
+		t.Errorf("cleared request: got %d", i)
+	}
+
+	p.Set(ResourceRef{Kind: "k", Name: "typo"})
+	if ref, ok := p.Missed(); !ok || ref.Name != "typo" {
+		t.Errorf("Missed() = %+v, %v; want the request", ref, ok)
+	}
+	if _, ok := p.Missed(); ok {
+		t.Error("Missed should drop the request")
+	}
+}
+
+func TestReselect(t *testing.T) {
+	old := []fakeMeta{{Kind: "k", Name: "web", Location: "europe-west1-b", State: "STAGING"}}
+	reloaded := []fakeMeta{
+		{Kind: "k", Name: "web", Location: "us-east1-b", State: "STOPPED"},
+		{Kind: "k", Name: "web", Location: "europe-west1-b", State: "RUNNING"},
+	}
+
+	if got := Reselect(&old[0], reloaded); got != &reloaded[1] {
+		t.Errorf("Reselect() = %+v, want the reloaded web in europe-west1-b", got)
+	}
+	if got := Reselect(&old[0], reloaded[:1]); got != &old[0] {
+		t.Errorf("Reselect() of a vanished resource = %+v, want the old one", got)
+	}
+	if got := Reselect[fakeMeta](nil, reloaded); got != nil {
+		t.Errorf("Reselect(nil) = %+v", got)
+	}
+}
+
+func TestResourceRefCollection(t *testing.T) {
+	sa := ResourceMeta{Kind: "iam.googleapis.com/ServiceAccount", Name: "deploy"}
+	for _, c := range []string{"service-accounts", "serviceaccounts", "service_account", "ServiceAccount"} {
+		if !(ResourceRef{Collection: c, Name: "deploy"}).Matches(sa) {
+			t.Errorf("collection %q should match %s", c, sa.Kind)
+		}
+	}
+	if (ResourceRef{Collection: "topics", Name: "deploy"}).Matches(sa) {
+		t.Error("collection topics matched a service account")
+	}
+}
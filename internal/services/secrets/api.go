@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/yogirk/tgcp/internal/core"
 	secretmanager "google.golang.org/api/secretmanager/v1"
 )
 
@@ -16,7 +17,11 @@ type Client struct {
 
 // NewClient creates a new Secret Manager client
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := secretmanager.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "secrets", secretmanager.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := secretmanager.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create secretmanager service: %w", err)
 	}
@@ -36,6 +41,7 @@ func (c *Client) ListSecrets(projectID string) ([]Secret, error) {
 				Name:        extractSecretName(s.Name),
 				Labels:      s.Labels,
 				Replication: formatReplication(s.Replication),
+				ProjectID:   projectID,
 			}
 
 			// Parse create time
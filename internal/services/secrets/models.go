@@ -1,6 +1,10 @@
 package secrets
 
-import "time"
+import (
+	"time"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
 
 // Secret represents a Secret Manager secret
 type Secret struct {
@@ -10,6 +14,21 @@ type Secret struct {
 	Labels      map[string]string
 	Replication string            // "automatic" or region list
 	VersionCount int              // Number of versions
+	ProjectID    string
+}
+
+// Meta implements services.Resource
+func (s Secret) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "secretmanager.googleapis.com/Secret",
+		ID:         s.FullName,
+		Name:       s.Name,
+		Project:    s.ProjectID,
+		Location:   "global",
+		Labels:     s.Labels,
+		SelfLink:   s.FullName,
+		ConsoleURL: services.ConsoleURL("security/secret-manager/secret/"+s.Name+"/versions", s.ProjectID),
+	}
 }
 
 // SecretVersion represents a version of a secret
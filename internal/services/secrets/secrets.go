@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/services"
+	"github.com/yogirk/tgcp/internal/styles"
 	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
@@ -18,7 +22,6 @@ const CacheTTL = 60 * time.Second // Secrets rarely change
 // Message Types
 // -----------------------------------------------------------------------------
 
-type tickMsg time.Time
 type secretsMsg []Secret
 type versionsMsg []SecretVersion
 type errMsg error
@@ -61,6 +64,9 @@ type Service struct {
 	viewState      ViewState
 	selectedSecret *Secret
 
+	// Resource to show once the list has loaded (set by search)
+	pendingOpen services.PendingOpen
+
 	// Cache
 	cache *core.Cache
 }
@@ -74,14 +80,14 @@ func NewService(cache *core.Cache) *Service {
 		{Title: "Created", Width: 20},
 	}
 
-	t := components.NewStandardTable(columns)
+	t := components.NewStandardTable(columns, components.WithName("secrets"))
 
 	versionColumns := []table.Column{
 		{Title: "Version", Width: 10},
 		{Title: "State", Width: 12},
 		{Title: "Created", Width: 25},
 	}
-	vt := components.NewStandardTable(versionColumns)
+	vt := components.NewStandardTable(versionColumns, components.WithName("secret-versions"))
 
 	svc := &Service{
 		table:        t,
@@ -91,7 +97,7 @@ func NewService(cache *core.Cache) *Service {
 		viewState:    ViewList,
 		cache:        cache,
 	}
-	svc.filterSession = components.NewFilterSession(&svc.filter, svc.getFilteredSecrets, svc.updateTable)
+	svc.filterSession = components.NewFieldFilterSession(&svc.filter, secretFilterFields, svc.updateTable)
 	return svc
 }
 
@@ -106,11 +112,11 @@ func (s *Service) ShortName() string {
 func (s *Service) HelpText() string {
 	switch s.viewState {
 	case ViewList:
-		return "r:Refresh  /:Filter  Enter:Detail"
+		return keys.HelpLine(keys.Refresh, keys.Filter, keys.As(keys.Select, "Detail"))
 	case ViewDetail:
-		return "v:Versions  Esc/q:Back"
+		return keys.HelpLine(keys.Versions, keys.Back)
 	case ViewVersions:
-		return "Esc/q:Back to Detail"
+		return keys.HelpLine(keys.As(keys.Back, "Back to Detail"))
 	default:
 		return ""
 	}
@@ -136,23 +142,23 @@ func (s *Service) Reinit(ctx context.Context, projectID string) error {
 }
 
 func (s *Service) Init() tea.Cmd {
-	return s.tick()
-}
-
-func (s *Service) tick() tea.Cmd {
-	return tea.Tick(CacheTTL, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	return nil
 }
 
 func (s *Service) Refresh() tea.Cmd {
 	return tea.Batch(
 		s.spinner.Start(""),
-		s.fetchSecretsCmd(true),
+		core.PreloadPersisted(s.cache, s.secretsCacheKey(), func(v []Secret) tea.Msg { return secretsMsg(v) }, s.fetchSecretsCmd(true)),
 	)
 }
 
+// secretsCacheKey is the cache key for the secrets list
+func (s *Service) secretsCacheKey() string {
+	return fmt.Sprintf("secrets:%s", s.projectID)
+}
+
 func (s *Service) Reset() {
+	s.pendingOpen.Clear()
 	s.viewState = ViewList
 	s.selectedSecret = nil
 	s.versions = nil
@@ -166,6 +172,50 @@ func (s *Service) IsRootView() bool {
 	return s.viewState == ViewList
 }
 
+// ExportTable snapshots the filtered secrets, or the versions of the
+// selected secret, for export
+func (s *Service) ExportTable() components.TableExport {
+	if s.viewState == ViewVersions {
+		return components.NewTableExport("secret-versions", s.versionTable, s.versions)
+	}
+	return components.NewTableExport("secrets", s.table, s.filterSession.Filtered())
+}
+
+// Resources implements services.ResourceProvider
+func (s *Service) Resources() []services.Resource {
+	return services.AsResources(s.secrets)
+}
+
+// OpenResource implements services.ResourceOpener
+func (s *Service) OpenResource(ref services.ResourceRef) tea.Cmd {
+	s.pendingOpen.Set(ref)
+	s.openPending()
+	return nil
+}
+
+// openPending opens the resource requested by search once it has loaded
+func (s *Service) openPending() {
+	if i := services.FindPending(&s.pendingOpen, s.secrets); i >= 0 {
+		s.selectedSecret = &s.secrets[i]
+		s.viewState = ViewDetail
+	}
+}
+
+// Location implements services.Navigable
+func (s *Service) Location() services.Location {
+	loc := services.Location{Filter: s.filter.Value()}
+	if !s.IsRootView() && s.selectedSecret != nil {
+		loc.Resource = services.RefOf(s.selectedSecret.Meta())
+	}
+	return loc
+}
+
+// Restore implements services.Navigable
+func (s *Service) Restore(loc services.Location) tea.Cmd {
+	s.filter.SetValue(loc.Filter)
+	return s.OpenResource(loc.Resource)
+}
+
 func (s *Service) Focus() {
 	s.table.Focus()
 }
@@ -186,14 +236,16 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.spinner, cmd = s.spinner.Update(msg)
 		return s, cmd
 
-	case tickMsg:
-		return s, tea.Batch(s.fetchSecretsCmd(false), s.tick())
+	case core.RefreshTickMsg:
+		return s, s.fetchSecretsCmd(true)
 
 	case secretsMsg:
 		s.spinner.Stop()
 		s.secrets = msg
+		s.selectedSecret = services.Reselect(s.selectedSecret, s.secrets)
 		s.filterSession.Apply(s.secrets)
-		return s, func() tea.Msg { return core.LastUpdatedMsg(time.Now()) }
+		s.openPending()
+		return s, tea.Batch(core.OpenNotFound(&s.pendingOpen, s.cache, s.secretsCacheKey()), core.LastUpdatedCmd(s.cache, s.secretsCacheKey()))
 
 	case versionsMsg:
 		s.spinner.Stop()
@@ -212,7 +264,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.table.HandleWindowSizeDefault(msg)
 		s.versionTable.HandleWindowSizeDefault(msg)
 
-	case tea.MouseMsg:
+	case tea.MouseMsg, components.HeaderClickMsg:
 		// Forward mouse events to active table for click selection
 		if s.viewState == ViewList {
 			var updatedTable *components.StandardTable
@@ -227,6 +279,7 @@ func (s *Service) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		s.pendingOpen.Clear()
 		return s.handleKeyMsg(msg)
 	}
 
@@ -249,10 +302,10 @@ func (s *Service) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		switch msg.String() {
-		case "r":
+		switch {
+		case key.Matches(msg, keys.Refresh):
 			return s, s.Refresh()
-		case "enter":
+		case key.Matches(msg, keys.Select):
 			secrets := s.getCurrentSecrets()
 			if idx := s.table.Cursor(); idx >= 0 && idx < len(secrets) {
 				s.selectedSecret = &secrets[idx]
@@ -267,13 +320,13 @@ func (s *Service) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return s, cmd
 
 	case ViewDetail:
-		switch msg.String() {
-		case "esc", "q":
+		switch {
+		case key.Matches(msg, keys.Back):
 			s.viewState = ViewList
 			s.selectedSecret = nil
 			s.versions = nil
 			return s, nil
-		case "v":
+		case key.Matches(msg, keys.Versions):
 			// Fetch versions for selected secret
 			if s.selectedSecret != nil {
 				s.viewState = ViewVersions
@@ -285,8 +338,8 @@ func (s *Service) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case ViewVersions:
-		switch msg.String() {
-		case "esc", "q":
+		switch {
+		case key.Matches(msg, keys.Back):
 			s.viewState = ViewDetail
 			return s, nil
 		}
@@ -373,7 +426,7 @@ func (s *Service) renderDetailView() string {
 
 	// Security note
 	note := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(styles.ColorTextMuted).
 		Italic(true).
 		Render("Note: Secret values are not displayed for security reasons.")
 
@@ -411,29 +464,16 @@ func (s *Service) renderVersionsView() string {
 
 func (s *Service) fetchSecretsCmd(force bool) tea.Cmd {
 	return func() tea.Msg {
-		cacheKey := fmt.Sprintf("secrets:%s", s.projectID)
-
-		if !force && s.cache != nil {
-			if val, found := s.cache.Get(cacheKey); found {
-				if secrets, ok := val.([]Secret); ok {
-					return secretsMsg(secrets)
-				}
+		secrets, err := core.GetOrFetch(s.cache, s.secretsCacheKey(), CacheTTL, force, func() ([]Secret, error) {
+			if s.client == nil {
+				return nil, fmt.Errorf("client not initialized")
 			}
-		}
-
-		if s.client == nil {
-			return errMsg(fmt.Errorf("client not initialized"))
-		}
-
-		secrets, err := s.client.ListSecrets(s.projectID)
+			return s.client.ListSecrets(s.projectID)
+		})
 		if err != nil {
 			return errMsg(err)
 		}
 
-		if s.cache != nil {
-			s.cache.Set(cacheKey, secrets, CacheTTL)
-		}
-
 		return secretsMsg(secrets)
 	}
 }
@@ -499,16 +539,16 @@ func (s *Service) getCurrentSecrets() []Secret {
 	return s.getFilteredSecrets(s.secrets, s.filter.Value())
 }
 
+// secretFilterFields are the fields the "/" filter understands
+var secretFilterFields = []components.FilterField[Secret]{
+	components.TextField("name", func(s Secret) string { return s.Name }),
+	components.TextField("replication", func(s Secret) string { return s.Replication }),
+	components.NumberField("versions", func(s Secret) float64 { return float64(s.VersionCount) }),
+	components.LabelsField("label", func(s Secret) map[string]string { return s.Labels }),
+}
+
+// getFilteredSecrets returns the secrets matching the filter query
 func (s *Service) getFilteredSecrets(secrets []Secret, query string) []Secret {
-	if query == "" {
-		return secrets
-	}
-	return components.FilterSlice(secrets, query, func(sec Secret, q string) bool {
-		// Build label string for search
-		var labelStr string
-		for k, v := range sec.Labels {
-			labelStr += k + "=" + v + " "
-		}
-		return components.ContainsMatch(sec.Name, sec.Replication, labelStr)(q)
-	})
+	filtered, _ := components.FilterByQuery(secrets, query, secretFilterFields)
+	return filtered
 }
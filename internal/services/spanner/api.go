@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/yogirk/tgcp/internal/core"
 	"google.golang.org/api/spanner/v1"
 )
 
@@ -13,7 +14,11 @@ type Client struct {
 }
 
 func NewClient(ctx context.Context) (*Client, error) {
-	svc, err := spanner.NewService(ctx)
+	opts, err := core.ClientOptions(ctx, "spanner", spanner.SpannerAdminScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := spanner.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("spanner client: %w", err)
 	}
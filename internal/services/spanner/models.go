@@ -1,5 +1,11 @@
 package spanner
 
+import (
+	"fmt"
+
+	"github.com/yogirk/tgcp/internal/services"
+)
+
 type Instance struct {
 	Name            string // Short ID
 	DisplayName     string
@@ -10,3 +16,18 @@ type Instance struct {
 	ProcessingUnits int
 	Labels          map[string]string
 }
+
+// Meta implements services.Resource
+func (i Instance) Meta() services.ResourceMeta {
+	return services.ResourceMeta{
+		Kind:       "spanner.googleapis.com/Instance",
+		ID:         i.Name,
+		Name:       i.Name,
+		Project:    i.ProjectID,
+		Location:   i.Config,
+		State:      i.State,
+		Labels:     i.Labels,
+		SelfLink:   fmt.Sprintf("projects/%s/instances/%s", i.ProjectID, i.Name),
+		ConsoleURL: services.ConsoleURL(fmt.Sprintf("spanner/instances/%s/details/databases", i.Name), i.ProjectID),
+	}
+}
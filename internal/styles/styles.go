@@ -2,120 +2,186 @@ package styles
 
 import "github.com/charmbracelet/lipgloss"
 
+// Colors and styles are set from the active theme (see SetTheme). Views
+// must read them when rendering rather than keep copies, so that they follow
+// theme and accent changes.
 var (
 	// Semantic colors
-	ColorBrandPrimary = lipgloss.Color("39")  // GCP Blue (brighter)
-	ColorBrandAccent  = lipgloss.Color("75")  // Light Blue (for highlights)
-	ColorTextPrimary  = lipgloss.Color("252") // Near white
-	ColorTextMuted    = lipgloss.Color("243") // Muted grey (more contrast from primary)
-	ColorBorderSubtle = lipgloss.Color("240") // Subtle border (more visible)
-	ColorSuccess      = lipgloss.Color("42")  // Green
-	ColorWarning      = lipgloss.Color("214") // Orange
-	ColorError        = lipgloss.Color("196") // Red
-	ColorInfo         = lipgloss.Color("45")  // Cyan
+	ColorBrandPrimary lipgloss.Color // GCP Blue
+	ColorBrandAccent  lipgloss.Color // Highlights, per profile
+	ColorTextPrimary  lipgloss.Color
+	ColorTextMuted    lipgloss.Color
+	ColorBorderSubtle lipgloss.Color
+	ColorSuccess      lipgloss.Color
+	ColorWarning      lipgloss.Color
+	ColorError        lipgloss.Color
+	ColorInfo         lipgloss.Color
+	ColorBadgeText    lipgloss.Color // Text on colored badges
+
+	// Surface colors
+	ColorHeaderBg    lipgloss.Color // Table headers
+	ColorSelectedFg  lipgloss.Color // Selected row of the focused table
+	ColorSelectedBg  lipgloss.Color
+	ColorBlurredFg   lipgloss.Color // Selected row of an unfocused table
+	ColorBlurredBg   lipgloss.Color
+	ColorStatusBarFg lipgloss.Color
+	ColorStatusBarBg lipgloss.Color
+	ColorPanelBg     lipgloss.Color // Toasts and the operations panel
 
 	// Base Styles
-	BaseStyle = lipgloss.NewStyle().
-			Foreground(ColorTextPrimary)
+	BaseStyle lipgloss.Style
 
 	// Component Styles - Box Hierarchy
 	// BoxStyle is the base (kept for backwards compatibility)
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorBorderSubtle).
-			Padding(0, 1)
+	BoxStyle lipgloss.Style
 
 	// PrimaryBoxStyle - for main content cards (detail views, active panels)
 	// Rounded border with accent color for visual prominence
-	PrimaryBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorBrandAccent).
-			Padding(1, 2)
+	PrimaryBoxStyle lipgloss.Style
 
 	// SecondaryBoxStyle - for supporting content (metadata, sections)
 	// Normal border with subtle color, less prominent
+	SecondaryBoxStyle lipgloss.Style
+
+	FocusedBoxStyle lipgloss.Style
+
+	HeaderStyle lipgloss.Style
+
+	TitleStyle lipgloss.Style
+
+	// Sidebar Styles
+	SidebarStyle lipgloss.Style
+
+	SelectedItemStyle lipgloss.Style
+
+	UnselectedItemStyle lipgloss.Style
+
+	// Status Bar Styles
+	StatusBarStyle lipgloss.Style
+
+	// Generic Styles
+	LabelStyle lipgloss.Style
+
+	ValueStyle lipgloss.Style
+
+	ErrorStyle lipgloss.Style
+
+	// New Styles
+	SubtleStyle lipgloss.Style
+
+	SubtextStyle lipgloss.Style // Alias for SubtextStyle used in views
+
+	SuccessStyle lipgloss.Style
+
+	WarningStyle lipgloss.Style
+
+	HelpStyle lipgloss.Style
+
+	// Tab Styles
+	ActiveTabStyle lipgloss.Style
+
+	InactiveTabStyle lipgloss.Style
+)
+
+// build derives the shared styles from the current colors
+func build() {
+	BaseStyle = lipgloss.NewStyle().
+		Foreground(ColorTextPrimary)
+
+	BoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorBorderSubtle).
+		Padding(0, 1)
+
+	PrimaryBoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorBrandAccent).
+		Padding(1, 2)
+
 	SecondaryBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(ColorBorderSubtle).
-			Padding(0, 1)
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(ColorBorderSubtle).
+		Padding(0, 1)
 
 	FocusedBoxStyle = BoxStyle.Copy().
-			BorderForeground(ColorBrandPrimary)
+		BorderForeground(ColorBrandPrimary)
 
 	HeaderStyle = lipgloss.NewStyle().
-			Foreground(ColorTextPrimary).
-			Background(lipgloss.Color("237")).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(ColorTextPrimary).
+		Background(ColorHeaderBg).
+		Bold(true).
+		Padding(0, 1)
 
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(ColorBrandPrimary).
-			Bold(true)
+		Foreground(ColorBrandPrimary).
+		Bold(true)
 
-	// Sidebar Styles
 	SidebarStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder(), false, true, false, false). // Right border only
-			BorderForeground(ColorBorderSubtle).
-			Padding(0, 1).
-			Width(25) // Fixed width for sidebar
+		Border(lipgloss.RoundedBorder(), false, true, false, false). // Right border only
+		BorderForeground(ColorBorderSubtle).
+		Padding(0, 1).
+		Width(25) // Fixed width for sidebar
 
 	SelectedItemStyle = lipgloss.NewStyle().
-				Foreground(ColorBrandAccent).
-				Bold(true).
-				Border(lipgloss.NormalBorder(), false, false, false, true). // Left border
-				BorderForeground(ColorBrandAccent).
-				Padding(0, 0, 0, 1)
+		Foreground(ColorBrandAccent).
+		Bold(true).
+		Border(lipgloss.NormalBorder(), false, false, false, true). // Left border
+		BorderForeground(ColorBrandAccent).
+		Padding(0, 0, 0, 1)
 
 	UnselectedItemStyle = lipgloss.NewStyle().
-				Foreground(ColorTextPrimary).
-				Padding(0, 0, 0, 2)
+		Foreground(ColorTextPrimary).
+		Padding(0, 0, 0, 2)
 
-	// Status Bar Styles
 	StatusBarStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			Background(lipgloss.Color("235")).
-			Padding(0, 1)
+		Foreground(ColorStatusBarFg).
+		Background(ColorStatusBarBg).
+		Padding(0, 1)
 
-	// Generic Styles
 	LabelStyle = lipgloss.NewStyle().
-			Foreground(ColorTextMuted).
-			Bold(true).
-			Width(10)
+		Foreground(ColorTextMuted).
+		Bold(true).
+		Width(10)
 
 	ValueStyle = lipgloss.NewStyle().
-			Foreground(ColorTextPrimary)
+		Foreground(ColorTextPrimary)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorError).
-			Bold(true)
+		Foreground(ColorError).
+		Bold(true)
 
-	// New Styles
 	SubtleStyle = lipgloss.NewStyle().
-			Foreground(ColorTextMuted)
+		Foreground(ColorTextMuted)
 
-	SubtextStyle = SubtleStyle // Alias for SubtextStyle used in views
+	SubtextStyle = SubtleStyle
 
 	SuccessStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess)
+		Foreground(ColorSuccess)
 
 	WarningStyle = lipgloss.NewStyle().
-			Foreground(ColorWarning)
+		Foreground(ColorWarning)
 
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(ColorTextMuted).
-			Italic(true)
+		Foreground(ColorTextMuted).
+		Italic(true)
 
-	// Tab Styles
 	ActiveTabStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder(), true, true, false, true).
-			BorderForeground(ColorBrandAccent).
-			Padding(0, 1).
-			Bold(true).
-			Foreground(ColorBrandAccent)
+		Border(lipgloss.RoundedBorder(), true, true, false, true).
+		BorderForeground(ColorBrandAccent).
+		Padding(0, 1).
+		Bold(true).
+		Foreground(ColorBrandAccent)
 
 	InactiveTabStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder(), true, true, false, true).
-				BorderForeground(ColorBorderSubtle).
-				Padding(0, 1).
-				Foreground(ColorTextMuted)
-)
+		Border(lipgloss.RoundedBorder(), true, true, false, true).
+		BorderForeground(ColorBorderSubtle).
+		Padding(0, 1).
+		Foreground(ColorTextMuted)
+}
+
+// SetAccent switches the accent color, e.g. per profile, and rebuilds the
+// shared styles derived from it. An empty color restores the theme's accent.
+func SetAccent(color string) {
+	accent = color
+	apply()
+}
@@ -0,0 +1,191 @@
+package styles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+// DefaultTheme is used when ui.theme is not set
+const DefaultTheme = "dark"
+
+// Presets are the built-in themes. "dark" is tuned for dark terminals,
+// "light" for light ones such as Solarized Light.
+var Presets = map[string]config.ThemeConfig{
+	"dark": {
+		Primary:     "39",
+		Accent:      "75",
+		Text:        "252",
+		Muted:       "243",
+		Border:      "240",
+		Success:     "42",
+		Warning:     "214",
+		Error:       "196",
+		Info:        "45",
+		BadgeText:   "232",
+		HeaderBg:    "237",
+		SelectedFg:  "39",
+		SelectedBg:  "236",
+		BlurredFg:   "245",
+		BlurredBg:   "240",
+		StatusBarFg: "241",
+		StatusBarBg: "235",
+		PanelBg:     "235",
+	},
+	"light": {
+		Primary:     "25",
+		Accent:      "31",
+		Text:        "235",
+		Muted:       "242",
+		Border:      "245",
+		Success:     "28",
+		Warning:     "130",
+		Error:       "160",
+		Info:        "30",
+		BadgeText:   "231",
+		HeaderBg:    "253",
+		SelectedFg:  "231",
+		SelectedBg:  "25",
+		BlurredFg:   "235",
+		BlurredBg:   "250",
+		StatusBarFg: "238",
+		StatusBarBg: "254",
+		PanelBg:     "255",
+	},
+	"high-contrast": {
+		Primary:     "51",
+		Accent:      "226",
+		Text:        "231",
+		Muted:       "250",
+		Border:      "250",
+		Success:     "46",
+		Warning:     "226",
+		Error:       "196",
+		Info:        "51",
+		BadgeText:   "16",
+		HeaderBg:    "238",
+		SelectedFg:  "16",
+		SelectedBg:  "226",
+		BlurredFg:   "16",
+		BlurredBg:   "250",
+		StatusBarFg: "231",
+		StatusBarBg: "236",
+		PanelBg:     "16",
+	},
+}
+
+var (
+	theme  = Presets[DefaultTheme]
+	accent string // Profile accent, replaces the theme's when set
+
+	// NoColor is set when the NO_COLOR environment variable is, see
+	// https://no-color.org. lipgloss then renders no colors, so views must
+	// mark selections with reverse video or text instead.
+	NoColor = os.Getenv("NO_COLOR") != ""
+)
+
+func init() {
+	apply()
+}
+
+// SetTheme switches to the named theme and rebuilds the shared styles.
+// Custom themes are looked up in themes before the presets. An unknown or
+// broken theme leaves the default in place and returns an error.
+func SetTheme(name string, themes map[string]config.ThemeConfig) error {
+	if name == "" {
+		name = DefaultTheme
+	}
+	t, err := resolve(name, themes, nil)
+	if err != nil {
+		theme = Presets[DefaultTheme]
+	} else {
+		theme = t
+	}
+	apply()
+	return err
+}
+
+// ThemeNames returns the preset and custom theme names, sorted
+func ThemeNames(themes map[string]config.ThemeConfig) []string {
+	names := make([]string, 0, len(Presets)+len(themes))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	for name := range themes {
+		if _, ok := Presets[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolve looks a theme up and fills in the colors it leaves to its base.
+// A custom theme may shadow a preset of the same name and then inherit from
+// that preset.
+func resolve(name string, themes map[string]config.ThemeConfig, seen []string) (config.ThemeConfig, error) {
+	for _, s := range seen {
+		if s == name {
+			return config.ThemeConfig{}, fmt.Errorf("theme %q: base cycle %s", seen[0], strings.Join(append(seen, name), " -> "))
+		}
+	}
+
+	t, ok := themes[name]
+	if !ok {
+		if p, ok := Presets[name]; ok {
+			return p, nil
+		}
+		if len(seen) > 0 {
+			return config.ThemeConfig{}, fmt.Errorf("theme %q: unknown base %q", seen[len(seen)-1], name)
+		}
+		return config.ThemeConfig{}, fmt.Errorf("unknown theme %q (have %s)", name, strings.Join(ThemeNames(themes), ", "))
+	}
+
+	baseName := t.Base
+	if baseName == "" {
+		baseName = DefaultTheme
+	}
+	var base config.ThemeConfig
+	var err error
+	if baseName == name {
+		base = Presets[name]
+	} else {
+		base, err = resolve(baseName, themes, append(seen, name))
+	}
+	if err != nil {
+		return config.ThemeConfig{}, err
+	}
+	return t.Inherit(base), nil
+}
+
+// apply sets the colors from the theme and accent, then rebuilds the styles
+func apply() {
+	ColorBrandPrimary = lipgloss.Color(theme.Primary)
+	ColorBrandAccent = lipgloss.Color(theme.Accent)
+	if accent != "" {
+		ColorBrandAccent = lipgloss.Color(accent)
+	}
+	ColorTextPrimary = lipgloss.Color(theme.Text)
+	ColorTextMuted = lipgloss.Color(theme.Muted)
+	ColorBorderSubtle = lipgloss.Color(theme.Border)
+	ColorSuccess = lipgloss.Color(theme.Success)
+	ColorWarning = lipgloss.Color(theme.Warning)
+	ColorError = lipgloss.Color(theme.Error)
+	ColorInfo = lipgloss.Color(theme.Info)
+	ColorBadgeText = lipgloss.Color(theme.BadgeText)
+
+	ColorHeaderBg = lipgloss.Color(theme.HeaderBg)
+	ColorSelectedFg = lipgloss.Color(theme.SelectedFg)
+	ColorSelectedBg = lipgloss.Color(theme.SelectedBg)
+	ColorBlurredFg = lipgloss.Color(theme.BlurredFg)
+	ColorBlurredBg = lipgloss.Color(theme.BlurredBg)
+	ColorStatusBarFg = lipgloss.Color(theme.StatusBarFg)
+	ColorStatusBarBg = lipgloss.Color(theme.StatusBarBg)
+	ColorPanelBg = lipgloss.Color(theme.PanelBg)
+
+	build()
+}
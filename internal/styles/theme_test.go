@@ -0,0 +1,68 @@
+package styles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func TestSetTheme(t *testing.T) {
+	defer SetAccent("")
+	defer SetTheme("", nil)
+
+	themes := map[string]config.ThemeConfig{
+		"solarized": {Base: "light", SelectedBg: "#268bd2"},
+		"mine":      {Base: "solarized", Accent: "201"},
+		"dark":      {Text: "231"}, // Shadows the preset it inherits from
+	}
+
+	if err := SetTheme("mine", themes); err != nil {
+		t.Fatalf("SetTheme(mine) error = %v", err)
+	}
+	if ColorSelectedBg != "#268bd2" || ColorBrandAccent != "201" || ColorTextPrimary != lipgloss.Color(Presets["light"].Text) {
+		t.Errorf("mine: selected %v, accent %v, text %v", ColorSelectedBg, ColorBrandAccent, ColorTextPrimary)
+	}
+
+	// A profile accent survives theme changes until it is cleared
+	SetAccent("196")
+	if err := SetTheme("dark", themes); err != nil {
+		t.Fatalf("SetTheme(dark) error = %v", err)
+	}
+	if ColorBrandAccent != "196" || ColorTextPrimary != "231" || ColorHeaderBg != lipgloss.Color(Presets["dark"].HeaderBg) {
+		t.Errorf("dark: accent %v, text %v, header %v", ColorBrandAccent, ColorTextPrimary, ColorHeaderBg)
+	}
+	SetAccent("")
+	if ColorBrandAccent != lipgloss.Color(Presets["dark"].Accent) {
+		t.Errorf("accent after reset = %v", ColorBrandAccent)
+	}
+
+	// Errors fall back to the default theme
+	themes["loop"] = config.ThemeConfig{Base: "loop2"}
+	themes["loop2"] = config.ThemeConfig{Base: "loop"}
+	themes["orphan"] = config.ThemeConfig{Base: "nope"}
+	tests := map[string]string{
+		"missing":       "unknown theme",
+		"loop":          "base cycle",
+		"orphan":        "unknown base",
+		"light":         "",
+		"high-contrast": "",
+	}
+	for name, want := range tests {
+		SetTheme("light", nil)
+		err := SetTheme(name, themes)
+		if want == "" {
+			if err != nil {
+				t.Errorf("SetTheme(%s) error = %v", name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("SetTheme(%s) error = %v, want %q", name, err, want)
+		}
+		if ColorTextPrimary != lipgloss.Color(Presets[DefaultTheme].Text) {
+			t.Errorf("SetTheme(%s) did not fall back to %s", name, DefaultTheme)
+		}
+	}
+}
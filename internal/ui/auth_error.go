@@ -7,7 +7,7 @@ import (
 
 func renderAuthError(m MainModel) string {
 	errTitle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")). // Red
+		Foreground(styles.ColorError).
 		Bold(true).
 		Render("⚠️  Authentication Error ⚠️")
 
@@ -33,7 +33,7 @@ func renderAuthError(m MainModel) string {
 
 	box := styles.BoxStyle.Copy().
 		Border(lipgloss.DoubleBorder()).
-		BorderForeground(lipgloss.Color("196")).
+		BorderForeground(styles.ColorError).
 		Padding(1, 2).
 		Width(60).
 		Render(
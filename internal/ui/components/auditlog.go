@@ -0,0 +1,174 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/styles"
+)
+
+// maxAuditRecords bounds how much history the viewer loads
+const maxAuditRecords = 500
+
+// auditRecord merges the started and finished lines of one action
+type auditRecord struct {
+	core.AuditEntry      // Latest line seen for the action
+	Pending         bool // Started but never finished
+}
+
+// AuditLogModel is the overlay listing actions from the local audit trail
+type AuditLogModel struct {
+	Width  int
+	Height int
+
+	records []auditRecord // Newest first
+	err     error
+	cursor  int
+}
+
+// NewAuditLog creates an audit log overlay
+func NewAuditLog() AuditLogModel {
+	return AuditLogModel{}
+}
+
+// Open reloads the audit trail from disk
+func (m *AuditLogModel) Open() {
+	m.cursor = 0
+	m.records = nil
+
+	// Twice the record limit, since most actions are two lines
+	entries, err := core.ReadAuditLog(2 * maxAuditRecords)
+	m.err = err
+
+	index := make(map[string]int)
+	var records []auditRecord
+	for _, e := range entries {
+		if i, ok := index[e.ActionID]; ok {
+			records[i] = auditRecord{AuditEntry: e, Pending: e.Phase != core.AuditFinished}
+			continue
+		}
+		index[e.ActionID] = len(records)
+		records = append(records, auditRecord{AuditEntry: e, Pending: e.Phase != core.AuditFinished})
+	}
+	for i := len(records) - 1; i >= 0 && len(m.records) < maxAuditRecords; i-- {
+		m.records = append(m.records, records[i])
+	}
+}
+
+// Update handles navigation inside the overlay. The caller handles closing it.
+func (m AuditLogModel) Update(msg tea.Msg) (AuditLogModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if m.cursor < len(m.records)-1 {
+				m.cursor++
+			}
+		case key.Matches(msg, keys.Top):
+			m.cursor = 0
+		case key.Matches(msg, keys.Refresh):
+			m.Open()
+		}
+	}
+	return m, nil
+}
+
+// View renders the overlay
+func (m AuditLogModel) View() string {
+	title := styles.TitleStyle.Copy().
+		Foreground(styles.ColorBrandAccent).
+		Bold(true).
+		Render("Action History")
+
+	muted := lipgloss.NewStyle().Foreground(styles.ColorTextMuted)
+	path, _ := core.DefaultAuditPath()
+	subtitle := muted.Render(fmt.Sprintf("%s  •  %d actions  •  %s", path, len(m.records),
+		keys.HelpLine(keys.Top, keys.As(keys.Refresh, "Reload"), keys.As(keys.Back, "Close"))))
+
+	header := fmt.Sprintf("%-19s  %-8s  %-8s  %-7s  %-20s  %-30s  %s",
+		"TIME", "RESULT", "SERVICE", "ACTION", "PROJECT", "RESOURCE", "IDENTITY")
+
+	// Leave room for title, subtitle, header and the detail footer
+	visible := m.Height - 10
+	if visible < 3 {
+		visible = 3
+	}
+	start := 0
+	if m.cursor >= visible {
+		start = m.cursor - visible + 1
+	}
+
+	var rows strings.Builder
+	rows.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.ColorTextPrimary).Render(header))
+	if m.err != nil {
+		rows.WriteString("\n" + styles.ErrorStyle.Render(m.err.Error()))
+	} else if len(m.records) == 0 {
+		rows.WriteString("\n" + muted.Render("No actions recorded yet."))
+	}
+	for i := start; i < len(m.records) && i < start+visible; i++ {
+		row := formatAuditRow(m.records[i])
+		if m.Width > 4 && lipgloss.Width(row) > m.Width-4 {
+			row = row[:m.Width-5] + "…"
+		}
+		style := lipgloss.NewStyle().Foreground(auditColor(m.records[i]))
+		if i == m.cursor {
+			style = style.Reverse(true)
+		}
+		rows.WriteString("\n" + style.Render(row))
+	}
+
+	detail := ""
+	if m.cursor < len(m.records) {
+		r := m.records[m.cursor]
+		detail = muted.Render(fmt.Sprintf("from %s  •  action %s", r.Host, r.ActionID))
+		if r.Impersonated != "" {
+			detail += muted.Render("  •  as " + r.Impersonated)
+		}
+		if r.OperationID != "" {
+			detail += muted.Render("  •  operation " + r.OperationID)
+		}
+		if r.Error != "" {
+			detail += "\n" + lipgloss.NewStyle().Foreground(styles.ColorError).Render(r.Error)
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", rows.String(), "", detail)
+	return lipgloss.NewStyle().Padding(1, 2).Render(content)
+}
+
+func formatAuditRow(r auditRecord) string {
+	result := r.Result
+	if r.Pending {
+		result = "pending"
+	}
+	return fmt.Sprintf("%-19s  %-8s  %-8s  %-7s  %-20s  %-30s  %s",
+		r.Time.Local().Format("2006-01-02 15:04:05"),
+		result,
+		r.Service,
+		r.Action,
+		r.Project,
+		r.Resource,
+		r.Identity,
+	)
+}
+
+func auditColor(r auditRecord) lipgloss.Color {
+	switch {
+	case r.Pending:
+		return styles.ColorWarning
+	case r.Result == core.AuditError:
+		return styles.ColorError
+	case r.Result == core.AuditBlocked:
+		return styles.ColorTextMuted
+	default:
+		return styles.ColorTextPrimary
+	}
+}
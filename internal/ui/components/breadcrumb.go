@@ -10,6 +10,18 @@ import (
 // Breadcrumb separator
 const breadcrumbSep = " › "
 
+// trailLen is the number of places to go back to shown before a breadcrumb
+const trailLen = 3
+
+// breadcrumbTrail holds the places to go back to, oldest first
+var breadcrumbTrail []string
+
+// SetBreadcrumbTrail sets the navigation history that breadcrumbs show
+// before the current location, oldest place first
+func SetBreadcrumbTrail(places []string) {
+	breadcrumbTrail = places
+}
+
 // Breadcrumb renders a consistent breadcrumb line.
 // Empty segments are ignored.
 // Path segments are muted, current location (last segment) is prominent.
@@ -24,6 +36,23 @@ func Breadcrumb(parts ...string) string {
 	if len(segments) == 0 {
 		return ""
 	}
+	return trail() + crumbs(segments)
+}
+
+// trail renders the last few places of the navigation history, muted
+func trail() string {
+	if len(breadcrumbTrail) == 0 {
+		return ""
+	}
+	places := breadcrumbTrail
+	if len(places) > trailLen {
+		places = append([]string{"…"}, places[len(places)-trailLen:]...)
+	}
+	sepStyle := lipgloss.NewStyle().Foreground(styles.ColorBorderSubtle)
+	return styles.SubtleStyle.Render("↩ "+strings.Join(places, breadcrumbSep)) + sepStyle.Render(" │ ")
+}
+
+func crumbs(segments []string) string {
 
 	// Style definitions
 	pathStyle := styles.SubtleStyle
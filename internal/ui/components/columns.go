@@ -0,0 +1,139 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/styles"
+)
+
+// ColumnEditorModel shows, hides and reorders the columns of a table.
+// Changes apply to the table as they are made.
+type ColumnEditorModel struct {
+	Width  int
+	Height int
+
+	Table  *StandardTable
+	titles []string
+	shown  []bool
+	cursor int
+}
+
+// NewColumnEditor creates a column editor for a table
+func NewColumnEditor(t *StandardTable) ColumnEditorModel {
+	titles, shown := t.ColumnLayout()
+	return ColumnEditorModel{Table: t, titles: titles, shown: shown}
+}
+
+// HandleKey applies a key press and reports whether the editor is done.
+// The caller should then run Table.SaveLayout.
+func (m ColumnEditorModel) HandleKey(msg tea.KeyMsg) (ColumnEditorModel, bool) {
+	switch {
+	case key.Matches(msg, keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, keys.Down):
+		if m.cursor < len(m.titles)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, keys.MoveUp):
+		m.move(-1)
+	case key.Matches(msg, keys.MoveDown):
+		m.move(1)
+	case key.Matches(msg, keys.ToggleColumn):
+		// Keep at least one column
+		if !m.shown[m.cursor] || m.shownCount() > 1 {
+			m.shown[m.cursor] = !m.shown[m.cursor]
+			m.apply()
+		}
+	case key.Matches(msg, keys.SortColumn):
+		for i, c := range m.Table.columns {
+			if c.Title == m.titles[m.cursor] {
+				m.Table.SortBy(i)
+			}
+		}
+	case key.Matches(msg, keys.Select, keys.Back, keys.Columns):
+		return m, true
+	}
+	return m, false
+}
+
+func (m *ColumnEditorModel) move(step int) {
+	to := m.cursor + step
+	if to < 0 || to >= len(m.titles) {
+		return
+	}
+	m.titles[m.cursor], m.titles[to] = m.titles[to], m.titles[m.cursor]
+	m.shown[m.cursor], m.shown[to] = m.shown[to], m.shown[m.cursor]
+	m.cursor = to
+	m.apply()
+}
+
+func (m ColumnEditorModel) shownCount() int {
+	n := 0
+	for _, s := range m.shown {
+		if s {
+			n++
+		}
+	}
+	return n
+}
+
+func (m ColumnEditorModel) apply() {
+	m.Table.SetColumnLayout(m.titles, m.shown)
+}
+
+// View renders the editor centered on screen
+func (m ColumnEditorModel) View() string {
+	title := lipgloss.NewStyle().Foreground(styles.ColorBrandAccent).Bold(true).Render("☰ Table Columns")
+	muted := lipgloss.NewStyle().Foreground(styles.ColorTextMuted)
+	selected := lipgloss.NewStyle().Foreground(styles.ColorBrandAccent).Bold(true).Reverse(true)
+
+	sortTitle := m.Table.layout.SortBy
+	var rows []string
+	for i, t := range m.titles {
+		check := "[ ]"
+		if m.shown[i] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf(" %s %-24s", check, t)
+		if strings.EqualFold(t, sortTitle) {
+			dir := "ascending"
+			if m.Table.layout.SortDesc {
+				dir = "descending"
+			}
+			line += muted.Render(" sorted " + dir)
+		}
+		if i == m.cursor {
+			line = selected.Render(line)
+		}
+		rows = append(rows, line)
+	}
+
+	name := m.Table.Name()
+	saved := muted.Render("Saved to ~/.tgcprc as \"" + name + "\"")
+	if name == "" {
+		saved = muted.Render("This table's layout is not saved")
+	}
+	help := muted.Render(keys.HelpLine(keys.ToggleColumn, keys.MoveUp, keys.MoveDown, keys.SortColumn, keys.As(keys.Select, "Done")))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorBrandAccent).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			title,
+			"",
+			strings.Join(rows, "\n"),
+			"",
+			saved,
+			help,
+		))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
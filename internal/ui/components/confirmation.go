@@ -2,9 +2,14 @@ package components
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/keys"
 	"github.com/yogirk/tgcp/internal/styles"
 )
 
@@ -16,8 +21,22 @@ type ConfirmationModel struct {
 	Message       string // Optional custom message (overrides default)
 	Width         int
 	Height        int
+
+	// RequireName makes the user type ResourceName before Enter confirms,
+	// used for resources in protected projects
+	RequireName bool
+	input       textinput.Model
 }
 
+// ConfirmResult is the outcome of a key press in a confirmation dialog
+type ConfirmResult int
+
+const (
+	ConfirmPending ConfirmResult = iota
+	ConfirmAccepted
+	ConfirmCancelled
+)
+
 // NewConfirmationModel creates a new confirmation dialog
 func NewConfirmationModel(action, resourceName, resourceType string) ConfirmationModel {
 	return ConfirmationModel{
@@ -37,6 +56,108 @@ func NewConfirmationModelWithMessage(action, resourceName, resourceType, message
 	}
 }
 
+// NewProtectedConfirmation creates a confirmation that is only accepted
+// once the resource name has been typed
+func NewProtectedConfirmation(action, resourceName, resourceType string) ConfirmationModel {
+	ti := textinput.New()
+	ti.Placeholder = resourceName
+	ti.CharLimit = 256
+	ti.Width = 40
+	ti.Prompt = "> "
+	ti.Focus()
+
+	m := NewConfirmationModel(action, resourceName, resourceType)
+	m.RequireName = true
+	m.input = ti
+	return m
+}
+
+// NewConfirmationFor picks a typed-name confirmation for protected projects
+// and the plain y/n dialog otherwise
+func NewConfirmationFor(action, resourceName, resourceType string, protected bool) ConfirmationModel {
+	if protected {
+		return NewProtectedConfirmation(action, resourceName, resourceType)
+	}
+	return NewConfirmationModel(action, resourceName, resourceType)
+}
+
+// maxBulkTargets is how many targets a bulk confirmation lists by name
+const maxBulkTargets = 12
+
+// NewBulkConfirmation creates one confirmation for an action on several
+// resources, listing them all. In protected projects the number of targets
+// must be typed to confirm.
+func NewBulkConfirmation(action, resourceType string, names []string, protected bool) ConfirmationModel {
+	count := strconv.Itoa(len(names))
+	targets := make([]string, 0, maxBulkTargets+1)
+	for i, name := range names {
+		if i == maxBulkTargets {
+			targets = append(targets, fmt.Sprintf("… and %d more", len(names)-maxBulkTargets))
+			break
+		}
+		targets = append(targets, "• "+name)
+	}
+	message := fmt.Sprintf("Are you sure you want to %s %s %ss?\n\n%s",
+		actionVerb(action),
+		styles.TitleStyle.Render(count),
+		resourceType,
+		strings.Join(targets, "\n"),
+	)
+
+	m := NewConfirmationFor(action, count, resourceType, protected)
+	m.Message = message
+	return m
+}
+
+// HandleKey applies a key press to the dialog. Plain dialogs accept on
+// keys.Confirm and cancel on keys.Cancel; typed dialogs only accept
+// keys.ConfirmTyped once the input matches the resource name, and every key
+// but keys.CancelTyped goes to the input.
+func (m ConfirmationModel) HandleKey(msg tea.KeyMsg) (ConfirmationModel, ConfirmResult, tea.Cmd) {
+	if !m.RequireName {
+		switch {
+		case key.Matches(msg, keys.Confirm):
+			return m, ConfirmAccepted, nil
+		case key.Matches(msg, keys.Cancel):
+			return m, ConfirmCancelled, nil
+		}
+		return m, ConfirmPending, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.CancelTyped):
+		return m, ConfirmCancelled, nil
+	case key.Matches(msg, keys.ConfirmTyped):
+		if m.input.Value() == m.ResourceName {
+			return m, ConfirmAccepted, nil
+		}
+		return m, ConfirmPending, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, ConfirmPending, cmd
+}
+
+// HelpText returns the status bar hint for the dialog
+func (m ConfirmationModel) HelpText() string {
+	if m.RequireName {
+		return "Type name + " + keys.HelpLine(keys.ConfirmTyped, keys.CancelTyped)
+	}
+	return keys.HelpLine(keys.Confirm, keys.Cancel)
+}
+
+// bindingHint renders bindings with RenderFooterHint, e.g. [y/Enter] Confirm
+func bindingHint(bindings ...key.Binding) string {
+	var parts []string
+	for _, b := range bindings {
+		if b.Enabled() {
+			parts = append(parts, b.Help().Key+" "+b.Help().Desc)
+		}
+	}
+	return RenderFooterHint(strings.Join(parts, " | "))
+}
+
 // Update handles messages (for future: interactive buttons, etc.)
 func (m ConfirmationModel) Update(msg tea.Msg) (ConfirmationModel, tea.Cmd) {
 	// For now, confirmation component is static
@@ -121,11 +242,20 @@ func (m ConfirmationModel) View() string {
 	}
 
 	// Help text
-	helpText := RenderFooterHint("y Confirm | n Cancel")
+	helpText := bindingHint(keys.Confirm, keys.Cancel)
 
 	// Build content parts
 	parts := []string{title, "", actionText}
 
+	// Protected resources must be confirmed by typing their name
+	if m.RequireName {
+		prompt := lipgloss.NewStyle().
+			Foreground(styles.ColorWarning).
+			Render("Protected project. Type " + styles.TitleStyle.Render(m.ResourceName) + " to confirm:")
+		parts = append(parts, "", prompt, m.input.View())
+		helpText = bindingHint(keys.ConfirmTyped, keys.CancelTyped)
+	}
+
 	// Add impact text for dangerous actions
 	if style.impactText != "" {
 		impactStyled := lipgloss.NewStyle().
@@ -158,32 +288,32 @@ func (m ConfirmationModel) View() string {
 
 // buildActionText constructs the confirmation message based on action type
 func (m ConfirmationModel) buildActionText() string {
-	actionUpper := capitalize(m.Action)
 	resourceNameStyled := styles.TitleStyle.Render(m.ResourceName)
-	
-	// Build action verb based on action type
-	var verb string
-	switch m.Action {
+
+	return fmt.Sprintf(
+		"Are you sure you want to %s %s %s?",
+		actionVerb(m.Action),
+		m.ResourceType,
+		resourceNameStyled,
+	)
+}
+
+// actionVerb returns the verb shown in confirmations for an action
+func actionVerb(action string) string {
+	switch action {
 	case "start":
-		verb = "START"
+		return "START"
 	case "stop":
-		verb = "STOP"
+		return "STOP"
 	case "delete":
-		verb = "DELETE"
+		return "DELETE"
 	case "restart":
-		verb = "RESTART"
+		return "RESTART"
 	case "snapshot":
-		verb = "CREATE SNAPSHOT OF"
+		return "CREATE SNAPSHOT OF"
 	default:
-		verb = actionUpper
+		return capitalize(action)
 	}
-
-	return fmt.Sprintf(
-		"Are you sure you want to %s %s %s?",
-		verb,
-		m.ResourceType,
-		resourceNameStyled,
-	)
 }
 
 // capitalize capitalizes the first letter of a string
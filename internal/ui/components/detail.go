@@ -109,7 +109,7 @@ func renderKeyValues(rows []KeyValue, keyStyle, valueStyle lipgloss.Style) strin
 func RenderFooterHint(hint string) string {
 	// Style for the key badge [s]
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("232")).
+		Foreground(styles.ColorBadgeText).
 		Background(styles.ColorBorderSubtle).
 		Bold(true).
 		Padding(0, 0)
@@ -0,0 +1,244 @@
+package components
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yogirk/tgcp/internal/utils"
+)
+
+// ExportFormat is a serialization format for table exports
+type ExportFormat string
+
+const (
+	ExportCSV      ExportFormat = "csv"
+	ExportJSON     ExportFormat = "json"
+	ExportMarkdown ExportFormat = "markdown"
+)
+
+// ExportFormats lists the export formats in the order the dialog cycles them
+var ExportFormats = []ExportFormat{ExportCSV, ExportJSON, ExportMarkdown}
+
+// Ext returns the file extension for the format
+func (f ExportFormat) Ext() string {
+	if f == ExportMarkdown {
+		return "md"
+	}
+	return string(f)
+}
+
+// TableExport is a snapshot of a table view, ready to be serialized
+type TableExport struct {
+	Name      string // Used for file names, e.g. "gce-instances"
+	Columns   []string
+	Rows      [][]string
+	Items     interface{} // Slice of the models behind the rows, nil if unavailable
+	Selection bool        // Only the marked rows are exported
+}
+
+// NewTableExport snapshots the rows a table currently shows, in their sort
+// order, or only the marked rows when some are marked. items must be the
+// slice of models behind the rows passed to SetRows.
+func NewTableExport(name string, t *StandardTable, items interface{}) TableExport {
+	cols := t.exportColumns()
+	columns := make([]string, len(cols))
+	for i, c := range cols {
+		columns[i] = c.Title
+	}
+
+	marked := make(map[int]bool)
+	for _, i := range t.Marked() {
+		marked[i] = true
+	}
+
+	display := t.displayRows()
+	rows := make([][]string, 0, len(display))
+	order := make([]int, 0, len(display))
+	for pos, i := range t.order {
+		if len(marked) > 0 && !marked[i] {
+			continue
+		}
+		row := make([]string, len(display[pos]))
+		for c, cell := range display[pos] {
+			row[c] = strings.TrimSpace(ansi.Strip(cell))
+		}
+		rows = append(rows, row)
+		order = append(order, i)
+	}
+
+	return TableExport{
+		Name:      name,
+		Columns:   columns,
+		Rows:      rows,
+		Items:     pickItems(items, order, len(t.rows)),
+		Selection: len(marked) > 0,
+	}
+}
+
+// pickItems returns the models at the given indexes, in that order. items
+// are returned unchanged if they do not match the table's rows.
+func pickItems(items interface{}, order []int, total int) interface{} {
+	v := reflect.ValueOf(items)
+	if items == nil || v.Kind() != reflect.Slice || v.Len() != total {
+		return items
+	}
+	picked := reflect.MakeSlice(v.Type(), len(order), len(order))
+	for pos, i := range order {
+		picked.Index(pos).Set(v.Index(i))
+	}
+	return picked.Interface()
+}
+
+// HasItems reports whether the export can serialize the full models
+func (e TableExport) HasItems() bool {
+	return e.Items != nil && reflect.ValueOf(e.Items).Kind() == reflect.Slice
+}
+
+// Write serializes the export. With full set, every field of the
+// underlying models is written instead of the (possibly truncated) cells.
+func (e TableExport) Write(w io.Writer, format ExportFormat, full bool) error {
+	columns, rows := e.Columns, e.Rows
+	if full && e.HasItems() {
+		if format == ExportJSON {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(utils.Normalize(e.Items))
+		}
+		columns, rows = flattenItems(e.Items)
+	}
+
+	switch format {
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case ExportJSON:
+		return writeJSONRows(w, columns, rows)
+	case ExportMarkdown:
+		return writeMarkdown(w, columns, rows)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// writeJSONRows writes one object per row, keeping the column order
+func writeJSONRows(w io.Writer, columns []string, rows [][]string) error {
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  {")
+		for j, col := range columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			cell := ""
+			if j < len(row) {
+				cell = row[j]
+			}
+			k, _ := json.Marshal(col)
+			v, _ := json.Marshal(cell)
+			b.Write(k)
+			b.WriteString(": ")
+			b.Write(v)
+		}
+		b.WriteString("}")
+	}
+	if len(rows) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeMarkdown writes a GitHub-flavored Markdown table
+func writeMarkdown(w io.Writer, columns []string, rows [][]string) error {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, c := range cells {
+			c = strings.ReplaceAll(c, "|", `\|`)
+			c = strings.ReplaceAll(c, "\n", " ")
+			b.WriteString(" " + c + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(columns)
+	sep := make([]string, len(columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(sep)
+	for _, row := range rows {
+		writeRow(row)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// flattenItems turns a slice of models into one column per field, named and
+// formatted as in the JSON export. Nested values (slices, maps, structs) are
+// written as compact JSON.
+func flattenItems(items interface{}) ([]string, [][]string) {
+	normalized, _ := utils.Normalize(items).([]interface{})
+	columns := utils.NormalizedKeys(reflect.TypeOf(items).Elem())
+	if columns == nil {
+		rows := make([][]string, len(normalized))
+		for i, item := range normalized {
+			rows[i] = []string{formatField(item)}
+		}
+		return []string{"value"}, rows
+	}
+
+	rows := make([][]string, len(normalized))
+	for i, item := range normalized {
+		row := make([]string, len(columns))
+		fields, _ := item.(utils.Fields) // nil for a nil pointer
+		for j, f := range fields {
+			row[j] = formatField(f.Value)
+		}
+		rows[i] = row
+	}
+	return columns, rows
+}
+
+// formatField renders a normalized value as a cell
+func formatField(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return ""
+		}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return ""
+		}
+	case utils.Fields:
+	default:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
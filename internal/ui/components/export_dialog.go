@@ -0,0 +1,123 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/styles"
+)
+
+// ExportDestination is where the user chose to send an export
+type ExportDestination int
+
+const (
+	ExportPending  ExportDestination = iota
+	ExportToFile                     // Write a file in the working directory
+	ExportToStdout                   // Print when tgcp exits
+	ExportCancelled
+)
+
+// ExportDialogModel lets the user pick the format and destination of a
+// table export
+type ExportDialogModel struct {
+	Width  int
+	Height int
+
+	Export TableExport
+	Format ExportFormat
+	Full   bool // Serialize the full models instead of the table cells
+}
+
+// NewExportDialog creates an export dialog for a table snapshot
+func NewExportDialog(export TableExport) ExportDialogModel {
+	return ExportDialogModel{Export: export, Format: ExportCSV}
+}
+
+// HandleKey applies a key press and reports the chosen destination, if any
+func (m ExportDialogModel) HandleKey(msg tea.KeyMsg) (ExportDialogModel, ExportDestination) {
+	switch {
+	case key.Matches(msg, keys.ExportCSV):
+		m.Format = ExportCSV
+	case key.Matches(msg, keys.ExportJSON):
+		m.Format = ExportJSON
+	case key.Matches(msg, keys.ExportMarkdown):
+		m.Format = ExportMarkdown
+	case key.Matches(msg, keys.NextFormat):
+		m.Format = m.cycleFormat(1)
+	case key.Matches(msg, keys.PrevFormat):
+		m.Format = m.cycleFormat(-1)
+	case key.Matches(msg, keys.ExportFull):
+		if m.Export.HasItems() {
+			m.Full = !m.Full
+		}
+	case key.Matches(msg, keys.ExportFile):
+		return m, ExportToFile
+	case key.Matches(msg, keys.ExportStdout):
+		return m, ExportToStdout
+	case key.Matches(msg, keys.Cancel):
+		return m, ExportCancelled
+	}
+	return m, ExportPending
+}
+
+func (m ExportDialogModel) cycleFormat(step int) ExportFormat {
+	for i, f := range ExportFormats {
+		if f == m.Format {
+			return ExportFormats[(i+step+len(ExportFormats))%len(ExportFormats)]
+		}
+	}
+	return ExportFormats[0]
+}
+
+// View renders the dialog centered on screen
+func (m ExportDialogModel) View() string {
+	title := lipgloss.NewStyle().Foreground(styles.ColorBrandAccent).Bold(true).Render("⇩ Export Table")
+	muted := lipgloss.NewStyle().Foreground(styles.ColorTextMuted)
+	selected := lipgloss.NewStyle().Foreground(styles.ColorBrandAccent).Bold(true).Reverse(true)
+
+	var formats []string
+	for _, f := range ExportFormats {
+		label := fmt.Sprintf(" %s ", strings.ToUpper(string(f)))
+		if f == m.Format {
+			formats = append(formats, selected.Render(label))
+		} else {
+			formats = append(formats, label)
+		}
+	}
+
+	content := "Visible columns"
+	switch {
+	case !m.Export.HasItems():
+		content += muted.Render("  (full models not available here)")
+	case m.Full:
+		content = "All fields of the underlying resources"
+	}
+
+	summary := fmt.Sprintf("%d row(s) from %s", len(m.Export.Rows), m.Export.Name)
+	if m.Export.Selection {
+		summary = fmt.Sprintf("%d selected row(s) from %s", len(m.Export.Rows), m.Export.Name)
+	}
+	help := muted.Render(keys.HelpLine(keys.ExportCSV, keys.ExportJSON, keys.ExportMarkdown, keys.NextFormat) + "\n" +
+		keys.HelpLine(keys.ExportFull, keys.ExportFile, keys.ExportStdout, keys.Cancel))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorBrandAccent).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			title,
+			"",
+			summary,
+			"",
+			"Format:  "+strings.Join(formats, " "),
+			"Content: "+content,
+			"",
+			help,
+		))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,96 @@
+package components
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/config"
+	"github.com/yogirk/tgcp/internal/keys"
+)
+
+type exportItem struct {
+	Name    string
+	Tags    []string
+	Created time.Time
+	secret  string
+}
+
+func testExport() TableExport {
+	t := NewStandardTable([]table.Column{{Title: "NAME", Width: 4}, {Title: "STATUS", Width: 6}})
+	t.SetRows([]table.Row{{"web|1", "\x1b[32mRUNNING\x1b[0m"}, {"db", ""}})
+	items := []exportItem{
+		{Name: "web|1", Tags: []string{"http"}, Created: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), secret: "x"},
+		{Name: "db"},
+	}
+	return NewTableExport("test", t, items)
+}
+
+func TestTableExportFormats(t *testing.T) {
+	tests := []struct {
+		format ExportFormat
+		full   bool
+		want   []string
+	}{
+		{ExportCSV, false, []string{"NAME,STATUS\n", "web|1,RUNNING\n", "db,\n"}},
+		{ExportJSON, false, []string{`{"NAME": "web|1", "STATUS": "RUNNING"}`}},
+		{ExportMarkdown, false, []string{"| NAME | STATUS |\n", "| --- | --- |\n", `| web\|1 | RUNNING |`}},
+		{ExportCSV, true, []string{"name,tags,created\n", `"[""http""]",2026-01-02T03:04:05Z`, "db,,\n"}},
+		{ExportJSON, true, []string{`"name": "web|1"`, `"tags": null`, `"created": "2026-01-02T03:04:05Z"`}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := testExport().Write(&buf, tt.format, tt.full); err != nil {
+			t.Fatalf("%s (full=%v): %v", tt.format, tt.full, err)
+		}
+		out := buf.String()
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s (full=%v) missing %q:\n%s", tt.format, tt.full, want, out)
+			}
+		}
+		if strings.Contains(out, "secret") || strings.Contains(out, "\x1b") {
+			t.Errorf("%s (full=%v) leaked unexported fields or escape codes:\n%s", tt.format, tt.full, out)
+		}
+	}
+}
+
+func TestTableExportWithoutItems(t *testing.T) {
+	e := testExport()
+	e.Items = nil
+	if e.HasItems() {
+		t.Fatal("HasItems should be false without items")
+	}
+
+	// Full mode falls back to the visible cells
+	var buf bytes.Buffer
+	if err := e.Write(&buf, ExportCSV, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "NAME,STATUS\n") {
+		t.Errorf("unexpected fallback output:\n%s", buf.String())
+	}
+}
+
+func TestExportDialogKeys(t *testing.T) {
+	defer keys.Configure(nil)
+	if err := keys.Configure(map[string]config.KeyList{"export_json": {"J"}, "export_stdout": {"p"}}); err != nil {
+		t.Fatal(err)
+	}
+	press := func(k string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)} }
+
+	d := NewExportDialog(testExport())
+	if d, _ = d.HandleKey(press("j")); d.Format != ExportCSV {
+		t.Errorf("unbound j chose %s", d.Format)
+	}
+	if d, _ = d.HandleKey(press("J")); d.Format != ExportJSON {
+		t.Errorf("rebound J chose %s", d.Format)
+	}
+	if _, dest := d.HandleKey(press("p")); dest != ExportToStdout {
+		t.Errorf("rebound p = %v, want ExportToStdout", dest)
+	}
+}
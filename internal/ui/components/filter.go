@@ -2,11 +2,14 @@ package components
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/keys"
 	"github.com/yogirk/tgcp/internal/styles"
 )
 
@@ -18,6 +21,7 @@ type FilterModel struct {
 	Active    bool
 	Matches   int
 	Total     int
+	Err       error // Why the query could not be applied, if it could not
 }
 
 // NewFilter creates a new FilterModel with default settings
@@ -68,7 +72,7 @@ func (m FilterModel) View() string {
 	hintStyle := styles.SubtleStyle
 	countStyle := styles.SubtleStyle
 	badgeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("232")).
+		Foreground(styles.ColorBadgeText).
 		Background(styles.ColorBrandAccent).
 		Padding(0, 1)
 	activeInputStyle := lipgloss.NewStyle().
@@ -84,6 +88,9 @@ func (m FilterModel) View() string {
 		countText = fmt.Sprintf("Matches: %d/%d", m.Matches, m.Total)
 	}
 	countView := countStyle.Render(countText)
+	if m.Err != nil && query != "" {
+		countView = styles.ErrorStyle.Render(m.Err.Error())
+	}
 
 	var bar string
 
@@ -127,6 +134,7 @@ func (m *FilterModel) EnterFilterMode() tea.Cmd {
 // ExitFilterMode deactivates the filter and resets the input
 func (m *FilterModel) ExitFilterMode() {
 	m.Active = false
+	m.Err = nil
 	m.TextInput.Blur()
 	m.TextInput.Reset()
 }
@@ -144,7 +152,7 @@ func (m *FilterModel) ExitFilterModeKeepValue() {
 func (m *FilterModel) HandleKeyMsg(msg tea.KeyMsg) (shouldExit bool, shouldKeepValue bool, cmd tea.Cmd) {
 	if !m.Active {
 		// Check if we should enter filter mode
-		if msg.String() == "/" {
+		if key.Matches(msg, keys.Filter) {
 			cmd = m.EnterFilterMode()
 			return false, false, cmd
 		}
@@ -172,9 +180,11 @@ func (m *FilterModel) HandleKeyMsg(msg tea.KeyMsg) (shouldExit bool, shouldKeepV
 }
 
 // IsNavigationKey checks if a key is a navigation key that should pass through to the table
-func IsNavigationKey(key string) bool {
-	return key == "up" || key == "down" || key == "j" || key == "k" || key == "g" || key == "G" ||
-		key == "home" || key == "end" || key == "pageup" || key == "pagedown"
+func IsNavigationKey(k string) bool {
+	if slices.Contains(keys.Up.Keys(), k) || slices.Contains(keys.Down.Keys(), k) {
+		return true
+	}
+	return k == "g" || k == "G" || k == "home" || k == "end" || k == "pageup" || k == "pagedown"
 }
 
 // Value returns the current filter query string
@@ -182,11 +192,22 @@ func (m FilterModel) Value() string {
 	return m.TextInput.Value()
 }
 
+// SetValue sets the query without entering filter mode, e.g. to restore a
+// list's filter
+func (m *FilterModel) SetValue(query string) {
+	m.TextInput.SetValue(query)
+}
+
 // IsActive returns whether the filter is currently active
 func (m FilterModel) IsActive() bool {
 	return m.Active
 }
 
+// SetError records why the query could not be applied, or clears it
+func (m *FilterModel) SetError(err error) {
+	m.Err = err
+}
+
 // SetMatchCounts updates the total and matched counts for the filter bar.
 func (m *FilterModel) SetMatchCounts(total, matches int) {
 	m.Total = total
@@ -24,6 +24,22 @@ func NewFilterSession[T any](
 	}
 }
 
+// NewFieldFilterSession creates a session that evaluates the filter as a
+// query (see FilterQuery) against the declared fields. Malformed queries
+// leave the list unfiltered and show the error in the filter bar.
+func NewFieldFilterSession[T any](
+	filter *FilterModel,
+	fields []FilterField[T],
+	updateTable func([]T),
+) FilterSession[T] {
+	getFiltered := func(items []T, query string) []T {
+		filtered, err := FilterByQuery(items, query, fields)
+		filter.SetError(err)
+		return filtered
+	}
+	return NewFilterSession(filter, getFiltered, updateTable)
+}
+
 // Apply stores the full list, applies the current query, and updates counts.
 func (s *FilterSession[T]) Apply(items []T) {
 	s.allItems = items
@@ -49,3 +65,11 @@ func (s *FilterSession[T]) HandleKey(msg tea.KeyMsg) FilterUpdateResult {
 		s.updateTable,
 	)
 }
+
+// Filtered returns the items matching the current query, as shown in the table.
+func (s *FilterSession[T]) Filtered() []T {
+	if s.filter == nil {
+		return s.allItems
+	}
+	return s.getFiltered(s.allItems, s.filter.Value())
+}
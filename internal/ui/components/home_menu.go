@@ -1,8 +1,10 @@
 package components
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/keys"
 	"github.com/yogirk/tgcp/internal/styles"
 )
 
@@ -140,16 +142,16 @@ func (m HomeMenuModel) Update(msg tea.Msg) (HomeMenuModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch {
+		case key.Matches(msg, keys.Up):
 			if m.Cursor > 0 {
 				m.Cursor--
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			if m.Cursor < len(visibleItems)-1 {
 				m.Cursor++
 			}
-		case " ": // Space to toggle category
+		case msg.String() == " ": // Space to toggle category
 			if m.Cursor < len(visibleItems) {
 				item := visibleItems[m.Cursor]
 				if item.isCategory {
@@ -0,0 +1,227 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/styles"
+)
+
+// InspectorTickMsg refreshes the inspector while it is open
+type InspectorTickMsg struct {
+	Time time.Time
+	gen  int // Open call that started the ticker
+}
+
+// InspectorModel is the API call inspector overlay
+type InspectorModel struct {
+	Width  int
+	Height int
+
+	calls    []core.APICall // Snapshot, newest first
+	services []string       // Services seen so far, for filter cycling
+	service  string         // Active service filter ("" = all)
+	cursor   int
+	gen      int // Bumped by Open so that a reopen doesn't start a second ticker
+}
+
+// NewInspector creates an inspector overlay
+func NewInspector() InspectorModel {
+	return InspectorModel{}
+}
+
+// Open takes a fresh snapshot and starts the live refresh ticker.
+// Opening from a service view pre-filters to that service.
+func (m *InspectorModel) Open(service string) tea.Cmd {
+	m.service = service
+	m.cursor = 0
+	m.gen++
+	m.snapshot()
+	return m.tick()
+}
+
+func (m InspectorModel) tick() tea.Cmd {
+	gen := m.gen
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return InspectorTickMsg{Time: t, gen: gen}
+	})
+}
+
+func (m *InspectorModel) snapshot() {
+	all := core.RecentAPICalls()
+
+	seen := make(map[string]bool)
+	m.services = m.services[:0]
+	m.calls = m.calls[:0]
+	for _, c := range all {
+		if !seen[c.Service] {
+			seen[c.Service] = true
+			m.services = append(m.services, c.Service)
+		}
+		if m.service == "" || c.Service == m.service {
+			m.calls = append(m.calls, c)
+		}
+	}
+	sort.Strings(m.services)
+
+	if m.cursor >= len(m.calls) {
+		m.cursor = max(0, len(m.calls)-1)
+	}
+}
+
+// cycleService moves the service filter by delta through "all" + seen services
+func (m *InspectorModel) cycleService(delta int) {
+	options := append([]string{""}, m.services...)
+	idx := 0
+	for i, s := range options {
+		if s == m.service {
+			idx = i
+		}
+	}
+	idx = (idx + delta + len(options)) % len(options)
+	m.service = options[idx]
+	m.cursor = 0
+	m.snapshot()
+}
+
+// Update handles navigation inside the overlay. The caller handles closing it.
+func (m InspectorModel) Update(msg tea.Msg) (InspectorModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case InspectorTickMsg:
+		if msg.gen != m.gen {
+			return m, nil // Ticker of an earlier Open
+		}
+		m.snapshot()
+		return m, m.tick()
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.NextService):
+			m.cycleService(1)
+		case key.Matches(msg, keys.PrevService):
+			m.cycleService(-1)
+		case key.Matches(msg, keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if m.cursor < len(m.calls)-1 {
+				m.cursor++
+			}
+		case key.Matches(msg, keys.Top):
+			m.cursor = 0
+		}
+	}
+	return m, nil
+}
+
+// View renders the overlay
+func (m InspectorModel) View() string {
+	title := styles.TitleStyle.Copy().
+		Foreground(styles.ColorBrandAccent).
+		Bold(true).
+		Render("API Call Inspector")
+
+	filter := "all services"
+	if m.service != "" {
+		filter = m.service
+	}
+	muted := lipgloss.NewStyle().Foreground(styles.ColorTextMuted)
+	subtitle := muted.Render(fmt.Sprintf("Filter: %s  •  %d calls  •  %s", filter, len(m.calls),
+		keys.HelpLine(keys.NextService, keys.Top, keys.As(keys.Back, "Close"))))
+
+	header := fmt.Sprintf("%-8s  %-9s  %-6s  %6s  %8s  %5s  %7s  %8s  %s",
+		"TIME", "SERVICE", "METHOD", "STATUS", "LATENCY", "RETRY", "WAIT", "SIZE", "PATH")
+
+	// Leave room for title, subtitle, header and the detail footer
+	visible := m.Height - 9
+	if visible < 3 {
+		visible = 3
+	}
+	start := 0
+	if m.cursor >= visible {
+		start = m.cursor - visible + 1
+	}
+
+	var rows strings.Builder
+	rows.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.ColorTextPrimary).Render(header))
+	if len(m.calls) == 0 {
+		rows.WriteString("\n" + muted.Render("No API calls recorded yet."))
+	}
+	for i := start; i < len(m.calls) && i < start+visible; i++ {
+		row := formatCallRow(m.calls[i])
+		if m.Width > 4 {
+			row = ansi.Truncate(row, m.Width-4, "…")
+		}
+		style := lipgloss.NewStyle().Foreground(statusColor(m.calls[i]))
+		if i == m.cursor {
+			style = style.Reverse(true)
+		}
+		rows.WriteString("\n" + style.Render(row))
+	}
+
+	detail := ""
+	if m.cursor < len(m.calls) {
+		c := m.calls[m.cursor]
+		detail = muted.Render(c.Method + " https://" + c.Host + c.Path)
+		if c.Error != "" {
+			detail += "\n" + lipgloss.NewStyle().Foreground(styles.ColorError).Render(c.Error)
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", rows.String(), "", detail)
+	return lipgloss.NewStyle().Padding(1, 2).Render(content)
+}
+
+func formatCallRow(c core.APICall) string {
+	status := "ERR"
+	if c.Status != 0 {
+		status = fmt.Sprintf("%d", c.Status)
+	}
+	wait := "-"
+	if c.RateLimitWait >= time.Millisecond {
+		wait = c.RateLimitWait.Round(time.Millisecond).String()
+	}
+	return fmt.Sprintf("%-8s  %-9s  %-6s  %6s  %8s  %5d  %7s  %8s  %s",
+		c.Time.Format("15:04:05"),
+		c.Service,
+		c.Method,
+		status,
+		c.Latency.Round(time.Millisecond),
+		c.Retries,
+		wait,
+		formatBytes(c.Size),
+		c.Path,
+	)
+}
+
+func statusColor(c core.APICall) lipgloss.Color {
+	switch {
+	case c.Status == 0 || c.Status >= 500:
+		return styles.ColorError
+	case c.Status >= 400:
+		return styles.ColorWarning
+	case c.Retries > 0 || c.RateLimitWait >= 100*time.Millisecond:
+		return styles.ColorInfo
+	default:
+		return styles.ColorTextPrimary
+	}
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
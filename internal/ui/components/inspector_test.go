@@ -0,0 +1,44 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yogirk/tgcp/internal/core"
+)
+
+func TestInspectorReopenKeepsOneTicker(t *testing.T) {
+	m := NewInspector()
+	m.Open("")
+	first := InspectorTickMsg{Time: time.Now(), gen: m.gen}
+	m.Open("gce")
+
+	if _, cmd := m.Update(first); cmd != nil {
+		t.Error("the ticker of the first Open should stop")
+	}
+	if _, cmd := m.Update(InspectorTickMsg{Time: time.Now(), gen: m.gen}); cmd == nil {
+		t.Error("the current ticker should keep ticking")
+	}
+}
+
+func TestInspectorTruncatesRowsByWidth(t *testing.T) {
+	m := NewInspector()
+	m.Width, m.Height = 60, 20
+	m.calls = []core.APICall{{
+		Time:    time.Now(),
+		Service: "gcs",
+		Method:  "GET",
+		Status:  200,
+		Path:    "/storage/v1/b/données-éphémères-日本語-バケット/o",
+	}}
+
+	view := m.View()
+	if !utf8.ValidString(view) {
+		t.Fatal("view contains a split rune")
+	}
+	if !strings.Contains(view, "…") {
+		t.Error("long row should end with an ellipsis")
+	}
+}
@@ -0,0 +1,51 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/styles"
+)
+
+// maxOperationRows keeps the panel small when many operations are pending
+const maxOperationRows = 5
+
+// RenderOperations renders the panel of pending long-running operations,
+// headed by the progress of any bulk actions
+func RenderOperations(ops []core.Operation, bulks []core.BulkProgress) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(styles.ColorInfo).
+		Bold(true)
+	muted := lipgloss.NewStyle().Foreground(styles.ColorTextMuted)
+	text := lipgloss.NewStyle().Foreground(styles.ColorTextPrimary)
+
+	var lines []string
+	for _, b := range bulks {
+		progress := fmt.Sprintf("%d/%d done", b.Done, b.Total)
+		if b.Failed > 0 {
+			progress += fmt.Sprintf(", %d failed", b.Failed)
+		}
+		lines = append(lines, titleStyle.Render("⟳ "+b.Description)+" "+muted.Render(progress))
+	}
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("⟳ %d operation(s) in progress", len(ops))))
+	for i, op := range ops {
+		if i == maxOperationRows {
+			lines = append(lines, muted.Render(fmt.Sprintf("  … and %d more", len(ops)-maxOperationRows)))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s",
+			text.Render(op.Description),
+			muted.Render(op.Elapsed().Round(time.Second).String()),
+		))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorInfo).
+		Padding(0, 2).
+		Background(styles.ColorPanelBg).
+		Render(strings.Join(lines, "\n"))
+}
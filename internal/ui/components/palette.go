@@ -130,20 +130,29 @@ func (m PaletteModel) Render(nav core.NavigationModel, screenWidth, screenHeight
 	// 2. Suggestions List
 	var suggestionsView string
 	if len(nav.Suggestions) > 0 {
+		// Limit display to 8 items, scrolled to keep the selection in view
+		const shown = 8
+		start := 0
+		if nav.Selection >= shown {
+			start = nav.Selection - shown + 1
+		}
+		end := min(start+shown, len(nav.Suggestions))
+
+		// Resource commands have longer names than the default ones
+		nameWidth := 25
+		for _, match := range nav.Suggestions[start:end] {
+			nameWidth = max(nameWidth, min(lipgloss.Width(match.Name)+1, boxWidth/2))
+		}
+
 		var lines []string
-		for i, match := range nav.Suggestions {
-			// Limit display to 8 items
-			if i >= 8 {
-				lines = append(lines, styles.SubtleStyle.Render(fmt.Sprintf("... and %d more", len(nav.Suggestions)-i)))
-				break
-			}
+		for i := start; i < end; i++ {
+			match := nav.Suggestions[i]
 
 			// Render Item with highlighted matches
 			name, desc := highlightMatches(match.Name, match.Description, match.MatchedIndexes)
 
 			// Layout: Name (padded) Description
 			// Use lipgloss width for proper padding with ANSI codes
-			nameWidth := 25
 			nameRendered := lipgloss.NewStyle().Width(nameWidth).Render(name)
 			content := nameRendered + " " + desc
 
@@ -160,6 +169,9 @@ func (m PaletteModel) Render(nav core.NavigationModel, screenWidth, screenHeight
 			}
 			lines = append(lines, content)
 		}
+		if end < len(nav.Suggestions) {
+			lines = append(lines, styles.SubtleStyle.Render(fmt.Sprintf("... and %d more", len(nav.Suggestions)-end)))
+		}
 		suggestionsView = lipgloss.JoinVertical(lipgloss.Left, lines...)
 
 		// Style the dropdown - no top border, same accent color as input
@@ -0,0 +1,323 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilterField declares one field of T that the "/" filter understands.
+// Services declare their fields once and build their FilterSession with
+// NewFieldFilterSession.
+type FilterField[T any] struct {
+	Name   string
+	Text   func(T) string            // Matched by free text, name:value, name=value and name:/regex/
+	Number func(T) float64           // Enables name>N, name>=N, name<N, name<=N and name=N
+	Labels func(T) map[string]string // Matched by name:key=value and name:key
+}
+
+// TextField declares a text field
+func TextField[T any](name string, get func(T) string) FilterField[T] {
+	return FilterField[T]{Name: name, Text: get}
+}
+
+// NumberField declares a numeric field, which also matches as text
+func NumberField[T any](name string, get func(T) float64) FilterField[T] {
+	return FilterField[T]{
+		Name:   name,
+		Number: get,
+		Text:   func(item T) string { return strconv.FormatFloat(get(item), 'f', -1, 64) },
+	}
+}
+
+// LabelsField declares a key/value field such as resource labels
+func LabelsField[T any](name string, get func(T) map[string]string) FilterField[T] {
+	return FilterField[T]{Name: name, Labels: get}
+}
+
+// FilterQuery is a parsed filter. It holds whitespace-separated terms that
+// must all match:
+//
+//	web                 free text, in any text field
+//	state:RUNNING       field contains the value
+//	zone:us-east1-*     glob, matched against the whole value
+//	name:/^web-\d+$/    regular expression
+//	name=web-01         exact value
+//	size>100            numeric comparison (>, >=, <, <=, =)
+//	label:env=prod      key/value field; label:env only checks the key
+//	-name:test          any term can be negated
+//
+// Values with spaces can be quoted: name:"my vm". Matching ignores case.
+type FilterQuery struct {
+	terms []queryTerm
+}
+
+type queryTerm struct {
+	negate bool
+	field  string // Empty for free text
+	op     string // ":", "=", ">", ">=", "<", "<="
+	raw    string // Value as typed
+	value  valueMatcher
+	num    float64
+	isNum  bool   // Value parses as a number
+	key    string // Key for key/value fields
+	pair   bool   // Value was "key=value" rather than just a key
+}
+
+// valueMatcher matches one value as a substring, exactly, or by pattern
+type valueMatcher struct {
+	text  string
+	exact bool
+	re    *regexp.Regexp
+}
+
+func (v valueMatcher) match(s string) bool {
+	switch {
+	case v.re != nil:
+		return v.re.MatchString(s)
+	case v.exact:
+		return strings.EqualFold(s, v.text)
+	default:
+		return strings.Contains(strings.ToLower(s), strings.ToLower(v.text))
+	}
+}
+
+// ParseFilterQuery parses a filter query. Field names are checked when the
+// query is evaluated, since they depend on the list.
+func ParseFilterQuery(query string) (FilterQuery, error) {
+	var q FilterQuery
+	for _, token := range splitQuery(query) {
+		t, err := parseTerm(token)
+		if err != nil {
+			return FilterQuery{}, err
+		}
+		q.terms = append(q.terms, t)
+	}
+	return q, nil
+}
+
+// Empty reports whether the query has no terms
+func (q FilterQuery) Empty() bool {
+	return len(q.terms) == 0
+}
+
+// splitQuery splits on whitespace outside double quotes and drops the quotes
+func splitQuery(query string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes, started := false, false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			started = true
+		case !inQuotes && (r == ' ' || r == '\t'):
+			if started {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+var queryOps = []string{">=", "<=", ":", "=", ">", "<"}
+
+func parseTerm(token string) (queryTerm, error) {
+	var t queryTerm
+	if len(token) > 1 && token[0] == '-' {
+		t.negate = true
+		token = token[1:]
+	}
+
+	// A field name is a letter followed by letters, digits, "_" or "."
+	end := 0
+	for end < len(token) && isFieldRune(token[end], end == 0) {
+		end++
+	}
+	value := token
+	if end > 0 {
+		for _, op := range queryOps {
+			if strings.HasPrefix(token[end:], op) {
+				t.field = strings.ToLower(token[:end])
+				t.op = op
+				value = token[end+len(op):]
+				break
+			}
+		}
+	}
+
+	t.raw = value
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		t.num, t.isNum = n, true
+	}
+	switch t.op {
+	case ">", ">=", "<", "<=":
+		if !t.isNum {
+			return t, fmt.Errorf("%s%s needs a number, got %q", t.field, t.op, value)
+		}
+		return t, nil
+	case ":":
+		// For key/value fields, "label:env=prod" matches the value of a key
+		// exactly and "label:env" only checks the key exists
+		t.key = value
+		if k, v, ok := strings.Cut(value, "="); ok && !strings.HasPrefix(value, "/") {
+			t.key, t.pair = k, true
+			m, err := newValueMatcher(v, true)
+			if err != nil {
+				return t, err
+			}
+			t.value = m
+			return t, nil
+		}
+	}
+
+	m, err := newValueMatcher(value, t.op == "=")
+	if err != nil {
+		return t, err
+	}
+	t.value = m
+	return t, nil
+}
+
+func isFieldRune(c byte, first bool) bool {
+	if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		return true
+	}
+	return !first && (c >= '0' && c <= '9' || c == '_' || c == '.')
+}
+
+// newValueMatcher compiles /regex/ and glob values; other values match as
+// substrings, or exactly when exact is set
+func newValueMatcher(value string, exact bool) (valueMatcher, error) {
+	if len(value) >= 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+		re, err := regexp.Compile("(?i)" + value[1:len(value)-1])
+		if err != nil {
+			return valueMatcher{}, fmt.Errorf("bad regex %s: %w", value, err)
+		}
+		return valueMatcher{text: value, re: re}, nil
+	}
+	if strings.ContainsAny(value, "*?") {
+		pattern := regexp.QuoteMeta(value)
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+		return valueMatcher{text: value, re: regexp.MustCompile("(?i)^" + pattern + "$")}, nil
+	}
+	return valueMatcher{text: value, exact: exact}, nil
+}
+
+// checkQuery reports terms that name unknown fields or compare text fields
+func checkQuery[T any](q FilterQuery, fields []FilterField[T]) error {
+	for _, t := range q.terms {
+		if t.field == "" {
+			continue
+		}
+		f, ok := findField(fields, t.field)
+		if !ok {
+			names := make([]string, len(fields))
+			for i, f := range fields {
+				names[i] = f.Name
+			}
+			return fmt.Errorf("unknown field %q (fields: %s)", t.field, strings.Join(names, ", "))
+		}
+		switch t.op {
+		case ">", ">=", "<", "<=":
+			if f.Number == nil {
+				return fmt.Errorf("%s is not numeric", f.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func findField[T any](fields []FilterField[T], name string) (FilterField[T], bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return FilterField[T]{}, false
+}
+
+// matchQuery reports whether the item satisfies every term
+func matchQuery[T any](q FilterQuery, fields []FilterField[T], item T) bool {
+	for _, t := range q.terms {
+		if matchTerm(t, fields, item) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func matchTerm[T any](t queryTerm, fields []FilterField[T], item T) bool {
+	if t.field == "" {
+		for _, f := range fields {
+			if f.Text != nil && t.value.match(f.Text(item)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	f, _ := findField(fields, t.field)
+	switch {
+	case f.Labels != nil:
+		labels := f.Labels(item)
+		for k, v := range labels {
+			if strings.EqualFold(k, t.key) && (!t.pair || t.value.match(v)) {
+				return true
+			}
+		}
+		return false
+	case f.Number != nil && t.isNum && t.op != ":":
+		n := f.Number(item)
+		switch t.op {
+		case ">":
+			return n > t.num
+		case ">=":
+			return n >= t.num
+		case "<":
+			return n < t.num
+		case "<=":
+			return n <= t.num
+		default:
+			return n == t.num
+		}
+	case f.Text != nil:
+		if t.pair {
+			// "name:a=b" on a text field means the literal value
+			return valueMatcher{text: t.raw}.match(f.Text(item))
+		}
+		return t.value.match(f.Text(item))
+	}
+	return false
+}
+
+// FilterByQuery returns the items matching a filter query. On a malformed
+// query it returns all items along with the error, so the list does not
+// flicker while a term is half-typed.
+func FilterByQuery[T any](items []T, query string, fields []FilterField[T]) ([]T, error) {
+	q, err := ParseFilterQuery(query)
+	if err == nil {
+		err = checkQuery(q, fields)
+	}
+	if err != nil || q.Empty() {
+		return items, err
+	}
+
+	var matches []T
+	for _, item := range items {
+		if matchQuery(q, fields, item) {
+			matches = append(matches, item)
+		}
+	}
+	return matches, nil
+}
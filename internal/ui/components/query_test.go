@@ -0,0 +1,99 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type queryItem struct {
+	Name   string
+	Zone   string
+	Size   int
+	Labels map[string]string
+}
+
+var queryFields = []FilterField[queryItem]{
+	TextField("name", func(i queryItem) string { return i.Name }),
+	TextField("zone", func(i queryItem) string { return i.Zone }),
+	NumberField("size", func(i queryItem) float64 { return float64(i.Size) }),
+	LabelsField("label", func(i queryItem) map[string]string { return i.Labels }),
+}
+
+var queryItems = []queryItem{
+	{Name: "web-01", Zone: "us-east1-b", Size: 50, Labels: map[string]string{"env": "prod"}},
+	{Name: "web-02", Zone: "us-west1-a", Size: 200, Labels: map[string]string{"env": "dev"}},
+	{Name: "test db", Zone: "europe-west1-c", Size: 100},
+}
+
+func TestFilterByQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"web-01", "web-02", "test db"}},
+		{"web", []string{"web-01", "web-02"}},
+		{"WEST1", []string{"web-02", "test db"}},
+		{"zone:us-*", []string{"web-01", "web-02"}},
+		{"zone:us-*-b", []string{"web-01"}},
+		{"name:/^web-\\d+$/", []string{"web-01", "web-02"}},
+		{"name:/^web-0[2-9]$/", []string{"web-02"}},
+		{"name=web", nil},
+		{"name=WEB-01", []string{"web-01"}},
+		{"size>100", []string{"web-02"}},
+		{"size>=100", []string{"web-02", "test db"}},
+		{"size<100", []string{"web-01"}},
+		{"size=200", []string{"web-02"}},
+		{"size:20", []string{"web-02"}},
+		{"label:env=prod", []string{"web-01"}},
+		{"label:env", []string{"web-01", "web-02"}},
+		{"label:env=*", []string{"web-01", "web-02"}},
+		{"-label:env", []string{"test db"}},
+		{"-name:web zone:europe*", []string{"test db"}},
+		{"web size>100", []string{"web-02"}},
+		{`name:"test db"`, []string{"test db"}},
+		{"prod", nil}, // Labels only match through their field
+	}
+	for _, tt := range tests {
+		got, err := FilterByQuery(queryItems, tt.query, queryFields)
+		if err != nil {
+			t.Errorf("FilterByQuery(%q): %v", tt.query, err)
+			continue
+		}
+		var names []string
+		for _, item := range got {
+			names = append(names, item.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("FilterByQuery(%q) = %v, want %v", tt.query, names, tt.want)
+		}
+	}
+}
+
+func TestFilterByQueryErrors(t *testing.T) {
+	tests := map[string]string{
+		"colour:red":    `unknown field "colour" (fields: name, zone, size, label)`,
+		"name>3":        "name is not numeric",
+		"size>big":      `size> needs a number, got "big"`,
+		"name:/web-(/":  "bad regex",
+		"web size>=abc": "needs a number",
+	}
+	for query, want := range tests {
+		got, err := FilterByQuery(queryItems, query, queryFields)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("FilterByQuery(%q) error = %v, want %q", query, err, want)
+		}
+		// A bad query leaves the list unfiltered
+		if len(got) != len(queryItems) {
+			t.Errorf("FilterByQuery(%q) returned %d items, want all %d", query, len(got), len(queryItems))
+		}
+	}
+}
+
+func TestSplitQuery(t *testing.T) {
+	got := splitQuery(`  name:"my vm"  -zone:us-*	"a b" `)
+	want := []string{"name:my vm", "-zone:us-*", "a b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitQuery = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/search"
+	"github.com/yogirk/tgcp/internal/styles"
+)
+
+// SearchModel is the overlay for finding resources across services
+type SearchModel struct {
+	Width  int
+	Height int
+
+	Input       textinput.Model
+	AllProjects bool // Search the configured projects or scope, not just the current project
+
+	query   string   // Query the results are for
+	scopes  []string // Scopes the results are for
+	results []search.Result
+	source  string
+	err     error
+	loading bool
+	cursor  int
+}
+
+// NewSearch creates a resource search overlay
+func NewSearch() SearchModel {
+	ti := textinput.New()
+	ti.Placeholder = "name, IP or label (env=prod)..."
+	ti.Prompt = "⌕ "
+	ti.CharLimit = 100
+	ti.Width = 50
+	return SearchModel{Input: ti}
+}
+
+// Open focuses the input, keeping the previous query and results
+func (m *SearchModel) Open() tea.Cmd {
+	m.Input.Focus()
+	return textinput.Blink
+}
+
+// Dirty reports whether the input differs from the query of the results
+func (m SearchModel) Dirty() bool {
+	return strings.TrimSpace(m.Input.Value()) != m.query
+}
+
+// Query returns the trimmed input
+func (m SearchModel) Query() string {
+	return strings.TrimSpace(m.Input.Value())
+}
+
+// Start shows the local results while the full search runs
+func (m *SearchModel) Start(query string, scopes []string, local []search.Result) {
+	m.query = query
+	m.scopes = scopes
+	m.results = local
+	m.source = search.SourceCache
+	m.err = nil
+	m.loading = true
+	m.cursor = 0
+}
+
+// SetResults shows the results of a search unless a newer one started
+func (m *SearchModel) SetResults(msg search.ResultsMsg) {
+	if msg.Query != m.query || !slices.Equal(msg.Scopes, m.scopes) {
+		return
+	}
+	m.results = msg.Results
+	m.source = msg.Source
+	m.err = msg.Err
+	m.loading = false
+	if m.cursor >= len(m.results) {
+		m.cursor = 0
+	}
+}
+
+// Selected returns the highlighted result
+func (m SearchModel) Selected() (search.Result, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.results) {
+		return search.Result{}, false
+	}
+	return m.results[m.cursor], true
+}
+
+// Update moves the selection and edits the query. The caller handles
+// enter, tab and closing.
+func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "ctrl+n":
+			if m.cursor < len(m.results)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+// View renders the overlay
+func (m SearchModel) View() string {
+	title := styles.TitleStyle.Copy().
+		Foreground(styles.ColorBrandAccent).
+		Bold(true).
+		Render("Search Resources")
+
+	muted := lipgloss.NewStyle().Foreground(styles.ColorTextMuted)
+	scope := "current project"
+	if m.AllProjects {
+		scope = "all configured projects"
+	}
+	subtitle := muted.Render(fmt.Sprintf("Scope: %s  •  Enter: search/open  Tab: toggle scope  ↑/↓: select  Esc: close", scope))
+
+	header := fmt.Sprintf("%-10s  %-40s  %-20s  %-16s  %s", "SERVICE", "NAME", "PROJECT", "LOCATION", "MATCH")
+
+	// Leave room for title, subtitle, input, header and the footer
+	visible := m.Height - 12
+	if visible < 3 {
+		visible = 3
+	}
+	start := 0
+	if m.cursor >= visible {
+		start = m.cursor - visible + 1
+	}
+
+	var rows strings.Builder
+	rows.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.ColorTextPrimary).Render(header))
+	if m.query != "" && len(m.results) == 0 && !m.loading {
+		rows.WriteString("\n" + muted.Render("No matching resources."))
+	}
+	for i := start; i < len(m.results) && i < start+visible; i++ {
+		row := formatSearchRow(m.results[i])
+		if m.Width > 4 && lipgloss.Width(row) > m.Width-4 {
+			row = row[:m.Width-5] + "…"
+		}
+		style := lipgloss.NewStyle().Foreground(styles.ColorTextPrimary)
+		if m.results[i].Service == "" {
+			style = style.Foreground(styles.ColorTextMuted)
+		}
+		if i == m.cursor {
+			style = style.Reverse(true)
+		}
+		rows.WriteString("\n" + style.Render(row))
+	}
+
+	var footer string
+	switch {
+	case m.loading:
+		footer = muted.Render(fmt.Sprintf("%d results from %s  •  searching Cloud Asset Inventory...", len(m.results), m.source))
+	case m.query != "":
+		footer = muted.Render(fmt.Sprintf("%d results from %s", len(m.results), m.source))
+		if m.err != nil {
+			footer += "\n" + lipgloss.NewStyle().Foreground(styles.ColorWarning).Render("Cloud Asset Inventory unavailable: "+m.err.Error())
+		}
+	}
+	if r, ok := m.Selected(); ok && r.Service == "" && r.Meta.SelfLink != "" {
+		footer += "\n" + muted.Render("No tgcp view for "+r.Meta.Kind+": "+r.Meta.SelfLink)
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", m.Input.View(), "", rows.String(), "", footer)
+	return lipgloss.NewStyle().Padding(1, 2).Render(content)
+}
+
+func formatSearchRow(r search.Result) string {
+	service := r.Service
+	if service == "" {
+		service = "-"
+	}
+	return fmt.Sprintf("%-10s  %-40s  %-20s  %-16s  %s",
+		service,
+		r.Meta.Name,
+		r.Meta.Project,
+		r.Meta.Location,
+		r.Match,
+	)
+}
@@ -3,7 +3,9 @@ package components
 import (
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/keys"
 	"github.com/yogirk/tgcp/internal/styles"
 )
 
@@ -84,12 +86,12 @@ func (m SidebarModel) Update(msg tea.Msg) (SidebarModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch {
+		case key.Matches(msg, keys.Up):
 			if m.Cursor > 0 {
 				m.Cursor--
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			if m.Cursor < len(m.Items)-1 {
 				m.Cursor++
 			}
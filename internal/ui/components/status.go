@@ -32,27 +32,18 @@ type statusConfig struct {
 	background lipgloss.Color
 }
 
-var statusConfigs = map[StatusCategory]statusConfig{
-	StatusRunning: {
-		icon:       IconRunning,
-		foreground: lipgloss.Color("0"),   // Black text for contrast
-		background: lipgloss.Color("42"),  // Green background
-	},
-	StatusStopped: {
-		icon:       IconStopped,
-		foreground: lipgloss.Color("0"),   // Black text for contrast
-		background: lipgloss.Color("196"), // Red background
-	},
-	StatusPending: {
-		icon:       IconPending,
-		foreground: lipgloss.Color("0"),   // Black text for contrast
-		background: lipgloss.Color("214"), // Yellow/Orange background
-	},
-	StatusUnknown: {
-		icon:       IconUnknown,
-		foreground: lipgloss.Color("252"), // Light text
-		background: lipgloss.Color("240"), // Grey background
-	},
+// statusConfigFor returns the styling of a status category in the
+// current theme
+func statusConfigFor(category StatusCategory) statusConfig {
+	switch category {
+	case StatusRunning:
+		return statusConfig{icon: IconRunning, foreground: styles.ColorBadgeText, background: styles.ColorSuccess}
+	case StatusStopped:
+		return statusConfig{icon: IconStopped, foreground: styles.ColorBadgeText, background: styles.ColorError}
+	case StatusPending:
+		return statusConfig{icon: IconPending, foreground: styles.ColorBadgeText, background: styles.ColorWarning}
+	}
+	return statusConfig{icon: IconUnknown, foreground: styles.ColorTextPrimary, background: styles.ColorBorderSubtle}
 }
 
 // runningStates maps state strings to StatusRunning
@@ -122,7 +113,7 @@ func CategorizeStatus(state string) StatusCategory {
 // Example output: " ✓ RUNNING " with green background
 func RenderStatus(state string) string {
 	category := CategorizeStatus(state)
-	config := statusConfigs[category]
+	config := statusConfigFor(category)
 
 	// Clean up the display text
 	displayText := strings.ToUpper(strings.TrimSpace(state))
@@ -143,7 +134,7 @@ func RenderStatus(state string) string {
 // Useful for tight spaces like table cells
 func RenderStatusMinimal(state string) string {
 	category := CategorizeStatus(state)
-	config := statusConfigs[category]
+	config := statusConfigFor(category)
 
 	displayText := strings.ToUpper(strings.TrimSpace(state))
 	displayText = shortenState(displayText)
@@ -1,11 +1,13 @@
 package components
 
 import (
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yogirk/tgcp/internal/styles"
+	"github.com/yogirk/tgcp/internal/utils"
 )
 
 type StatusMsg string
@@ -18,6 +20,14 @@ type StatusBarModel struct {
 	Width       int
 	LastUpdated time.Time
 	IsError     bool
+	StaleSince  time.Time // Non-zero while showing data loaded from the disk cache
+	ReadOnly    bool      // Mutating actions are disabled for the active project
+	Protected   bool      // Actions in the active project need typed confirmation
+	Profile     string    // Active profile, shown in its accent color
+
+	// Background refresh cadence of the service on screen, e.g. "every 30s"
+	Refresh      string
+	RefreshAlert bool // Watch mode or paused, shown as a badge
 }
 
 func NewStatusBar() StatusBarModel {
@@ -60,14 +70,14 @@ func (m StatusBarModel) View() string {
 
 	// Mode badge style
 	modeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("232")).
+		Foreground(styles.ColorBadgeText).
 		Background(styles.ColorBorderSubtle).
 		Bold(true).
 		Padding(0, 1)
 
 	modeLabel := m.Mode
 	if m.IsError {
-		modeStyle = modeStyle.Background(lipgloss.Color("196")) // Red
+		modeStyle = modeStyle.Background(styles.ColorError)
 		modeLabel = "ERROR"
 	} else if m.Mode == "COMMAND" {
 		modeStyle = modeStyle.Background(styles.ColorBrandPrimary)
@@ -90,19 +100,74 @@ func (m StatusBarModel) View() string {
 	// Layout: ┃ MODE ┃ Message ............... │ Help Hints
 	mode := modeStyle.Render(modeLabel)
 
-	// Right side: Help hints only (removed timestamp)
+	// Right side: data age and help hints
 	rightSide := ""
+	if !m.LastUpdated.IsZero() && m.StaleSince.IsZero() {
+		rightSide = sep + helpStyle.Render("updated "+utils.FormatAge(time.Since(m.LastUpdated)))
+	}
+	if m.Refresh != "" && !m.RefreshAlert {
+		rightSide += sep + helpStyle.Render(m.Refresh)
+	}
 	if m.HelpText != "" {
-		rightSide = sep + helpStyle.Render(m.HelpText)
+		rightSide += sep + helpStyle.Render(m.HelpText)
+	}
+
+	// Stale badge while cached data is shown and a refresh is in flight
+	stale := ""
+	if !m.StaleSince.IsZero() {
+		stale = lipgloss.NewStyle().
+			Foreground(styles.ColorBadgeText).
+			Background(styles.ColorWarning).
+			Padding(0, 1).
+			Render("STALE " + utils.FormatAge(time.Since(m.StaleSince)))
+		stale += " "
+	}
+
+	// Guardrail badge so a read-only or protected session is always visible
+	guard := ""
+	if m.ReadOnly || m.Protected {
+		label, bg := "PROTECTED", styles.ColorWarning
+		if m.ReadOnly {
+			label, bg = "READ-ONLY", styles.ColorError
+		}
+		guard = lipgloss.NewStyle().
+			Foreground(styles.ColorBadgeText).
+			Background(bg).
+			Bold(true).
+			Padding(0, 1).
+			Render(label) + " "
+	}
+
+	// Refresh badge while watching or paused, as the data is not refreshing
+	// at its usual cadence
+	refresh := ""
+	if m.Refresh != "" && m.RefreshAlert {
+		refresh = lipgloss.NewStyle().
+			Foreground(styles.ColorBadgeText).
+			Background(styles.ColorInfo).
+			Bold(true).
+			Padding(0, 1).
+			Render(m.Refresh) + " "
+	}
+
+	// Profile badge in the profile's accent, so prod never looks like staging
+	profile := ""
+	if m.Profile != "" {
+		profile = lipgloss.NewStyle().
+			Foreground(styles.ColorBadgeText).
+			Background(styles.ColorBrandAccent).
+			Bold(true).
+			Padding(0, 1).
+			Render(strings.ToUpper(m.Profile)) + " "
 	}
 
 	// Calculate available width for message
-	infoWidth := m.Width - lipgloss.Width(mode) - lipgloss.Width(rightSide) - 1
+	infoWidth := m.Width - lipgloss.Width(mode) - lipgloss.Width(profile) - lipgloss.Width(guard) - lipgloss.Width(refresh) - lipgloss.Width(stale) - lipgloss.Width(rightSide) - 1
 	if infoWidth < 0 {
 		infoWidth = 0
 	}
 
 	info := styles.StatusBarStyle.Width(infoWidth).Render(m.Message)
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, mode, " ", info, rightSide)
+	return lipgloss.JoinHorizontal(lipgloss.Top, mode, " ", profile, guard, refresh, stale, info, rightSide)
 }
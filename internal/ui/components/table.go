@@ -1,26 +1,52 @@
 package components
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yogirk/tgcp/internal/config"
+	"github.com/yogirk/tgcp/internal/keys"
 	"github.com/yogirk/tgcp/internal/styles"
 )
 
-// Standard colors for table selection
-const (
-	TableSelectedFocused = lipgloss.Color("236") // Dark grey background when focused
-	TableSelectedBlurred = lipgloss.Color("240") // Lighter grey background when blurred
-	TableTextFocused     = lipgloss.Color("39")  // Brand accent (light blue) when focused
-	TableTextBlurred     = lipgloss.Color("245") // Muted text when blurred
-	TableHeaderBg        = lipgloss.Color("237") // Subtle background for headers
-)
-
-// StandardTable is a standardized table component with built-in Focus/Blur and window size handling
+// StandardTable is a standardized table component with built-in Focus/Blur and window size handling.
+// Rows can be sorted by any column ("o" next column, "O" reverse, or a header
+// click) and columns hidden or reordered ("C"). Cursor and SetCursor always
+// use indexes into the rows passed to SetRows, so callers can ignore the sort.
+// Rows can be marked for bulk actions (space toggles, "*" marks all rows).
 type StandardTable struct {
 	table.Model
-	focused     bool
+	focused      bool
 	heightOffset int
+
+	name     string         // Key of the saved layout; layouts of unnamed tables are not saved
+	columns  []table.Column // All columns, in default order
+	rows     []table.Row    // Rows as set, before sorting
+	layout   config.TableLayout
+	visible  []int // Indexes into columns, in display order
+	order    []int // Index into rows for each displayed row
+	sortCol  int   // Index into columns, -1 when unsorted
+	sortDesc bool
+
+	marks   map[string]bool // Keys of the marked rows, kept across refreshes
+	keyCols []int           // Columns that identify a row
+}
+
+// EditColumnsMsg asks for the column editor to be opened for a table
+type EditColumnsMsg struct {
+	Table *StandardTable
+}
+
+// HeaderClickMsg reports a click on a rendered screen line, so that the
+// table whose header it is can sort by the clicked column
+type HeaderClickMsg struct {
+	Line string // Screen line, without ANSI codes
+	X    int    // Column clicked, in cells from the start of Line
 }
 
 // TableOption is a function that configures a StandardTable
@@ -40,6 +66,22 @@ func WithHeightOffset(offset int) TableOption {
 	}
 }
 
+// WithName names the table (e.g. "gce-instances") so that its column
+// layout and sort are saved in ~/.tgcprc and restored on the next start
+func WithName(name string) TableOption {
+	return func(t *StandardTable) {
+		t.name = name
+	}
+}
+
+// WithRowKey sets the columns that identify a row (by default the first),
+// so that marks stay on the same resources across refreshes and sorting
+func WithRowKey(cols ...int) TableOption {
+	return func(t *StandardTable) {
+		t.keyCols = cols
+	}
+}
+
 // WithFocused sets whether the table starts focused
 func WithFocused(focused bool) TableOption {
 	return func(t *StandardTable) {
@@ -60,11 +102,17 @@ func NewStandardTable(columns []table.Column, opts ...TableOption) *StandardTabl
 		table.WithFocused(true),
 		table.WithHeight(10),
 	)
+	t.KeyMap.LineUp = keys.Up
+	t.KeyMap.LineDown = keys.Down
 
 	st := &StandardTable{
-		Model:       t,
-		focused:     true,
+		Model:        t,
+		focused:      true,
 		heightOffset: 6, // Default offset
+		columns:      columns,
+		sortCol:      -1,
+		marks:        make(map[string]bool),
+		keyCols:      []int{0},
 	}
 
 	// Apply options
@@ -72,6 +120,12 @@ func NewStandardTable(columns []table.Column, opts ...TableOption) *StandardTabl
 		opt(st)
 	}
 
+	// Restore the saved layout
+	if st.name != "" {
+		st.layout, _ = loadTableLayout(st.name)
+	}
+	st.applyLayout()
+
 	// Apply initial styles
 	st.applyStyles()
 
@@ -85,23 +139,27 @@ func (st *StandardTable) applyStyles() {
 	// Header style: subtle background, bold, primary text
 	s.Header = lipgloss.NewStyle().
 		Foreground(styles.ColorTextPrimary).
-		Background(TableHeaderBg).
+		Background(styles.ColorHeaderBg).
 		Bold(true).
 		Padding(0, 1)
 
 	if st.focused {
-		// Focused: Dark grey background, accent text, bold
+		// Focused: theme selection colors, bold
 		s.Selected = lipgloss.NewStyle().
-			Foreground(TableTextFocused).
-			Background(TableSelectedFocused).
+			Foreground(styles.ColorSelectedFg).
+			Background(styles.ColorSelectedBg).
 			Bold(true)
 	} else {
-		// Blurred: Lighter grey background, muted text
+		// Blurred: muted selection colors
 		s.Selected = lipgloss.NewStyle().
-			Foreground(TableTextBlurred).
-			Background(TableSelectedBlurred).
+			Foreground(styles.ColorBlurredFg).
+			Background(styles.ColorBlurredBg).
 			Bold(false)
 	}
+	if styles.NoColor {
+		// Without colors, reverse video (underline when blurred) shows the cursor
+		s.Selected = s.Selected.Reverse(st.focused).Underline(!st.focused)
+	}
 
 	st.Model.SetStyles(s)
 }
@@ -134,9 +192,11 @@ func (st *StandardTable) HandleWindowSizeDefault(msg tea.WindowSizeMsg) {
 	st.HandleWindowSize(msg, st.heightOffset)
 }
 
-// SetRows sets the table rows
+// SetRows sets the table rows, which are shown in the current sort order
 func (st *StandardTable) SetRows(rows []table.Row) {
-	st.Model.SetRows(rows)
+	st.rows = rows
+	st.sortRows()
+	st.render()
 	if len(rows) == 0 {
 		return
 	}
@@ -146,18 +206,74 @@ func (st *StandardTable) SetRows(rows []table.Row) {
 	}
 }
 
+// SetName switches a table that shows several views in turn, e.g. topics
+// and subscriptions, to the named view's saved layout and sort. Call
+// SetColumns with the view's columns afterwards.
+func (st *StandardTable) SetName(name string) {
+	if name == st.name {
+		return
+	}
+	st.name = name
+	st.layout, _ = loadTableLayout(name)
+}
+
+// SetColumns replaces the columns, keeping the saved layout and sort
+func (st *StandardTable) SetColumns(columns []table.Column) {
+	st.columns = columns
+	st.applyLayout()
+}
+
+// Cursor returns the index of the selected row among the rows passed to
+// SetRows, whatever the sort order
+func (st *StandardTable) Cursor() int {
+	c := st.Model.Cursor()
+	if c >= 0 && c < len(st.order) {
+		return st.order[c]
+	}
+	return c
+}
+
 // SetHeight sets the table height
 func (st *StandardTable) SetHeight(height int) {
 	st.Model.SetHeight(height)
 }
 
-// SetCursor sets the cursor position
+// SetCursor selects a row by its index among the rows passed to SetRows
 func (st *StandardTable) SetCursor(index int) {
+	for pos, i := range st.order {
+		if i == index {
+			st.Model.SetCursor(pos)
+			return
+		}
+	}
 	st.Model.SetCursor(index)
 }
 
 // Update handles messages and returns commands
 func (st *StandardTable) Update(msg tea.Msg) (*StandardTable, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Sort):
+			return st, st.sortNext()
+		case key.Matches(msg, keys.ReverseSort):
+			return st, st.reverseSort()
+		case key.Matches(msg, keys.Columns):
+			return st, func() tea.Msg { return EditColumnsMsg{Table: st} }
+		case key.Matches(msg, keys.Mark):
+			st.toggleMark()
+			return st, nil
+		case key.Matches(msg, keys.MarkAll):
+			st.toggleAllMarks()
+			return st, nil
+		}
+	case HeaderClickMsg:
+		if col, ok := st.headerColumn(msg); ok {
+			return st, st.SortBy(col)
+		}
+		return st, nil
+	}
+
 	var cmd tea.Cmd
 	st.Model, cmd = st.Model.Update(msg)
 	return st, cmd
@@ -168,6 +284,335 @@ func (st *StandardTable) View() string {
 	return st.Model.View()
 }
 
+// Name returns the name the table layout is saved under
+func (st *StandardTable) Name() string {
+	return st.name
+}
+
+// SortBy sorts by a column (an index into the columns the table was created
+// with), or reverses the order if it is already sorted by that column
+func (st *StandardTable) SortBy(col int) tea.Cmd {
+	if col < 0 || col >= len(st.columns) {
+		return nil
+	}
+	if col == st.sortCol {
+		st.sortDesc = !st.sortDesc
+	} else {
+		st.sortCol, st.sortDesc = col, false
+	}
+	return st.resort()
+}
+
+// sortNext sorts by the next shown column, ending with the API order
+func (st *StandardTable) sortNext() tea.Cmd {
+	next := -1
+	if st.sortCol < 0 && len(st.visible) > 0 {
+		next = st.visible[0]
+	}
+	for pos, i := range st.visible {
+		if i == st.sortCol && pos+1 < len(st.visible) {
+			next = st.visible[pos+1]
+		}
+	}
+	st.sortCol, st.sortDesc = next, false
+	return st.resort()
+}
+
+func (st *StandardTable) reverseSort() tea.Cmd {
+	if st.sortCol < 0 {
+		return st.sortNext()
+	}
+	st.sortDesc = !st.sortDesc
+	return st.resort()
+}
+
+// resort re-sorts the rows keeping the same row selected, and saves the sort
+func (st *StandardTable) resort() tea.Cmd {
+	selected := st.Cursor()
+	st.layout.SortBy, st.layout.SortDesc = "", false
+	if st.sortCol >= 0 {
+		st.layout.SortBy, st.layout.SortDesc = st.columns[st.sortCol].Title, st.sortDesc
+	}
+	st.SetRows(st.rows)
+	st.SetCursor(selected)
+	return st.SaveLayout()
+}
+
+// sortRows computes the display order of the rows
+func (st *StandardTable) sortRows() {
+	st.order = make([]int, len(st.rows))
+	for i := range st.order {
+		st.order[i] = i
+	}
+	if st.sortCol < 0 {
+		return
+	}
+	keys := make([]cellKey, len(st.rows))
+	for i, r := range st.rows {
+		if st.sortCol < len(r) {
+			keys[i] = parseCell(r[st.sortCol])
+		}
+	}
+	sort.SliceStable(st.order, func(a, b int) bool {
+		c := compareCells(keys[st.order[a]], keys[st.order[b]])
+		if st.sortDesc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// applyLayout works out the shown columns and sort from the layout
+func (st *StandardTable) applyLayout() {
+	index := make(map[string]int, len(st.columns))
+	for i, c := range st.columns {
+		index[strings.ToLower(c.Title)] = i
+	}
+	hidden := make(map[int]bool)
+	for _, title := range st.layout.Hidden {
+		if i, ok := index[strings.ToLower(title)]; ok {
+			hidden[i] = true
+		}
+	}
+
+	st.visible = st.visible[:0]
+	placed := make(map[int]bool)
+	for _, title := range st.layout.Order {
+		if i, ok := index[strings.ToLower(title)]; ok && !placed[i] {
+			placed[i] = true
+			if !hidden[i] {
+				st.visible = append(st.visible, i)
+			}
+		}
+	}
+	for i := range st.columns {
+		if !placed[i] && !hidden[i] {
+			st.visible = append(st.visible, i)
+		}
+	}
+	if len(st.visible) == 0 {
+		for i := range st.columns {
+			st.visible = append(st.visible, i)
+		}
+	}
+
+	st.sortCol, st.sortDesc = -1, false
+	if i, ok := index[strings.ToLower(st.layout.SortBy)]; ok && st.layout.SortBy != "" {
+		st.sortCol, st.sortDesc = i, st.layout.SortDesc
+	}
+
+	st.SetRows(st.rows)
+}
+
+// render shows the rows in display order, with the mark column while any
+// shown row is marked
+func (st *StandardTable) render() {
+	cols := st.displayColumns()
+	rows := st.displayRows()
+	if st.showMarks() {
+		cols = append([]table.Column{{Title: "", Width: 1}}, cols...)
+		for pos, i := range st.order {
+			mark := " "
+			if st.marks[st.rowKey(i)] {
+				mark = "●"
+			}
+			rows[pos] = append(table.Row{mark}, rows[pos]...)
+		}
+	}
+	// Rows must never have more cells than there are columns
+	st.Model.SetRows(nil)
+	st.Model.SetColumns(cols)
+	st.Model.SetRows(rows)
+}
+
+// displayColumns returns the shown columns, marking the sorted one
+func (st *StandardTable) displayColumns() []table.Column {
+	cols := make([]table.Column, len(st.visible))
+	for pos, i := range st.visible {
+		cols[pos] = st.columns[i]
+		if i == st.sortCol {
+			arrow := " ▲"
+			if st.sortDesc {
+				arrow = " ▼"
+			}
+			cols[pos].Title += arrow
+		}
+	}
+	return cols
+}
+
+// displayRows returns the rows in display order with the shown cells
+func (st *StandardTable) displayRows() []table.Row {
+	rows := make([]table.Row, len(st.order))
+	for pos, i := range st.order {
+		row := make(table.Row, len(st.visible))
+		for c, col := range st.visible {
+			if col < len(st.rows[i]) {
+				row[c] = st.rows[i][col]
+			}
+		}
+		rows[pos] = row
+	}
+	return rows
+}
+
+// headerColumn maps a click on the rendered header to a column index
+func (st *StandardTable) headerColumn(msg HeaderClickMsg) (int, bool) {
+	header, _, _ := strings.Cut(ansi.Strip(st.Model.View()), "\n")
+	header = strings.TrimRight(header, " ")
+	start := strings.Index(msg.Line, header)
+	if header == "" || start < 0 {
+		return 0, false
+	}
+
+	x := msg.X - ansi.StringWidth(msg.Line[:start])
+	if st.showMarks() {
+		x -= 3 // Mark column
+	}
+	for _, i := range st.visible {
+		w := st.columns[i].Width
+		if w <= 0 {
+			continue
+		}
+		w += 2 // Header cells are padded by one on each side
+		if x >= 0 && x < w {
+			return i, true
+		}
+		x -= w
+	}
+	return 0, false
+}
+
+// ColumnLayout lists every column title in display order, with whether it is shown
+func (st *StandardTable) ColumnLayout() (titles []string, shown []bool) {
+	isShown := make(map[int]bool, len(st.visible))
+	for _, i := range st.visible {
+		titles = append(titles, st.columns[i].Title)
+		shown = append(shown, true)
+		isShown[i] = true
+	}
+	// Hidden columns go where the saved order puts them, else at the end
+	for _, title := range st.layout.Order {
+		for i, c := range st.columns {
+			if !isShown[i] && strings.EqualFold(c.Title, title) {
+				titles = append(titles, c.Title)
+				shown = append(shown, false)
+				isShown[i] = true
+			}
+		}
+	}
+	for i, c := range st.columns {
+		if !isShown[i] {
+			titles = append(titles, c.Title)
+			shown = append(shown, false)
+		}
+	}
+	return titles, shown
+}
+
+// SetColumnLayout shows the columns marked shown, in the given order. It
+// does not save the layout; call SaveLayout once the user is done.
+func (st *StandardTable) SetColumnLayout(titles []string, shown []bool) {
+	selected := st.Cursor()
+	st.layout.Order = append([]string(nil), titles...)
+	st.layout.Hidden = nil
+	for i, title := range titles {
+		if i < len(shown) && !shown[i] {
+			st.layout.Hidden = append(st.layout.Hidden, title)
+		}
+	}
+	st.applyLayout()
+	st.SetCursor(selected)
+}
+
+// SaveLayout returns a command that saves the column layout and sort to
+// ~/.tgcprc, or nil for unnamed tables
+func (st *StandardTable) SaveLayout() tea.Cmd {
+	if st.name == "" {
+		return nil
+	}
+	return saveTableLayout(st.name, st.layout)
+}
+
+// rowKey identifies a row by its key columns
+func (st *StandardTable) rowKey(i int) string {
+	parts := make([]string, len(st.keyCols))
+	for k, c := range st.keyCols {
+		if c < len(st.rows[i]) {
+			parts[k] = ansi.Strip(st.rows[i][c])
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func (st *StandardTable) showMarks() bool {
+	if len(st.marks) == 0 {
+		return false
+	}
+	for i := range st.rows {
+		if st.marks[st.rowKey(i)] {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleMark marks or unmarks the selected row and moves to the next one
+func (st *StandardTable) toggleMark() {
+	i := st.Cursor()
+	if i < 0 || i >= len(st.rows) {
+		return
+	}
+	key := st.rowKey(i)
+	if st.marks[key] {
+		delete(st.marks, key)
+	} else {
+		st.marks[key] = true
+	}
+	st.render()
+	st.Model.MoveDown(1)
+}
+
+// toggleAllMarks marks every row, or unmarks them all if they already are
+func (st *StandardTable) toggleAllMarks() {
+	all := len(st.rows) > 0 && len(st.Marked()) == len(st.rows)
+	for i := range st.rows {
+		if all {
+			delete(st.marks, st.rowKey(i))
+		} else {
+			st.marks[st.rowKey(i)] = true
+		}
+	}
+	st.render()
+}
+
+// Marked returns the indexes of the marked rows among the rows passed to
+// SetRows. Marked rows hidden by a filter are not included.
+func (st *StandardTable) Marked() []int {
+	var marked []int
+	for i := range st.rows {
+		if st.marks[st.rowKey(i)] {
+			marked = append(marked, i)
+		}
+	}
+	return marked
+}
+
+// ClearMarks unmarks every row
+func (st *StandardTable) ClearMarks() {
+	st.marks = make(map[string]bool)
+	st.render()
+}
+
+// exportColumns returns the shown columns without the sort marker
+func (st *StandardTable) exportColumns() []table.Column {
+	cols := make([]table.Column, len(st.visible))
+	for pos, i := range st.visible {
+		cols[pos] = st.columns[i]
+	}
+	return cols
+}
+
 // -----------------------------------------------------------------------------
 // Legacy TableModel (kept for backward compatibility)
 // -----------------------------------------------------------------------------
@@ -187,13 +632,14 @@ func NewTable(columns []table.Column, rows []table.Row) TableModel {
 	s := table.DefaultStyles()
 	s.Header = lipgloss.NewStyle().
 		Foreground(styles.ColorTextPrimary).
-		Background(TableHeaderBg).
+		Background(styles.ColorHeaderBg).
 		Bold(true).
 		Padding(0, 1)
 	s.Selected = s.Selected.
-		Foreground(TableTextFocused).
-		Background(TableSelectedFocused).
-		Bold(true)
+		Foreground(styles.ColorSelectedFg).
+		Background(styles.ColorSelectedBg).
+		Bold(true).
+		Reverse(styles.NoColor)
 	t.SetStyles(s)
 
 	return TableModel{Table: t}
@@ -208,7 +654,7 @@ func (m *TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 func (m TableModel) View() string {
 	baseStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240"))
+		BorderForeground(styles.ColorBorderSubtle)
 
 	return baseStyle.Render(m.Table.View())
 }
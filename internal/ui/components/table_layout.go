@@ -0,0 +1,81 @@
+package components
+
+import (
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yogirk/tgcp/internal/config"
+	"github.com/yogirk/tgcp/internal/core"
+)
+
+var (
+	layoutsMu    sync.Mutex
+	tableLayouts = make(map[string]config.TableLayout)
+)
+
+// SetTableLayouts installs the saved table layouts from ~/.tgcprc. Named
+// tables created afterwards start with their saved layout.
+func SetTableLayouts(layouts map[string]config.TableLayout) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+
+	tableLayouts = make(map[string]config.TableLayout, len(layouts))
+	for name, l := range layouts {
+		tableLayouts[name] = l
+	}
+}
+
+func loadTableLayout(name string) (config.TableLayout, bool) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	l, ok := tableLayouts[name]
+	return l, ok
+}
+
+// layoutSaveDelay batches the layout changes of quick repeated keypresses,
+// e.g. cycling the sort column, into one write of ~/.tgcprc
+const layoutSaveDelay = 500 * time.Millisecond
+
+var (
+	layoutsGen uint64     // Bumped by every change, guarded by layoutsMu
+	saveMu     sync.Mutex // Serializes writes of ~/.tgcprc
+)
+
+// saveTableLayout records a table's layout and returns a command that
+// writes all layouts to ~/.tgcprc once no further change has come in for
+// layoutSaveDelay
+func saveTableLayout(name string, l config.TableLayout) tea.Cmd {
+	layoutsMu.Lock()
+	tableLayouts[name] = l
+	layoutsGen++
+	gen := layoutsGen
+	layoutsMu.Unlock()
+
+	return tea.Tick(layoutSaveDelay, func(time.Time) tea.Msg {
+		if err := flushTableLayouts(gen); err != nil {
+			return core.ToastMsg{Message: "Could not save table layout: " + err.Error(), Type: core.ToastError}
+		}
+		return nil
+	})
+}
+
+// flushTableLayouts writes the layouts unless they changed after gen, in
+// which case the save scheduled by that change writes them
+func flushTableLayouts(gen uint64) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
+	layoutsMu.Lock()
+	if gen != layoutsGen {
+		layoutsMu.Unlock()
+		return nil
+	}
+	snapshot := make(map[string]config.TableLayout, len(tableLayouts))
+	for k, v := range tableLayouts {
+		snapshot[k] = v
+	}
+	layoutsMu.Unlock()
+
+	return config.SaveTables(snapshot)
+}
@@ -0,0 +1,120 @@
+package components
+
+import (
+	"bytes"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Kinds of cell values, in the order mixed columns sort them
+const (
+	cellEmpty = iota
+	cellNumber
+	cellIP
+	cellText
+)
+
+// cellKey is a cell parsed for sorting. Sizes, durations, times and
+// relative ages become numbers so that "9 GB" sorts before "10 GB".
+type cellKey struct {
+	kind int
+	num  float64
+	ip   net.IP
+	text string
+}
+
+var (
+	sizePattern = regexp.MustCompile(`^(?i)([0-9]+(?:\.[0-9]+)?)\s*(B|KB|MB|GB|TB|PB|KiB|MiB|GiB|TiB|PiB)$`)
+	agePattern  = regexp.MustCompile(`^(?i)([0-9]+)\s*(second|minute|hour|day|week|month|year)s?\s+ago$`)
+)
+
+var sizeUnits = map[string]float64{
+	"B": 1, "KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12, "PB": 1e15,
+	"KIB": 1 << 10, "MIB": 1 << 20, "GIB": 1 << 30, "TIB": 1 << 40, "PIB": 1 << 50,
+}
+
+var ageUnits = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+	"month":  30 * 24 * time.Hour,
+	"year":   365 * 24 * time.Hour,
+}
+
+// timeLayouts are the time formats the services render in tables
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01-02 15:04:05",
+	"Jan 02 15:04",
+	"Jan 2, 2006",
+}
+
+// parseCell classifies a rendered cell for sorting
+func parseCell(cell string) cellKey {
+	s := strings.TrimSpace(ansi.Strip(cell))
+	switch s {
+	case "", "-", "N/A", "n/a", "<none>":
+		return cellKey{kind: cellEmpty}
+	}
+
+	if ip := net.ParseIP(s); ip != nil {
+		return cellKey{kind: cellIP, ip: ip.To16()}
+	}
+	if n, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64); err == nil {
+		return cellKey{kind: cellNumber, num: n}
+	}
+	if m := sizePattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		return cellKey{kind: cellNumber, num: n * sizeUnits[strings.ToUpper(m[2])]}
+	}
+	if m := agePattern.FindStringSubmatch(s); m != nil {
+		// Older first, like the timestamps they stand for
+		n, _ := strconv.Atoi(m[1])
+		return cellKey{kind: cellNumber, num: -float64(time.Duration(n) * ageUnits[strings.ToLower(m[2])])}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return cellKey{kind: cellNumber, num: float64(d)}
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return cellKey{kind: cellNumber, num: float64(t.UnixNano())}
+		}
+	}
+	return cellKey{kind: cellText, text: strings.ToLower(s)}
+}
+
+// compareCells orders two parsed cells, returning -1, 0 or 1
+func compareCells(a, b cellKey) int {
+	if a.kind != b.kind {
+		if a.kind < b.kind {
+			return -1
+		}
+		return 1
+	}
+	switch a.kind {
+	case cellNumber:
+		switch {
+		case a.num < b.num:
+			return -1
+		case a.num > b.num:
+			return 1
+		}
+		return 0
+	case cellIP:
+		return bytes.Compare(a.ip, b.ip)
+	case cellText:
+		return strings.Compare(a.text, b.text)
+	}
+	return 0
+}
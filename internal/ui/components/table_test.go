@@ -0,0 +1,266 @@
+package components
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yogirk/tgcp/internal/config"
+)
+
+func testSortTable() *StandardTable {
+	t := NewStandardTable([]table.Column{
+		{Title: "Name", Width: 8},
+		{Title: "Size", Width: 8},
+		{Title: "IP", Width: 10},
+	})
+	t.SetRows([]table.Row{
+		{"web", "10 GB", "10.0.0.10"},
+		{"db", "9 GB", "10.0.0.9"},
+		{"cache", "1 TB", "-"},
+	})
+	return t
+}
+
+func firstCells(t *StandardTable) []string {
+	var cells []string
+	for _, r := range t.Rows() {
+		cells = append(cells, r[0])
+	}
+	return cells
+}
+
+func TestStandardTableSort(t *testing.T) {
+	tbl := testSortTable()
+
+	tbl.SortBy(1)
+	if got := firstCells(tbl); !reflect.DeepEqual(got, []string{"db", "web", "cache"}) {
+		t.Errorf("by size = %v", got)
+	}
+	tbl.SortBy(1)
+	if got := firstCells(tbl); !reflect.DeepEqual(got, []string{"cache", "web", "db"}) {
+		t.Errorf("by size descending = %v", got)
+	}
+	if title := tbl.Columns()[1].Title; title != "Size ▼" {
+		t.Errorf("sorted header = %q", title)
+	}
+
+	// Empty cells sort first ascending; IPs compare numerically
+	tbl.SortBy(2)
+	if got := firstCells(tbl); !reflect.DeepEqual(got, []string{"cache", "db", "web"}) {
+		t.Errorf("by ip = %v", got)
+	}
+
+	// Cursor and SetCursor use the indexes of the rows as set
+	tbl.SetCursor(1) // db
+	if tbl.Model.Cursor() != 1 || tbl.Cursor() != 1 {
+		t.Errorf("cursor = %d (display %d)", tbl.Cursor(), tbl.Model.Cursor())
+	}
+	tbl.SortBy(0)
+	if tbl.Cursor() != 1 || tbl.SelectedRow()[0] != "db" {
+		t.Errorf("selection lost on sort: %d %v", tbl.Cursor(), tbl.SelectedRow())
+	}
+}
+
+func TestStandardTableSortKeys(t *testing.T) {
+	tbl := testSortTable()
+	press := func(key string) {
+		tbl.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	press("o")
+	if tbl.sortCol != 0 || tbl.sortDesc {
+		t.Errorf("after o: col %d desc %v", tbl.sortCol, tbl.sortDesc)
+	}
+	press("O")
+	if tbl.sortCol != 0 || !tbl.sortDesc {
+		t.Errorf("after O: col %d desc %v", tbl.sortCol, tbl.sortDesc)
+	}
+	press("o")
+	press("o")
+	press("o")
+	if tbl.sortCol != -1 || !reflect.DeepEqual(firstCells(tbl), []string{"web", "db", "cache"}) {
+		t.Errorf("cycling past the last column should restore API order, got col %d %v", tbl.sortCol, firstCells(tbl))
+	}
+
+	_, cmd := tbl.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if msg, ok := cmd().(EditColumnsMsg); !ok || msg.Table != tbl {
+		t.Errorf("C = %v", msg)
+	}
+}
+
+func TestStandardTableHeaderClick(t *testing.T) {
+	tbl := testSortTable()
+	header, _, _ := strings.Cut(ansi.Strip(tbl.View()), "\n")
+	line := "│ " + header
+
+	// Columns are 8+2, 8+2 and 10+2 cells wide
+	tbl.Update(HeaderClickMsg{Line: line, X: 2 + 12})
+	if tbl.sortCol != 1 {
+		t.Errorf("click on Size sorted by %d", tbl.sortCol)
+	}
+	tbl.Update(HeaderClickMsg{Line: "│ some other line", X: 3})
+	if tbl.sortCol != 1 || tbl.sortDesc {
+		t.Errorf("click elsewhere changed the sort")
+	}
+}
+
+func TestStandardTableLayout(t *testing.T) {
+	SetTableLayouts(map[string]config.TableLayout{
+		"test": {Order: []string{"ip", "Name"}, Hidden: []string{"Size"}, SortBy: "Name", SortDesc: true},
+	})
+	defer SetTableLayouts(nil)
+
+	tbl := NewStandardTable([]table.Column{{Title: "Name", Width: 8}, {Title: "Size", Width: 8}, {Title: "IP", Width: 10}}, WithName("test"))
+	tbl.SetRows([]table.Row{{"a", "1 GB", "10.0.0.1"}, {"b", "2 GB", "10.0.0.2"}})
+
+	var titles []string
+	for _, c := range tbl.Columns() {
+		titles = append(titles, c.Title)
+	}
+	if !reflect.DeepEqual(titles, []string{"IP", "Name ▼"}) {
+		t.Errorf("columns = %v", titles)
+	}
+	if !reflect.DeepEqual(tbl.Rows(), []table.Row{{"10.0.0.2", "b"}, {"10.0.0.1", "a"}}) {
+		t.Errorf("rows = %v", tbl.Rows())
+	}
+
+	all, shown := tbl.ColumnLayout()
+	if !reflect.DeepEqual(all, []string{"IP", "Name", "Size"}) || !reflect.DeepEqual(shown, []bool{true, true, false}) {
+		t.Errorf("ColumnLayout = %v %v", all, shown)
+	}
+
+	tbl.SetColumnLayout([]string{"Size", "Name", "IP"}, []bool{true, true, false})
+	if !reflect.DeepEqual(tbl.Rows(), []table.Row{{"2 GB", "b"}, {"1 GB", "a"}}) {
+		t.Errorf("rows after SetColumnLayout = %v", tbl.Rows())
+	}
+
+	// Exports follow the layout and sort, without the sort marker
+	e := NewTableExport("test", tbl, []string{"a", "b"})
+	if !reflect.DeepEqual(e.Columns, []string{"Size", "Name"}) || !reflect.DeepEqual(e.Items, []string{"b", "a"}) {
+		t.Errorf("export = %v %v", e.Columns, e.Items)
+	}
+}
+
+func TestStandardTableSetName(t *testing.T) {
+	SetTableLayouts(map[string]config.TableLayout{
+		"topics": {SortBy: "Name"},
+		"subs":   {SortBy: "Name", SortDesc: true},
+	})
+	defer SetTableLayouts(nil)
+
+	tbl := NewStandardTable([]table.Column{{Title: "Name", Width: 8}}, WithName("topics"))
+	tbl.SetRows([]table.Row{{"a"}, {"b"}})
+	if got := tbl.Rows()[0][0]; got != "a" {
+		t.Errorf("topics first row = %q", got)
+	}
+
+	tbl.SetName("subs")
+	tbl.SetColumns([]table.Column{{Title: "Name", Width: 8}})
+	if got := tbl.Rows()[0][0]; got != "b" {
+		t.Errorf("subs first row = %q", got)
+	}
+}
+
+func TestFlushTableLayouts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	defer SetTableLayouts(nil)
+
+	saveTableLayout("a", config.TableLayout{SortBy: "Name"})
+	stale := layoutsGen
+	saveTableLayout("b", config.TableLayout{SortBy: "Size"})
+
+	// A save superseded by a later change writes nothing
+	if err := flushTableLayouts(stale); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := config.Path()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("superseded save wrote %s: %v", path, err)
+	}
+
+	if err := flushTableLayouts(layoutsGen); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "sort_by: Size") {
+		t.Errorf("saved config = %s, %v", data, err)
+	}
+}
+
+func TestParseCell(t *testing.T) {
+	// Each list is in ascending order
+	tests := [][]string{
+		{"512 MB", "9 GB", "10 GB", "1.5 TB"},
+		{"10.0.0.9", "10.0.0.10", "192.168.1.1"},
+		{"2024-12-31", "2025-01-02 08:00", "2025-01-02 09:30:00"},
+		{"3 days ago", "5 hours ago", "1 minute ago"},
+		{"2", "10", "1,000"},
+		{"", "alpha", "Beta", "gamma"},
+	}
+	for _, want := range tests {
+		// Reverse the input first so that a no-op sort fails
+		rev := make([]string, len(want))
+		for i, s := range want {
+			rev[len(want)-1-i] = s
+		}
+		sort.SliceStable(rev, func(i, j int) bool {
+			return compareCells(parseCell(rev[i]), parseCell(rev[j])) < 0
+		})
+		if !reflect.DeepEqual(rev, want) {
+			t.Errorf("sorted %v, want %v", rev, want)
+		}
+	}
+}
+
+func TestStandardTableMarks(t *testing.T) {
+	tbl := testSortTable()
+	press := func(key string) {
+		tbl.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	// Space marks the selected row and moves down
+	press(" ")
+	press(" ")
+	if got := tbl.Marked(); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("Marked() = %v", got)
+	}
+	if tbl.Cursor() != 2 {
+		t.Errorf("cursor = %d after marking two rows", tbl.Cursor())
+	}
+	if !strings.Contains(tbl.View(), "●") {
+		t.Error("marked rows are not shown")
+	}
+
+	// Marks follow the row through sorting and refreshes
+	tbl.SortBy(0)
+	tbl.SetRows([]table.Row{
+		{"new", "1 GB", "10.0.0.1"},
+		{"db", "9 GB", "10.0.0.9"},
+		{"web", "10 GB", "10.0.0.10"},
+	})
+	if got := tbl.Marked(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Marked() after refresh = %v", got)
+	}
+
+	// The selection exports in display order
+	e := NewTableExport("test", tbl, []string{"new", "db", "web"})
+	if !e.Selection || !reflect.DeepEqual(e.Items, []string{"db", "web"}) || len(e.Rows) != 2 {
+		t.Errorf("export of marked rows = %v %v", e.Items, e.Rows)
+	}
+
+	press("*")
+	if len(tbl.Marked()) != 3 {
+		t.Errorf("* marked %v", tbl.Marked())
+	}
+	press("*")
+	if len(tbl.Marked()) != 0 || strings.Contains(tbl.View(), "●") {
+		t.Errorf("second * left %v marked", tbl.Marked())
+	}
+}
@@ -77,7 +77,7 @@ func (t *ToastModel) View() string {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(borderColor).
 		Padding(0, 2).
-		Background(lipgloss.Color("235"))
+		Background(styles.ColorPanelBg)
 
 	// Icon style
 	iconStyle := lipgloss.NewStyle().
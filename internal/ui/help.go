@@ -4,58 +4,23 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/keys"
 	"github.com/yogirk/tgcp/internal/styles"
 )
 
 // HelpView renders the help screen overlay
 func HelpView(width, height int) string {
-	// Define keybindings data
-	sections := []struct {
-		title string
-		items [][]string
-	}{
-		{
-			"Global",
-			[][]string{
-				{":", "Command Palette"},
-				{"?", "Toggle Help"},
-				{"Tab", "Toggle Sidebar"},
-				{"Ctrl+c", "Force Quit"},
-			},
-		},
-		{
-			"Navigation",
-			[][]string{
-				{"↑/↓  j/k", "Navigate List"},
-				{"Enter", "Select / Details"},
-				{"/", "Filter Items"},
-				{"Esc", "Go Back"},
-			},
-		},
-		{
-			"Actions",
-			[][]string{
-				{"r", "Refresh Data"},
-				{"s", "Start Resource"},
-				{"x", "Stop Resource"},
-				{"h", "SSH Connect"},
-				{"l", "Log Tailing"},
-			},
-		},
-	}
+	// Keybindings come from the registry, so rebound keys show up here
+	sections := keys.HelpSections()
 
 	// Calculate column widths adaptively
 	var columns []string
 	for _, section := range sections {
 		// Find max widths for this section
-		maxKeyLen := len(section.title)
-		maxDescLen := 0
-		for _, item := range section.items {
-			if len(item[0]) > maxKeyLen {
-				maxKeyLen = len(item[0])
-			}
-			if len(item[1]) > maxDescLen {
-				maxDescLen = len(item[1])
+		maxKeyLen := len(section.Title)
+		for _, item := range section.Items {
+			if w := lipgloss.Width(item.Keys); w > maxKeyLen {
+				maxKeyLen = w
 			}
 		}
 
@@ -70,29 +35,32 @@ func HelpView(width, height int) string {
 			Foreground(styles.ColorBrandAccent).
 			Bold(true).
 			Underline(true).
-			Render(section.title)
+			Render(section.Title)
 		col.WriteString(header + "\n\n")
 
 		// Items
-		for _, item := range section.items {
+		for _, item := range section.Items {
 			key := lipgloss.NewStyle().
 				Foreground(styles.ColorBrandPrimary).
 				Bold(true).
 				Width(keyWidth).
-				Render(item[0])
+				Render(item.Keys)
 
 			desc := lipgloss.NewStyle().
 				Foreground(styles.ColorTextPrimary).
-				Render(item[1])
+				Render(item.Desc)
 
 			col.WriteString(key + desc + "\n")
 		}
 
+		if len(columns) > 0 {
+			columns = append(columns, "   ")
+		}
 		columns = append(columns, col.String())
 	}
 
 	// Join columns with spacing
-	content := lipgloss.JoinHorizontal(lipgloss.Top, columns[0], "   ", columns[1], "   ", columns[2])
+	content := lipgloss.JoinHorizontal(lipgloss.Top, columns...)
 
 	// Calculate dialog width based on content
 	contentWidth := lipgloss.Width(content)
@@ -111,7 +79,7 @@ func HelpView(width, height int) string {
 	// Get Banner
 	banner := GetBanner()
 
-	footer := styles.SubtleStyle.Render("Press ? or Esc to close")
+	footer := styles.SubtleStyle.Render("Press " + keys.Help.Help().Key + " or Esc to close")
 
 	dialog := styles.PrimaryBoxStyle.Copy().
 		Width(dialogWidth).
@@ -2,14 +2,32 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yogirk/tgcp/internal/core"
 	"github.com/yogirk/tgcp/internal/styles"
+	"github.com/yogirk/tgcp/internal/ui/components"
 )
 
 // View renders the application UI
 func (m MainModel) View() string {
-	// 1. Check for Help Overlay
+	// 1. Check for Inspector / Help Overlay
+	if m.ShowInspector {
+		return m.Inspector.View()
+	}
+	if m.ShowAuditLog {
+		return m.AuditLog.View()
+	}
+	if m.ShowExport {
+		return m.Export.View()
+	}
+	if m.ShowSearch {
+		return m.Search.View()
+	}
+	if m.ShowColumns {
+		return m.Columns.View()
+	}
 	if m.ShowHelp {
 		return HelpView(m.Width, m.Height)
 	}
@@ -28,20 +46,37 @@ func (m MainModel) View() string {
 	}
 
 	// Status Bar (Always Visible at bottom)
-	statusBar := m.StatusBar.View()
+	// Data age only applies to the service being viewed
+	sb := m.StatusBar
+	if m.ViewMode == ViewHome {
+		sb.LastUpdated = time.Time{}
+	}
+	_, sb.ReadOnly = core.ReadOnlyReason(m.AuthState.ProjectID)
+	sb.Protected = core.IsProtected(m.AuthState.ProjectID)
+	if m.Config != nil {
+		sb.Profile = m.Config.ActiveProfile
+	}
+	if m.ViewMode == ViewService {
+		sb.Refresh = m.Refresh.Label(m.ActiveService)
+		sb.RefreshAlert = m.Refresh.Mode(m.ActiveService) != core.RefreshAuto
+	}
+	statusBar := sb.View()
 
 	// Layout Content + Status Bar
 	screen := lipgloss.JoinVertical(lipgloss.Top, content, statusBar)
 
-	// 3. Toast Overlay (if active)
+	// 3. Pending operations panel and toast overlay, above the status bar
+	var overlays []string
+	if ops, bulks := core.PendingOperations(), core.PendingBulks(); len(ops) > 0 || len(bulks) > 0 {
+		overlays = append(overlays, lipgloss.PlaceHorizontal(m.Width, lipgloss.Right, components.RenderOperations(ops, bulks)))
+	}
 	if m.Toast != nil && !m.Toast.IsExpired() {
-		toastView := m.Toast.View()
-		// Position toast at bottom-right, above status bar
-		screen = lipgloss.JoinVertical(lipgloss.Top,
-			content,
-			lipgloss.PlaceHorizontal(m.Width, lipgloss.Right, toastView),
-			statusBar,
-		)
+		// Position toast at bottom-right
+		overlays = append(overlays, lipgloss.PlaceHorizontal(m.Width, lipgloss.Right, m.Toast.View()))
+	}
+	if len(overlays) > 0 {
+		parts := append([]string{content}, overlays...)
+		screen = lipgloss.JoinVertical(lipgloss.Top, append(parts, statusBar)...)
 	}
 
 	// 4. Loading Spinner (if active) - show inline at top of content
@@ -82,9 +117,25 @@ func renderLandingPage(m MainModel) string {
 	// User Info Box
 	userInfo := fmt.Sprintf(
 		"👤 User: %s    📁 Project: %s",
-		m.AuthState.UserEmail,
+		m.AuthState.EffectiveIdentity(),
 		m.AuthState.ProjectID,
 	)
+	if m.AuthState.ImpersonatedAccount != "" {
+		userInfo = fmt.Sprintf(
+			"🎭 Acting as: %s (via %s)    📁 Project: %s",
+			m.AuthState.ImpersonatedAccount,
+			m.AuthState.UserEmail,
+			m.AuthState.ProjectID,
+		)
+	}
+	if m.Config != nil && m.Config.ActiveProfile != "" {
+		userInfo = "🏷  Profile: " + m.Config.ActiveProfile + "    " + userInfo
+	}
+	if reason, ok := core.ReadOnlyReason(m.AuthState.ProjectID); ok {
+		userInfo += "    🔒 " + reason
+	} else if core.IsProtected(m.AuthState.ProjectID) {
+		userInfo += "    🛡  protected"
+	}
 	infoBox := styles.PrimaryBoxStyle.Copy().
 		Render(userInfo)
 
@@ -2,12 +2,22 @@ package ui
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/yogirk/tgcp/internal/config"
 	"github.com/yogirk/tgcp/internal/core"
+	"github.com/yogirk/tgcp/internal/keys"
+	"github.com/yogirk/tgcp/internal/search"
 	"github.com/yogirk/tgcp/internal/services"
+	"github.com/yogirk/tgcp/internal/styles"
 	"github.com/yogirk/tgcp/internal/services/bigquery"
 	"github.com/yogirk/tgcp/internal/services/bigtable"
 	"github.com/yogirk/tgcp/internal/services/cloudrun"
@@ -28,6 +38,7 @@ import (
 	"github.com/yogirk/tgcp/internal/services/secrets"
 	"github.com/yogirk/tgcp/internal/services/spanner"
 	"github.com/yogirk/tgcp/internal/ui/components"
+	"github.com/yogirk/tgcp/internal/utils"
 )
 
 // ViewMode defines the high-level view state
@@ -64,18 +75,44 @@ type MainModel struct {
 	Sidebar   components.SidebarModel
 	HomeMenu  components.HomeMenuModel // Added
 	StatusBar components.StatusBarModel
-	Palette   components.PaletteModel  // Added
-	Toast     *components.ToastModel   // Toast notification (nil when hidden)
-	Spinner   components.SpinnerModel  // Global loading spinner
+	Palette   components.PaletteModel      // Added
+	Toast     *components.ToastModel       // Toast notification (nil when hidden)
+	Spinner   components.SpinnerModel      // Global loading spinner
+	Inspector components.InspectorModel    // API call inspector overlay
+	AuditLog  components.AuditLogModel     // Local audit trail viewer
+	Export    components.ExportDialogModel // Table export dialog
+	Search    components.SearchModel       // Cross-service resource search
+	Columns   components.ColumnEditorModel // Column layout editor
 
 	// State
 	ViewMode      ViewMode // Added
 	Focus         FocusArea
 	LastFocus     FocusArea
 	ShowHelp      bool
+	ShowInspector bool
+	ShowAuditLog  bool
+	ShowExport    bool
+	ShowSearch    bool
+	ShowColumns   bool
 	ActiveService string
 
+	// Exports to print to stdout once the program exits
+	exitOutput []string
+
+	// Invalid keybindings or themes in the config, reported once the UI starts
+	configErr error
+
+	// Deep link or bookmark to open once the UI starts (--open)
+	startLink string
+
+	// When the service on screen reloads its data in the background
+	Refresh core.RefreshSchedule
+
+	// Config is the active configuration, with the selected profile applied
+	Config *config.Config
+
 	// External Managers
+	Cache           *core.Cache
 	ProjectManager  *core.ProjectManager
 	ServiceRegistry *core.ServiceRegistry
 
@@ -88,45 +125,121 @@ type MainModel struct {
 func InitialModel(authState core.AuthState, cfg *config.Config, version core.VersionInfo) MainModel {
 	// Initialize Cache
 	cache := core.NewCache()
+	cache.SetProject(authState.ProjectID)
+	if cfg.Cache.Disk {
+		if dir, err := core.DefaultCacheDir(); err == nil {
+			if disk, err := core.NewDiskCache(dir, int64(cfg.Cache.MaxSizeMB)<<20); err == nil {
+				cache.AttachDisk(disk)
+			} else {
+				utils.Log("Disk cache disabled: %v", err)
+			}
+		}
+	}
+
+	// Saved table layouts, keybindings and the theme must be in place
+	// before services build their tables
+	components.SetTableLayouts(cfg.Tables)
+	keysErr := keys.Configure(cfg.Keys)
+	themeErr := setTheme(cfg)
 
 	// Create service registry and register all services
 	registry := core.NewServiceRegistry(cache)
+	registry.SetLocation(cfg.Region, cfg.Zone)
 	registerAllServices(registry)
 
 	// Create service map but don't initialize services yet (lazy initialization)
 	// Services will be initialized on first access
 	svcMap := registry.InitializeAll(context.Background(), authState.ProjectID)
 
+	if p, ok := cfg.Profile(); ok {
+		styles.SetAccent(p.Accent)
+	}
+
 	// Initialize Components
 	sb := components.NewSidebar()
 	sb.Visible = cfg.UI.SidebarVisible
 	statusBar := components.NewStatusBar()
 	statusBar.SetFocusPane("HOME")
+	nav := core.NewNavigation()
+	nav.AddBookmarks(cfg.Bookmarks)
 
 	return MainModel{
 		AuthState:       authState,
-		Navigation:      core.NewNavigation(),
+		Navigation:      nav,
 		Sidebar:         sb,
 		HomeMenu:        components.NewHomeMenu(),
 		StatusBar:       statusBar,
 		Palette:         components.NewPalette(),
+		Inspector:       components.NewInspector(),
+		AuditLog:        components.NewAuditLog(),
+		Search:          components.NewSearch(),
 		Spinner:         components.NewSpinner(),
 		Focus:           FocusSidebar,
 		ViewMode:        ViewHome,
 		ServiceMap:      svcMap,
+		Config:          cfg,
+		Cache:           cache,
 		ProjectManager:  core.NewProjectManager(cache),
 		ServiceRegistry: registry,
 		Version:         version,
+		Refresh:         core.NewRefreshSchedule(cfg.UI, refreshDefaults),
+		configErr:       errors.Join(keysErr, themeErr),
+	}
+}
+
+// setTheme applies the theme selected by ui.theme. Custom themes come from
+// ~/.tgcprc and ~/.tgcp/themes.
+func setTheme(cfg *config.Config) error {
+	themes := cfg.Themes
+	var loadErr error
+	if dir, err := config.ThemesDir(); err == nil {
+		themes, loadErr = cfg.LoadThemes(dir)
 	}
+	return errors.Join(loadErr, styles.SetTheme(cfg.UI.Theme, themes))
 }
 
 // Init initializes the bubbletea program
 func (m MainModel) Init() tea.Cmd {
 	// Start with mouse support and check for updates in background
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		tea.EnableMouseCellMotion,
 		core.CheckForUpdates(m.Version.Version),
-	)
+		core.RefreshClock(),
+	}
+	if m.configErr != nil {
+		err := m.configErr
+		cmds = append(cmds, func() tea.Msg {
+			return core.ToastMsg{Message: err.Error(), Type: core.ToastError, Duration: 10 * time.Second}
+		})
+	}
+	if m.startLink != "" && m.AuthState.Error == nil {
+		cmds = append(cmds, m.openLink(m.startLink))
+	}
+	return tea.Batch(cmds...)
+}
+
+// OpenOnStart sets a deep link or bookmark name to open once the UI starts
+func (m *MainModel) OpenOnStart(link string) {
+	m.startLink = link
+}
+
+// openLink opens a deep link, or the link saved under a bookmark name
+func (m MainModel) openLink(link string) tea.Cmd {
+	if bookmark, ok := m.Config.Bookmarks[link]; ok {
+		link = bookmark
+	}
+	msg, err := core.ParseLink(link)
+	if err == nil {
+		if open, ok := msg.(core.OpenResourceMsg); ok && m.ServiceMap[open.Service] == nil {
+			err = fmt.Errorf("link %q: unknown service %q", link, open.Service)
+		}
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return core.ToastMsg{Message: err.Error(), Type: core.ToastError, Duration: 10 * time.Second}
+		}
+	}
+	return func() tea.Msg { return msg }
 }
 
 // getOrInitializeService gets a service from the map, initializing it lazily if needed
@@ -182,6 +295,32 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Toast = nil
 		return m, nil
 
+	// Long-running Operations
+	case core.OperationPendingMsg:
+		return m, core.ContinuePolling(msg)
+
+	case core.OperationDoneMsg:
+		// Operations of a bulk action are summarized once the batch is done
+		if msg.Op.Bulk != "" {
+			return m, core.BulkOperationDone(msg.Op)
+		}
+		m.Toast = components.NewToastFromMsg(core.OperationToast(msg.Op))
+		cmds = append(cmds, m.Toast.DismissCmd())
+		// Let the affected service reload the resource, even when the user
+		// has moved on, so its cached list is current when they return
+		cmds = append(cmds, m.updateService(msg.Op.Service, msg))
+		return m, tea.Batch(cmds...)
+
+	case core.BulkItemMsg:
+		return m, core.HandleBulkItem(msg)
+
+	case core.BulkDoneMsg:
+		m.Toast = components.NewToastFromMsg(core.BulkToast(msg))
+		cmds = append(cmds, m.Toast.DismissCmd())
+		// Let the affected service reload the resources
+		cmds = append(cmds, m.updateService(msg.Service, msg))
+		return m, tea.Batch(cmds...)
+
 	// Version Update Check
 	case core.UpdateCheckedMsg:
 		m.UpdateInfo = &msg.UpdateInfo
@@ -224,11 +363,153 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.StatusBar.LastUpdated = time.Time(msg)
 		return m, nil
 
+	case profileSwitchedMsg:
+		if msg.auth.Error != nil {
+			// Keep the previous profile's identity for any client created later
+			core.SetImpersonation(m.Config.ImpersonateServiceAccount)
+			m.StatusBar.Message = "Failed to switch profile: " + msg.auth.Error.Error()
+			m.StatusBar.IsError = true
+			return m, nil
+		}
+
+		profile, _ := msg.cfg.Profile()
+		styles.SetAccent(profile.Accent)
+		core.SetGuardrails(msg.cfg)
+		core.SetAuditIdentity(msg.auth.UserEmail, msg.auth.ImpersonatedAccount)
+		m.Config = msg.cfg
+		m.Refresh.Configure(msg.cfg.UI)
+		m.AuthState = msg.auth
+		m.StatusBar.StaleSince = time.Time{}
+		if m.ServiceRegistry != nil {
+			m.ServiceRegistry.SetLocation(msg.cfg.Region, msg.cfg.Zone)
+			m.ServiceRegistry.ReinitializeAll(context.Background(), m.AuthState.ProjectID, m.ServiceMap)
+		}
+		m.StatusBar.Message = fmt.Sprintf("Switched to profile %s (project: %s)", msg.cfg.ActiveProfile, m.AuthState.ProjectID)
+		m.StatusBar.IsError = false
+
+		// Refresh whatever is on screen with the new project and identity
+		if m.ViewMode == ViewService && m.CurrentSvc != nil {
+			return m, m.CurrentSvc.Refresh()
+		}
+		return m, nil
+
+	case search.ResultsMsg:
+		m.Search.SetResults(msg)
+		return m, nil
+
+	case components.EditColumnsMsg:
+		m.Columns = components.NewColumnEditor(msg.Table)
+		m.Columns.Width = m.Width
+		m.Columns.Height = m.Height
+		m.ShowColumns = true
+		m.ShowHelp = false
+		return m, nil
+
+	case core.RefreshClockMsg:
+		cmds = append(cmds, core.RefreshClock())
+		service := ""
+		if m.ViewMode == ViewService && m.CurrentSvc != nil {
+			service = m.ActiveService
+		}
+		if m.Refresh.Due(service, time.Time(msg)) {
+			newModel, cmd := m.CurrentSvc.Update(core.RefreshTickMsg{})
+			if updatedSvc, ok := newModel.(services.Service); ok {
+				m.CurrentSvc = updatedSvc
+				m.ServiceMap[m.ActiveService] = updatedSvc
+			}
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case core.StaleDataMsg:
+		m.StatusBar.StaleSince = msg.SavedAt
+		return m, nil
+
+	case components.InspectorTickMsg:
+		if !m.ShowInspector {
+			return m, nil // Stop ticking once closed
+		}
+		m.Inspector, cmd = m.Inspector.Update(msg)
+		return m, cmd
+
 	case tea.KeyMsg:
+		if key.Matches(msg, keys.ForceQuit) {
+			return m, tea.Quit
+		}
+
+		// The inspector overlay captures all keys while open
+		if m.ShowInspector {
+			if key.Matches(msg, keys.Back, keys.Inspector) {
+				m.ShowInspector = false
+				return m, nil
+			}
+			m.Inspector, cmd = m.Inspector.Update(msg)
+			return m, cmd
+		}
+
+		// Same for the audit log viewer
+		if m.ShowAuditLog {
+			if key.Matches(msg, keys.Back) {
+				m.ShowAuditLog = false
+				return m, nil
+			}
+			m.AuditLog, cmd = m.AuditLog.Update(msg)
+			return m, cmd
+		}
+
+		// And the export dialog
+		if m.ShowExport {
+			var dest components.ExportDestination
+			m.Export, dest = m.Export.HandleKey(msg)
+			switch dest {
+			case components.ExportToFile:
+				m.ShowExport = false
+				return m, writeExportCmd(m.Export)
+			case components.ExportToStdout:
+				m.ShowExport = false
+				toast := m.queueExitExport()
+				return m, func() tea.Msg { return toast }
+			case components.ExportCancelled:
+				m.ShowExport = false
+			}
+			return m, nil
+		}
+
+		// And the column editor
+		if m.ShowColumns {
+			var done bool
+			m.Columns, done = m.Columns.HandleKey(msg)
+			if done {
+				m.ShowColumns = false
+				return m, m.Columns.Table.SaveLayout()
+			}
+			return m, nil
+		}
+
+		// And the resource search
+		if m.ShowSearch {
+			switch msg.String() {
+			case "esc":
+				m.ShowSearch = false
+				m.Search.Input.Blur()
+				return m, nil
+			case "tab":
+				m.Search.AllProjects = !m.Search.AllProjects
+				return m, m.runSearch()
+			case "enter":
+				if m.Search.Dirty() {
+					return m, m.runSearch()
+				}
+				return m, m.openSearchResult()
+			}
+			m.Search, cmd = m.Search.Update(msg)
+			return m, cmd
+		}
+
 		// Global Keybindings
 		if m.Focus != FocusPalette {
-			switch msg.String() {
-			case "q", "esc":
+			switch {
+			case key.Matches(msg, keys.Quit, keys.Back):
 				if m.ShowHelp {
 					m.ShowHelp = false
 					return m, nil
@@ -247,19 +528,31 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					return m, tea.Quit
 				}
-			case "ctrl+c":
-				return m, tea.Quit
-			case ":":
+			case key.Matches(msg, keys.Palette):
 				m.LastFocus = m.Focus
 				m.setFocus(FocusPalette)
 				m.Navigation.PaletteActive = true
+				m.Navigation.SetResourceCommands(core.BuildResourceCommands(m.ServiceMap))
 				m.StatusBar.Mode = "COMMAND"
 				m.StatusBar.Message = "Type command..."
 				return m, nil
-			case "?":
+			case key.Matches(msg, keys.Help):
 				m.ShowHelp = !m.ShowHelp
 				return m, nil
-			case "tab":
+			case key.Matches(msg, keys.Inspector):
+				return m, m.openInspector()
+			case key.Matches(msg, keys.Export):
+				return m, m.openExport()
+			case key.Matches(msg, keys.HistoryBack):
+				return m, m.goBack()
+			case key.Matches(msg, keys.HistoryForward):
+				return m, m.goForward()
+			case key.Matches(msg, keys.RefreshMode):
+				if m.ViewMode == ViewService {
+					m.StatusBar.Message = refreshModeMessage(m.ActiveService, m.Refresh.Cycle(m.ActiveService), m.Refresh.Interval(m.ActiveService))
+				}
+				return m, nil
+			case key.Matches(msg, keys.Sidebar):
 				if m.ViewMode == ViewService {
 					m.Sidebar.Visible = !m.Sidebar.Visible
 					// Adjust focus if hiding active sidebar
@@ -294,25 +587,55 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Route Logic
 					if route.View == core.ViewHome {
 						// Check for Project Switch
-						if len(route.ID) > 15 && route.ID[:15] == "SWITCH_PROJECT:" {
-							newProjectID := route.ID[15:]
-							m.AuthState.ProjectID = newProjectID
-
-							// Re-initialize all services with new project using registry
-							if m.ServiceRegistry != nil {
-								m.ServiceRegistry.ReinitializeAll(context.Background(), newProjectID, m.ServiceMap)
-							}
-
-							m.StatusBar.Message = "Switched to project: " + newProjectID
+						if strings.HasPrefix(route.ID, core.SwitchProjectPrefix) {
+							m.switchProject(strings.TrimPrefix(route.ID, core.SwitchProjectPrefix))
 							m.Navigation.RestoreBaseCommands()
 							m.ViewMode = ViewHome
 							m.Sidebar.Active = false
+						} else if strings.HasPrefix(route.ID, core.OpenLinkPrefix) {
+							m.Navigation.RestoreBaseCommands()
+							cmds = append(cmds, m.openLink(strings.TrimPrefix(route.ID, core.OpenLinkPrefix)))
+						} else if strings.HasPrefix(route.ID, core.SwitchProfilePrefix) {
+							name := strings.TrimPrefix(route.ID, core.SwitchProfilePrefix)
+							m.Navigation.RestoreBaseCommands()
+							cmds = append(cmds, m.switchProfileCmd(name))
+						} else if route.ID == core.RouteClearCache {
+							// Stay on the current view; report via StatusMsg since closing the palette resets the message
+							status := core.StatusMsg{Message: "Cleared cache for project: " + m.AuthState.ProjectID}
+							if err := m.Cache.InvalidateProject(); err != nil {
+								status = core.StatusMsg{Message: "Failed to clear cache: " + err.Error(), IsError: true}
+							}
+							m.StatusBar.StaleSince = time.Time{}
+							m.Navigation.RestoreBaseCommands()
+							cmds = append(cmds, func() tea.Msg { return status })
+						} else if route.ID == core.RouteAPIInspector {
+							m.Navigation.RestoreBaseCommands()
+							cmds = append(cmds, m.openInspector())
+						} else if route.ID == core.RouteAuditLog {
+							m.Navigation.RestoreBaseCommands()
+							m.ShowAuditLog = true
+							m.ShowHelp = false
+							m.AuditLog.Open()
+						} else if route.ID == core.RouteExportTable {
+							m.Navigation.RestoreBaseCommands()
+							cmds = append(cmds, m.openExport())
+						} else if route.ID == core.RouteSearch {
+							m.Navigation.RestoreBaseCommands()
+							m.ShowSearch = true
+							m.ShowHelp = false
+							cmds = append(cmds, m.Search.Open())
+						} else if route.ID == core.RouteRateLimitStats {
+							status := core.StatusMsg{Message: rateLimitSummary(core.RateLimitStats())}
+							m.Navigation.RestoreBaseCommands()
+							cmds = append(cmds, func() tea.Msg { return status })
 						} else {
+							m.pushHistory()
 							m.ViewMode = ViewHome
 							m.Sidebar.Active = false
 						}
 					} else if route.View == core.ViewServiceList {
 						// Logic to switch service
+						m.pushHistory()
 						m.ViewMode = ViewService
 						m.ActiveService = route.Service
 						// Sync Sidebar
@@ -349,6 +672,31 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 						m.setFocus(FocusMain)
 						m.Sidebar.Active = false
+					} else if route.View == core.ViewResourceDetail {
+						open := core.OpenResourceMsg{Service: route.Service, Project: route.Project, Ref: route.Location.Resource, Action: route.ID}
+						cmds = append(cmds, func() tea.Msg { return open })
+					} else if route.View == core.ViewProfileSwitcher {
+						names := m.Config.ProfileNames()
+						if len(names) == 0 {
+							m.StatusBar.Message = "No profiles configured in ~/.tgcprc"
+							m.Navigation.RestoreBaseCommands()
+							m.Navigation.FilterCommands("")
+							return m, nil
+						}
+						var profileCmds []core.Command
+						for _, name := range names {
+							id := core.SwitchProfilePrefix + name
+							profileCmds = append(profileCmds, core.Command{
+								Name:        name,
+								Description: profileDescription(m.Config.Profiles[name], name == m.Config.ActiveProfile),
+								Action:      func() core.Route { return core.Route{View: core.ViewHome, ID: id} },
+							})
+						}
+						m.Navigation.SetCommands(profileCmds)
+						m.Palette.TextInput.Reset()
+						m.StatusBar.Message = "Select a profile to switch..."
+						m.setFocus(FocusPalette)
+						return m, nil
 					} else if route.View == core.ViewProjectSwitcher {
 						// Trigger fetch projects
 						cmds = append(cmds, func() tea.Msg {
@@ -397,8 +745,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// HOME MODE
 		if m.ViewMode == ViewHome && !m.ShowHelp && m.Focus != FocusPalette {
-			switch msg.String() {
-			case "enter", " ":
+			switch {
+			case key.Matches(msg, keys.Select) || msg.String() == " ":
 				// If on category header, toggle it
 				if m.HomeMenu.IsOnCategory() {
 					m.HomeMenu.ToggleCurrentCategory()
@@ -407,6 +755,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Select service
 				selected := m.HomeMenu.SelectedItem()
 				if selected.ShortName != "" && !selected.IsComing { // Only allow entering implemented services
+					m.pushHistory()
 					m.ViewMode = ViewService
 					m.ActiveService = selected.ShortName
 
@@ -460,9 +809,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// SERVICE MODE
 		if m.ViewMode == ViewService {
 			// Handle 'q' explicitly for hierarchy navigation (Fix Bug 1)
-			if msg.String() == "q" && !m.ShowHelp && m.Focus != FocusPalette {
+			if key.Matches(msg, keys.Quit) && !m.ShowHelp && m.Focus != FocusPalette {
 				if m.CurrentSvc != nil && m.CurrentSvc.IsRootView() {
 					// If at root of service, go back to Home
+					m.pushHistory()
 					m.ViewMode = ViewHome
 					m.Sidebar.Active = false
 					m.HomeMenu.IsFocused = true
@@ -475,8 +825,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Focus Switching - Handle BEFORE forwarding to service
 			if m.Focus != FocusPalette && !m.ShowHelp {
-				switch msg.String() {
-				case "left":
+				switch {
+				case key.Matches(msg, keys.FocusSidebar):
 					// Always allow escaping to sidebar with Left Arrow
 					if m.Focus == FocusMain {
 						m.setFocus(FocusSidebar)
@@ -484,20 +834,13 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						// Do not forward 'left' to service
 						return m, nil
 					}
-				case "h":
-					// Only allow 'h' to switch focus if we are in sidebar
-					// In FocusMain, 'h' is reserved for SSH
-					if m.Focus == FocusSidebar {
-						// Actually 'h' in sidebar usually collapses or does nothing contextually
-						// But for now let's just keep 'left' behavior or ignore it for consistency
-					}
-				case "right", "l":
+				case key.Matches(msg, keys.FocusMain):
 					if m.Focus == FocusSidebar && m.Sidebar.Visible {
 						m.setFocus(FocusMain)
 						m.Sidebar.Active = false
 						return m, nil
 					}
-				case "enter":
+				case key.Matches(msg, keys.Select):
 					// 'enter' in sidebar also moves to main
 					if m.Focus == FocusSidebar && m.Sidebar.Visible {
 						m.setFocus(FocusMain)
@@ -532,7 +875,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Action: func() core.Route {
 					return core.Route{
 						View: core.ViewHome,
-						ID:   "SWITCH_PROJECT:" + p.ID,
+						ID:   core.SwitchProjectPrefix + p.ID,
 					}
 				},
 			})
@@ -542,6 +885,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case core.SwitchToLogsMsg:
+		m.pushHistory()
+		if msg.Project != "" && msg.Project != m.AuthState.ProjectID {
+			m.switchProject(msg.Project)
+		}
 		// Switch to Logging Service
 		m.ViewMode = ViewService
 		m.ActiveService = "logs"
@@ -611,6 +958,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case core.SwitchToServiceMsg:
 		// Switch to a specific service
+		m.pushHistory()
 		m.ViewMode = ViewService
 		m.ActiveService = msg.Service
 		// Sync Sidebar
@@ -654,6 +1002,30 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Sidebar.Active = true
 		return m, tea.Batch(cmds...)
 
+	case core.NavigateBackMsg:
+		return m, m.goBack()
+
+	case core.OpenResourceMsg:
+		m.pushHistory()
+		if msg.Project != "" && msg.Project != m.AuthState.ProjectID {
+			m.switchProject(msg.Project)
+		}
+		svc, cmd := m.enterService(msg.Service)
+		if svc == nil {
+			return m, cmd
+		}
+
+		// Ask for the resource before the refresh, so it opens as soon as the list arrives
+		if runner, ok := svc.(services.CommandProvider); ok && msg.Action != "" {
+			cmds = append(cmds, runner.RunCommand(msg.Action, msg.Ref))
+		} else if opener, ok := svc.(services.ResourceOpener); ok && msg.Ref != (services.ResourceRef{}) {
+			cmds = append(cmds, opener.OpenResource(msg.Ref))
+		}
+		cmds = append(cmds, svc.Refresh())
+		m.setFocus(FocusMain)
+		m.Sidebar.Active = false
+		return m, tea.Batch(cmds...)
+
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
@@ -661,6 +1033,16 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		availableHeight := msg.Height - 1
 		m.Sidebar.Height = availableHeight
 		m.StatusBar.Width = msg.Width
+		m.Inspector.Width = msg.Width
+		m.Inspector.Height = msg.Height
+		m.AuditLog.Width = msg.Width
+		m.AuditLog.Height = msg.Height
+		m.Export.Width = msg.Width
+		m.Export.Height = msg.Height
+		m.Search.Width = msg.Width
+		m.Search.Height = msg.Height
+		m.Columns.Width = msg.Width
+		m.Columns.Height = msg.Height
 
 		// Update home menu with screen dimensions for mouse click calculations
 		m.HomeMenu.ScreenWidth = msg.Width
@@ -686,6 +1068,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.Sidebar.Active = false
 					if m.CurrentSvc != nil {
 						m.CurrentSvc.Focus()
+						// Let a table sort if its header was clicked
+						if click, ok := m.headerClick(msg); ok {
+							_, svcCmd := m.CurrentSvc.Update(click)
+							cmds = append(cmds, svcCmd)
+						}
 						// Adjust X coordinate to be relative to main content area
 						adjustedMsg := tea.MouseMsg{
 							X:      msg.X - m.Sidebar.Width,
@@ -716,6 +1103,13 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Sidebar, cmd = m.Sidebar.Update(msg)
 				cmds = append(cmds, cmd)
 			} else if m.CurrentSvc != nil {
+				// With the sidebar hidden, clicks land here
+				if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+					if click, ok := m.headerClick(msg); ok {
+						_, svcCmd := m.CurrentSvc.Update(click)
+						cmds = append(cmds, svcCmd)
+					}
+				}
 				newModel, svcCmd := m.CurrentSvc.Update(msg)
 				if updatedSvc, ok := newModel.(services.Service); ok {
 					m.CurrentSvc = updatedSvc
@@ -740,6 +1134,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.Sidebar.Active {
 			selectedSvc := m.Sidebar.SelectedService()
 			if selectedSvc.ShortName != "" && m.ActiveService != selectedSvc.ShortName {
+				// Browsing the sidebar passes through every service on the way,
+				// so only record places where something was opened or filtered
+				if from := m.currentRoute(); from.Location != (services.Location{}) {
+					m.pushHistory()
+				}
 				m.ActiveService = selectedSvc.ShortName
 				// Get or initialize service lazily
 				svc, err := m.getOrInitializeService(context.Background(), m.ActiveService)
@@ -798,9 +1197,9 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.StatusBar.SetHelpText("Esc:Cancel  Enter:Run  ↑/↓:Select")
 	} else if m.ViewMode == ViewHome {
 		// Add help hint if help is not currently shown
-		helpText := "q:Quit  Enter:Select"
+		helpText := keys.HelpLine(keys.Quit, keys.Select)
 		if !m.ShowHelp {
-			helpText += "  ?:Help"
+			helpText += "  " + keys.HelpLine(keys.Help)
 		}
 		m.StatusBar.SetHelpText(helpText)
 	} else if m.CurrentSvc != nil {
@@ -809,16 +1208,16 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.ShowHelp {
 			// Append help hint to service help text
 			if helpText != "" {
-				helpText += "  ?:Help"
+				helpText += "  " + keys.HelpLine(keys.Help)
 			} else {
-				helpText = "?:Help"
+				helpText = keys.HelpLine(keys.Help)
 			}
 		}
 		m.StatusBar.SetHelpText(helpText)
 	} else {
 		// Fallback: show help hint if available
 		if !m.ShowHelp {
-			m.StatusBar.SetHelpText("?:Help")
+			m.StatusBar.SetHelpText(keys.HelpLine(keys.Help))
 		} else {
 			m.StatusBar.SetHelpText("")
 		}
@@ -892,6 +1291,29 @@ func registerAllServices(registry *core.ServiceRegistry) {
 	})
 }
 
+// refreshDefaults is how often each service refreshes in the background
+// unless ~/.tgcprc says otherwise: as often as its cached data expires
+var refreshDefaults = map[string]time.Duration{
+	"gce":       gce.CacheTTL,
+	"gke":       gke.CacheTTL,
+	"disks":     disks.CacheTTL,
+	"pubsub":    pubsub.CacheTTL,
+	"redis":     redis.CacheTTL,
+	"spanner":   spanner.CacheTTL,
+	"bigtable":  bigtable.CacheTTL,
+	"dataflow":  dataflow.CacheTTL,
+	"dataproc":  dataproc.CacheTTL,
+	"firestore": firestore.CacheTTL,
+	"sql":       cloudsql.CacheTTL,
+	"iam":       iam.CacheTTL,
+	"run":       cloudrun.CacheTTL,
+	"gcs":       gcs.CacheTTL,
+	"bq":        bigquery.CacheTTL,
+	"net":       net.CacheTTL,
+	"logs":      logging.CacheTTL,
+	"secrets":   secrets.CacheTTL,
+}
+
 func (m *MainModel) setFocus(area FocusArea) {
 	m.Focus = area
 	if m.ViewMode == ViewService && m.CurrentSvc != nil {
@@ -920,3 +1342,348 @@ func (m *MainModel) syncStatusBarFocus() {
 		m.StatusBar.SetFocusPane("")
 	}
 }
+
+// rateLimitSummary renders limiter stats for the status bar, busiest hosts first
+func rateLimitSummary(stats []core.HostLimiterStats) string {
+	if len(stats) == 0 {
+		return "Rate limits: no API calls yet"
+	}
+	parts := make([]string, 0, 3)
+	for i, s := range stats {
+		if i == 3 {
+			break
+		}
+		host := strings.TrimSuffix(s.Host, ".googleapis.com")
+		parts = append(parts, fmt.Sprintf("%s %d req, %d waited (avg %s, max %s)",
+			host, s.Requests, s.Waits, s.AvgWait().Round(time.Millisecond), s.MaxWait.Round(time.Millisecond)))
+	}
+	return "Rate limits: " + strings.Join(parts, " │ ")
+}
+
+// openInspector shows the API call inspector, filtered to the active service
+func (m *MainModel) openInspector() tea.Cmd {
+	m.ShowInspector = true
+	m.ShowHelp = false
+	service := ""
+	if m.ViewMode == ViewService {
+		service = m.ActiveService
+	}
+	return m.Inspector.Open(service)
+}
+
+// headerClick returns the screen line under a click, for tables to check
+// whether their header was clicked
+func (m MainModel) headerClick(msg tea.MouseMsg) (components.HeaderClickMsg, bool) {
+	lines := strings.Split(m.View(), "\n")
+	if msg.Y < 0 || msg.Y >= len(lines) {
+		return components.HeaderClickMsg{}, false
+	}
+	return components.HeaderClickMsg{Line: ansi.Strip(lines[msg.Y]), X: msg.X}, true
+}
+
+// openExport shows the export dialog for the table of the active service
+func (m *MainModel) openExport() tea.Cmd {
+	svc, ok := m.CurrentSvc.(interface {
+		ExportTable() components.TableExport
+	})
+	if m.ViewMode != ViewService || !ok {
+		return func() tea.Msg {
+			return core.ToastMsg{Message: "Nothing to export here", Type: core.ToastInfo}
+		}
+	}
+	m.Export = components.NewExportDialog(svc.ExportTable())
+	m.Export.Width = m.Width
+	m.Export.Height = m.Height
+	m.ShowExport = true
+	m.ShowHelp = false
+	return nil
+}
+
+// writeExportCmd writes an export to a timestamped file in the working directory
+func writeExportCmd(d components.ExportDialogModel) tea.Cmd {
+	return func() tea.Msg {
+		name := fmt.Sprintf("tgcp-%s-%s.%s", d.Export.Name, time.Now().Format("20060102-150405"), d.Format.Ext())
+		f, err := os.Create(name)
+		if err == nil {
+			err = d.Export.Write(f, d.Format, d.Full)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			return core.ToastMsg{Message: "Export failed: " + err.Error(), Type: core.ToastError}
+		}
+		if abs, err := filepath.Abs(name); err == nil {
+			name = abs
+		}
+		return core.ToastMsg{Message: fmt.Sprintf("Exported %d row(s) to %s", len(d.Export.Rows), name), Type: core.ToastSuccess}
+	}
+}
+
+// runSearch shows matches from the loaded service lists right away and
+// searches Cloud Asset Inventory in the background
+func (m *MainModel) runSearch() tea.Cmd {
+	query := m.Search.Query()
+	if query == "" {
+		return nil
+	}
+	providers := make(map[string]services.ResourceProvider)
+	for name, svc := range m.ServiceMap {
+		if p, ok := svc.(services.ResourceProvider); ok {
+			providers[name] = p
+		}
+	}
+	local := search.Local(query, providers)
+	scopes := search.Scopes(m.AuthState.ProjectID, m.Search.AllProjects, m.Config.Search.Projects, m.Config.Search.Scope)
+	m.Search.Start(query, scopes, local)
+	return search.SearchCmd(query, scopes, local)
+}
+
+// openSearchResult closes the search and jumps to the selected resource
+func (m *MainModel) openSearchResult() tea.Cmd {
+	r, ok := m.Search.Selected()
+	if !ok {
+		return nil
+	}
+	if r.Service == "" {
+		toast := core.ToastMsg{Message: "No view for " + r.Meta.Kind + " yet", Type: core.ToastInfo}
+		return func() tea.Msg { return toast }
+	}
+	if search.IsProjectNumber(r.Meta.Project) {
+		toast := core.ToastMsg{Message: "Couldn't resolve project " + r.Meta.Project + " to an ID", Type: core.ToastError}
+		return func() tea.Msg { return toast }
+	}
+	m.ShowSearch = false
+	m.Search.Input.Blur()
+	open := core.OpenResourceMsg{Service: r.Service, Project: r.Meta.Project, Ref: r.Ref()}
+	return func() tea.Msg { return open }
+}
+
+// enterService switches the main view to a service, reset to its list and
+// sized to the screen. It returns nil and an error message when the service
+// can't be initialized.
+func (m *MainModel) enterService(name string) (services.Service, tea.Cmd) {
+	m.ViewMode = ViewService
+	m.ActiveService = name
+	// Sync Sidebar
+	for i, item := range m.Sidebar.Items {
+		if item.ShortName == name {
+			m.Sidebar.Cursor = i
+			break
+		}
+	}
+
+	svc, err := m.getOrInitializeService(context.Background(), name)
+	if err != nil {
+		return nil, func() tea.Msg {
+			return core.StatusMsg{Message: "Failed to initialize service: " + err.Error(), IsError: true}
+		}
+	}
+	if svc == nil {
+		return nil, nil
+	}
+	svc.Reset()
+	m.CurrentSvc = svc
+
+	// Sync Window Size
+	if m.Width > 0 && m.Height > 0 {
+		availWidth := m.Width
+		if m.Sidebar.Visible {
+			availWidth -= m.Sidebar.Width
+		}
+		newModel, _ := svc.Update(tea.WindowSizeMsg{
+			Width:  availWidth,
+			Height: m.Height,
+		})
+		if updatedSvc, ok := newModel.(services.Service); ok {
+			svc = updatedSvc
+			m.ServiceMap[name] = svc
+			m.CurrentSvc = svc
+		}
+	}
+	return svc, nil
+}
+
+// updateService passes a message to a service whether or not it is on
+// screen, e.g. when an operation it started has finished
+func (m *MainModel) updateService(name string, msg tea.Msg) tea.Cmd {
+	svc, ok := m.ServiceMap[name]
+	if !ok {
+		return nil
+	}
+	newModel, cmd := svc.Update(msg)
+	if updatedSvc, ok := newModel.(services.Service); ok {
+		m.ServiceMap[name] = updatedSvc
+		if m.CurrentSvc == svc {
+			m.CurrentSvc = updatedSvc
+		}
+	}
+	return cmd
+}
+
+// currentRoute describes where the user is, for the navigation history
+func (m MainModel) currentRoute() core.Route {
+	if m.ViewMode != ViewService || m.CurrentSvc == nil {
+		return core.Route{View: core.ViewHome}
+	}
+	r := core.Route{View: core.ViewServiceList, Service: m.ActiveService, Project: m.AuthState.ProjectID}
+	if nav, ok := m.CurrentSvc.(services.Navigable); ok {
+		r.Location = nav.Location()
+	}
+	return r
+}
+
+// pushHistory records the current place before jumping to another
+func (m *MainModel) pushHistory() {
+	m.Navigation.Push(m.currentRoute())
+	m.syncTrail()
+}
+
+func (m *MainModel) syncTrail() {
+	labels := make([]string, len(m.Navigation.History))
+	for i, r := range m.Navigation.History {
+		labels[i] = r.Label()
+	}
+	components.SetBreadcrumbTrail(labels)
+}
+
+// goBack returns to the previous place in the navigation history
+func (m *MainModel) goBack() tea.Cmd {
+	to, ok := m.Navigation.Back(m.currentRoute())
+	if !ok {
+		m.StatusBar.Message = "No previous place"
+		return nil
+	}
+	m.syncTrail()
+	return m.goTo(to)
+}
+
+// goForward undoes goBack
+func (m *MainModel) goForward() tea.Cmd {
+	to, ok := m.Navigation.Forward(m.currentRoute())
+	if !ok {
+		m.StatusBar.Message = "No next place"
+		return nil
+	}
+	m.syncTrail()
+	return m.goTo(to)
+}
+
+// goTo shows a place from the navigation history as it was left: same
+// project, view, filter and selected resource
+func (m *MainModel) goTo(r core.Route) tea.Cmd {
+	m.ShowHelp = false
+	if r.View == core.ViewHome {
+		m.ViewMode = ViewHome
+		m.Sidebar.Active = false
+		m.HomeMenu.IsFocused = true
+		m.setFocus(FocusSidebar)
+		return nil
+	}
+
+	if r.Project != "" && r.Project != m.AuthState.ProjectID {
+		m.switchProject(r.Project)
+	}
+	svc, cmd := m.enterService(r.Service)
+	if svc == nil {
+		return cmd
+	}
+	var cmds []tea.Cmd
+	if nav, ok := svc.(services.Navigable); ok {
+		cmds = append(cmds, nav.Restore(r.Location))
+	}
+	cmds = append(cmds, svc.Refresh())
+	m.setFocus(FocusMain)
+	m.Sidebar.Active = false
+	return tea.Batch(cmds...)
+}
+
+// switchProject points every service at another project
+func (m *MainModel) switchProject(projectID string) {
+	m.AuthState.ProjectID = projectID
+
+	// Re-initialize all services with new project using registry
+	if m.ServiceRegistry != nil {
+		m.ServiceRegistry.ReinitializeAll(context.Background(), projectID, m.ServiceMap)
+	}
+
+	m.StatusBar.Message = "Switched to project: " + projectID
+	m.StatusBar.StaleSince = time.Time{}
+}
+
+// queueExitExport renders the dialog's export for printing after exit
+func (m *MainModel) queueExitExport() core.ToastMsg {
+	var b strings.Builder
+	if err := m.Export.Export.Write(&b, m.Export.Format, m.Export.Full); err != nil {
+		return core.ToastMsg{Message: "Export failed: " + err.Error(), Type: core.ToastError}
+	}
+	m.exitOutput = append(m.exitOutput, b.String())
+	return core.ToastMsg{
+		Message: fmt.Sprintf("%d row(s) will be printed when tgcp exits", len(m.Export.Export.Rows)),
+		Type:    core.ToastSuccess,
+	}
+}
+
+// ExitOutput returns the exports queued for printing after the program exits
+func (m MainModel) ExitOutput() string {
+	return strings.Join(m.exitOutput, "\n")
+}
+
+// profileSwitchedMsg carries the result of re-authenticating for a profile
+type profileSwitchedMsg struct {
+	cfg  *config.Config
+	auth core.AuthState
+}
+
+// switchProfileCmd applies a profile and re-authenticates in the background,
+// since verifying an impersonation target makes a network call.
+func (m *MainModel) switchProfileCmd(name string) tea.Cmd {
+	cfg, err := m.Config.WithProfile(name)
+	if err != nil {
+		return func() tea.Msg { return core.StatusMsg{Message: err.Error(), IsError: true} }
+	}
+	m.StatusBar.Message = "Switching to profile " + name + "..."
+
+	project := cfg.Project
+	if project == "" {
+		project = m.AuthState.ProjectID
+	}
+	return func() tea.Msg {
+		if core.IsReplaying() {
+			return profileSwitchedMsg{cfg: cfg, auth: core.ReplayAuthState(project)}
+		}
+		core.SetImpersonation(cfg.ImpersonateServiceAccount)
+		return profileSwitchedMsg{cfg: cfg, auth: core.Authenticate(context.Background(), project)}
+	}
+}
+
+// refreshModeMessage confirms a switch of refresh mode
+func refreshModeMessage(service string, mode core.RefreshMode, interval time.Duration) string {
+	switch {
+	case mode == core.RefreshWatch:
+		return fmt.Sprintf("Watching %s: refreshing every %s", service, core.FormatInterval(interval))
+	case mode == core.RefreshPaused:
+		return "Auto-refresh paused for " + service
+	case interval <= 0:
+		return "Auto-refresh is off for " + service + " (ui.refresh_interval)"
+	}
+	return fmt.Sprintf("Auto-refreshing %s every %s", service, core.FormatInterval(interval))
+}
+
+// profileDescription summarizes a profile for the switcher palette
+func profileDescription(p config.ProfileConfig, active bool) string {
+	parts := []string{}
+	if p.Project != "" {
+		parts = append(parts, "project "+p.Project)
+	}
+	if p.ImpersonateServiceAccount != "" {
+		parts = append(parts, "as "+p.ImpersonateServiceAccount)
+	}
+	if p.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	if active {
+		parts = append(parts, "(active)")
+	}
+	return strings.Join(parts, ", ")
+}
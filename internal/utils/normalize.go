@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Normalize turns models into Fields, maps, slices and scalars, the one
+// schema every serialization of them shares ("tgcp get -o json/yaml", table
+// exports): fields are keyed by their Go name in snake_case (MachineType
+// becomes machine_type) whatever the struct tags, times are RFC 3339 in UTC
+// and durations read like "1m30s". Map keys, e.g. labels, are kept as they
+// are. Nil pointers and zero times become nil.
+func Normalize(v interface{}) interface{} {
+	return normalize(reflect.ValueOf(v))
+}
+
+func normalize(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch x := v.Interface().(type) {
+	case time.Time:
+		if x.IsZero() {
+			return nil
+		}
+		return x.UTC().Format(time.RFC3339)
+	case time.Duration:
+		return x.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return normalize(v.Elem())
+	case reflect.Struct:
+		fields := Fields{}
+		for _, f := range structFields(v.Type()) {
+			fields = append(fields, Field{f.key, normalize(v.FieldByIndex(f.index))})
+		}
+		return fields
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = normalize(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = normalize(v.Index(i))
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// NormalizedKeys returns the keys Normalize gives a struct type, or nil for
+// other types. Pointers are followed.
+func NormalizedKeys(t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var keys []string
+	for _, f := range structFields(t) {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+// structField is an exported field of a struct, with those of embedded
+// structs inlined
+type structField struct {
+	key   string
+	index []int
+}
+
+func structFields(t reflect.Type) []structField {
+	var out []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			// Promoted fields count even when the embedded type is unexported
+			for _, inner := range structFields(f.Type) {
+				out = append(out, structField{inner.key, append([]int{i}, inner.index...)})
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		out = append(out, structField{SnakeCase(f.Name), []int{i}})
+	}
+	return out
+}
+
+// Field is one key of a normalized struct
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Fields is a normalized struct. Unlike a map it keeps the declaration
+// order, so a model's name comes out first in JSON and YAML alike.
+type Fields []Field
+
+// MarshalJSON implements json.Marshaler
+func (fs Fields) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (fs Fields) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range fs {
+		var key, value yaml.Node
+		if err := key.Encode(f.Key); err != nil {
+			return nil, err
+		}
+		if err := value.Encode(f.Value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &key, &value)
+	}
+	return node, nil
+}
+
+// SnakeCase converts a Go name to snake_case, keeping initialisms together:
+// InternalIP becomes internal_ip, IPAddress ip_address
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			endOfInitialism := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || endOfInitialism {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":        "name",
+		"ID":          "id",
+		"MachineType": "machine_type",
+		"InternalIP":  "internal_ip",
+		"IPAddress":   "ip_address",
+		"SizeGb":      "size_gb",
+	}
+	for in, want := range tests {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+type base struct {
+	Project string
+}
+
+type instance struct {
+	base
+	Name        string
+	MachineType string `json:"machineType"`
+	Labels      map[string]string
+	Created     time.Time
+	Started     time.Time
+	Uptime      time.Duration
+	internal    string
+}
+
+func TestNormalize(t *testing.T) {
+	in := []instance{{
+		base:        base{Project: "acme"},
+		Name:        "web-1",
+		MachineType: "e2-small",
+		Labels:      map[string]string{"TeamName": "web"},
+		Created:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)),
+		Uptime:      90 * time.Second,
+	}}
+
+	data, err := json.Marshal(Normalize(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"project":"acme","name":"web-1","machine_type":"e2-small","labels":{"TeamName":"web"},` +
+		`"created":"2026-01-02T02:04:05Z","started":null,"uptime":"1m30s"}]`
+	if string(data) != want {
+		t.Errorf("Normalize =\n%s\nwant\n%s", data, want)
+	}
+
+	keys := NormalizedKeys(reflect.TypeOf(&instance{}))
+	if len(keys) != 7 || keys[0] != "project" || keys[2] != "machine_type" {
+		t.Errorf("NormalizedKeys = %v", keys)
+	}
+}